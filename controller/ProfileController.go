@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"os"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,20 +10,24 @@ import (
 )
 
 type PublicProfileResponse struct {
-	ID             uint        `json:"id"`
-	Name           string      `json:"name"`
-	ProfilePicture string      `json:"profile_picture"`
-	CVFile         string      `json:"cv_file"`
-	AboutMe        string      `json:"about_me"`
-	Location       string      `json:"location"`
-	Interests      string      `json:"interests"`
-	Academic       string      `json:"academic"`
-	WebsiteURL     string      `json:"website_url"`
-	GithubURL      string      `json:"github_url"`
-	LinkedInURL    string      `json:"linkedin_url"`
-	InstagramURL   string      `json:"instagram_url"`
-	PortofolioURL  string      `json:"portfolio_url"`
-	Skills         interface{} `json:"skills"`
+	ID              uint              `json:"id"`
+	Name            string            `json:"name"`
+	ProfilePicture  string            `json:"profile_picture"`
+	PictureVariants map[string]string `json:"profile_picture_variants,omitempty"`
+	CVFile          string            `json:"cv_file"`
+	AboutMe         string            `json:"about_me"`
+	Location        string            `json:"location"`
+	City            string            `json:"city"`
+	Region          string            `json:"region"`
+	LocationMode    string            `json:"location_mode"`
+	Interests       string            `json:"interests"`
+	Academic        string            `json:"academic"`
+	WebsiteURL      string            `json:"website_url"`
+	GithubURL       string            `json:"github_url"`
+	LinkedInURL     string            `json:"linkedin_url"`
+	InstagramURL    string            `json:"instagram_url"`
+	PortofolioURL   string            `json:"portfolio_url"`
+	Skills          interface{}       `json:"skills"`
 }
 
 type ProfileController struct {
@@ -46,17 +49,23 @@ func (ctrl *ProfileController) GetUserProfile(c *fiber.Ctx) error {
 	}
 
 	responseData := fiber.Map{
-		"id":                  user.ID,
-		"name":                user.Name,
-		"email":               user.Email,
-		"phone":               user.Phone,
-		"colaboration_status": user.StatusCollaboration,
-		"profile_picture":     helper.GetUrlFile(profile.ProfilePicture),
-		"cv_file":             helper.GetUrlFile(profile.CVFile),
-		"skills":              user.UserSkills,
+		"id":                       user.ID,
+		"name":                     user.Name,
+		"email":                    user.Email,
+		"phone":                    user.Phone,
+		"colaboration_status":      user.StatusCollaboration,
+		"profile_picture":          helper.GetUrlFile(profile.ProfilePicture),
+		"profile_picture_variants": helper.GetImageVariantURLs(profile.ProfilePicture),
+		"cv_file":                  helper.GetCVUrl(user.ID, profile.CVFile),
+		"skills":                   user.UserSkills,
 		"profile": fiber.Map{
 			"about_me":      profile.AboutMe,
 			"location":      profile.Location,
+			"city":          profile.City,
+			"region":        profile.Region,
+			"latitude":      profile.Latitude,
+			"longitude":     profile.Longitude,
+			"location_mode": profile.LocationMode,
 			"interests":     profile.Interests,
 			"academic":      profile.Academic,
 			"website_url":   profile.WebsiteURL,
@@ -95,6 +104,12 @@ func (ctrl *ProfileController) UpdateProfile(c *fiber.Ctx) error {
 	location := c.FormValue("location")
 	dto.Location = &location
 
+	geoLocation, err := parseGeoLocationForm(c)
+	if err != nil {
+		return err
+	}
+	dto.GeoLocation = &geoLocation
+
 	interests := c.FormValue("interests")
 	dto.Interests = &interests
 
@@ -129,12 +144,13 @@ func (ctrl *ProfileController) UpdateProfile(c *fiber.Ctx) error {
 	}
 
 	return helper.Message200(c, fiber.Map{
-		"id":              user.ID,
-		"name":            user.Name,
-		"email":           user.Email,
-		"phone":           user.Phone,
-		"profile_picture": helper.GetUrlFile(profile.ProfilePicture),
-		"cv_file":         helper.GetUrlFile(profile.CVFile),
+		"id":                       user.ID,
+		"name":                     user.Name,
+		"email":                    user.Email,
+		"phone":                    user.Phone,
+		"profile_picture":          helper.GetUrlFile(profile.ProfilePicture),
+		"profile_picture_variants": helper.GetImageVariantURLs(profile.ProfilePicture),
+		"cv_file":                  helper.GetCVUrl(user.ID, profile.CVFile),
 
 		"profile": profile,
 	}, "Profile updated successfully")
@@ -156,57 +172,29 @@ func (ctrl *ProfileController) GetPublicUserProfile(c *fiber.Ctx) error {
 	}
 
 	publicResponse := PublicProfileResponse{
-		ID:             user.ID,
-		Name:           user.Name,
-		ProfilePicture: helper.GetUrlFile(profile.ProfilePicture),
-		CVFile:         helper.GetUrlFile(profile.CVFile),
-		AboutMe:        profile.AboutMe,
-		Location:       profile.Location,
-		Interests:      profile.Interests,
-		Academic:       profile.Academic,
-		WebsiteURL:     profile.WebsiteURL,
-		GithubURL:      profile.GithubURL,
-		LinkedInURL:    profile.LinkedInURL,
-		InstagramURL:   profile.InstagramURL,
-		PortofolioURL:  profile.PortfolioURL,
-		Skills:         user.UserSkills,
+		ID:              user.ID,
+		Name:            user.Name,
+		ProfilePicture:  helper.GetUrlFile(profile.ProfilePicture),
+		PictureVariants: helper.GetImageVariantURLs(profile.ProfilePicture),
+		CVFile:          helper.GetCVUrl(user.ID, profile.CVFile),
+		AboutMe:         profile.AboutMe,
+		Location:        profile.Location,
+		City:            profile.City,
+		Region:          profile.Region,
+		LocationMode:    profile.LocationMode,
+		Interests:       profile.Interests,
+		Academic:        profile.Academic,
+		WebsiteURL:      profile.WebsiteURL,
+		GithubURL:       profile.GithubURL,
+		LinkedInURL:     profile.LinkedInURL,
+		InstagramURL:    profile.InstagramURL,
+		PortofolioURL:   profile.PortfolioURL,
+		Skills:          user.UserSkills,
 	}
 
 	return helper.Message200(c, publicResponse, "Profile retrieved successfully")
 }
 
-func (ctrl *ProfileController) GetCVFile(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	userId, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		return helper.Message400("Invalid user ID")
-	}
-
-	filePath, err := ctrl.ProfileService.GetCVFilePath(uint(userId))
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return helper.Message404("Profile not found for this user")
-		}
-		return helper.Message500("Could not retrieve profile")
-	}
-
-	if filePath == "" {
-		return helper.Message404("User has not uploaded a CV")
-	}
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return helper.Message404("CV file not found on server")
-	}
-
-	action := c.Query("action")
-
-	if action == "download" {
-		return c.Download(filePath)
-	}
-
-	return c.SendFile(filePath, false)
-}
-
 func (ctrl *ProfileController) DeleteProfilePicture(c *fiber.Ctx) error {
 	userId := c.Locals("user_id").(uint)
 
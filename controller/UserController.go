@@ -29,7 +29,12 @@ func GetReadyUsers(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	perPage, _ := strconv.Atoi(c.Query("per_page", "20"))
 
-	users, paginationData, err := service.GetReadyUsersPaginatedWithTransform(page, perPage)
+	geo, err := parseGeoFilter(c, c.Locals("user_id").(uint))
+	if err != nil {
+		return err
+	}
+
+	users, paginationData, err := service.GetReadyUsersPaginatedWithTransform(page, perPage, geo)
 	if err != nil {
 		return helper.Message500("Failed to retrieve ready users")
 	}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type CalendarController struct {
+	calendarService *service.CalendarService
+}
+
+func NewCalendarController(cs *service.CalendarService) *CalendarController {
+	return &CalendarController{calendarService: cs}
+}
+
+// GetFeedLink returns the current user's calendar feed URL
+func (ctrl *CalendarController) GetFeedLink(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	link, err := ctrl.calendarService.GetFeedLink(userID)
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, link, "Calendar feed retrieved successfully")
+}
+
+// ResetFeedLink replaces the current user's calendar feed URL, revoking the old one
+func (ctrl *CalendarController) ResetFeedLink(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	link, err := ctrl.calendarService.ResetFeedLink(userID)
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, link, "Calendar feed reset successfully")
+}
+
+// GetUserCalendar serves a user's calendar feed. The token in the URL is the authorization,
+// since calendar apps subscribe without logging in.
+func (ctrl *CalendarController) GetUserCalendar(c *fiber.Ctx) error {
+	data, err := ctrl.calendarService.GetUserCalendar(c.Params("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrCalendarFeedNotFound) {
+			return helper.Message404(err.Error())
+		}
+		return helper.Message500(err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, helper.ICSContentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.Send(data)
+}
+
+// GetProjectCalendar downloads the deadline, dates and milestones of a project as an .ics file
+func (ctrl *CalendarController) GetProjectCalendar(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+	userID, _ := c.Locals("user_id").(uint)
+
+	data, project, err := ctrl.calendarService.GetProjectCalendar(uint(projectID), userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	c.Attachment(fmt.Sprintf("project-%d.ics", project.ID))
+	c.Set(fiber.HeaderContentType, helper.ICSContentType)
+	return c.Send(data)
+}
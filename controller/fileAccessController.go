@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"errors"
+	"path"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type FileAccessController struct {
+	fileAccessService *service.FileAccessService
+}
+
+func NewFileAccessController(fas *service.FileAccessService) *FileAccessController {
+	return &FileAccessController{fileAccessService: fas}
+}
+
+// GetCVLink issues a short-lived signed link to a user's CV for the user themselves
+// or for someone reviewing their application
+func (ctrl *FileAccessController) GetCVLink(c *fiber.Ctx) error {
+	viewerID := c.Locals("user_id").(uint)
+	ownerID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid user ID")
+	}
+
+	link, err := ctrl.fileAccessService.IssueCVLink(uint(ownerID), viewerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCVNotUploaded):
+			return helper.Message404(err.Error())
+		case errors.Is(err, service.ErrCVAccessDenied):
+			return helper.Message403(err.Error())
+		}
+		return helper.Message500(err.Error())
+	}
+
+	if c.Query("action") == "download" {
+		link.URL += "&download=true"
+	}
+	return helper.Message200(c, link, "CV link created successfully")
+}
+
+// GetPrivateFile serves a private file through a signed link. The signature is the
+// authorization, so this route works without a login, e.g. when opened in a new tab.
+func (ctrl *FileAccessController) GetPrivateFile(c *fiber.Ctx) error {
+	file, err := helper.VerifySignedFile(c.Query)
+	if err != nil {
+		return helper.Message403(err.Error())
+	}
+
+	data, err := ctrl.fileAccessService.OpenSignedFile(file, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPrivateFileGone):
+			return helper.Message404(err.Error())
+		case errors.Is(err, service.ErrAttachmentAccessDenied):
+			return helper.Message403(err.Error())
+		}
+		return helper.Message500(err.Error())
+	}
+
+	if c.QueryBool("download") {
+		c.Attachment(path.Base(file.Path))
+	}
+	c.Set(fiber.HeaderContentType, helper.FileContentType(file.Path))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Send(data)
+}
+
+// GetCVViews lists who downloaded the current user's CV
+func (ctrl *FileAccessController) GetCVViews(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	var views []*model.FileAccessLog
+	paginationData, err := helper.Paginate(ctrl.fileAccessService.GetCVViewsQuery(userID), c, &views)
+	if err != nil {
+		return helper.Message500("Failed to retrieve CV views")
+	}
+	service.DecorateFileAccessLogs(views)
+
+	return helper.Message200(c, fiber.Map{
+		"views":      views,
+		"pagination": paginationData,
+	}, "CV views retrieved successfully")
+}
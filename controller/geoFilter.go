@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+func parseOptionalFloat(value, name string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, helper.Message400("Invalid " + name)
+	}
+	return &number, nil
+}
+
+// parseGeoLocationForm reads the typed coordinates and location mode sent with a location
+func parseGeoLocationForm(c *fiber.Ctx) (service.GeoLocationInput, error) {
+	input := service.GeoLocationInput{Mode: c.FormValue("location_mode")}
+	var err error
+	if input.Latitude, err = parseOptionalFloat(c.FormValue("latitude"), "latitude"); err != nil {
+		return input, err
+	}
+	if input.Longitude, err = parseOptionalFloat(c.FormValue("longitude"), "longitude"); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// parseGeoFilter reads the radius, city, region and mode filters of listings. The centre of a
+// radius search is either lat and lng, or near: a place name, or "me" for the signed-in user's
+// own location (userID is 0 on public routes).
+func parseGeoFilter(c *fiber.Ctx, userID uint) (service.GeoFilter, error) {
+	filter := service.GeoFilter{City: c.Query("city"), Region: c.Query("region")}
+
+	if modes := c.Query("mode"); modes != "" {
+		for _, mode := range strings.Split(modes, ",") {
+			normalized, err := service.NormalizeLocationMode(mode)
+			if err != nil {
+				return filter, helper.Message400(err.Error())
+			}
+			filter.Modes = append(filter.Modes, normalized)
+		}
+	}
+
+	var err error
+	if filter.Latitude, err = parseOptionalFloat(c.Query("lat"), "lat"); err != nil {
+		return filter, err
+	}
+	if filter.Longitude, err = parseOptionalFloat(c.Query("lng"), "lng"); err != nil {
+		return filter, err
+	}
+	if (filter.Latitude == nil) != (filter.Longitude == nil) {
+		return filter, helper.Message400("lat and lng must be sent together")
+	}
+	if filter.HasCenter() && !helper.ValidCoordinates(*filter.Latitude, *filter.Longitude) {
+		return filter, helper.Message400("Invalid lat or lng")
+	}
+
+	if near := strings.TrimSpace(c.Query("near")); near != "" && !filter.HasCenter() {
+		if strings.EqualFold(near, "me") {
+			if userID == 0 {
+				return filter, helper.Message400("near=me is not available here; send lat and lng instead")
+			}
+			location, err := service.GetUserGeoLocation(userID)
+			if err != nil {
+				return filter, helper.Message500(err.Error())
+			}
+			if location == nil || !location.HasCoordinates() {
+				return filter, helper.Message400("Set a location on your profile to search near you")
+			}
+			filter.Latitude, filter.Longitude = location.Latitude, location.Longitude
+		} else {
+			place, err := helper.GetGeocoder().Geocode(near)
+			if err != nil {
+				return filter, helper.Message400("Unknown place: " + near)
+			}
+			filter.Latitude, filter.Longitude = &place.Latitude, &place.Longitude
+		}
+	}
+
+	radius, err := parseOptionalFloat(c.Query("radius_km"), "radius_km")
+	if err != nil {
+		return filter, err
+	}
+	if radius != nil {
+		if *radius <= 0 || *radius > service.MaxSearchRadiusKm {
+			return filter, helper.Message400("radius_km must be more than 0 and at most " + strconv.Itoa(service.MaxSearchRadiusKm))
+		}
+		filter.RadiusKm = *radius
+	}
+	return filter, nil
+}
@@ -2,6 +2,8 @@ package controller
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"synergazing.com/synergazing/helper"
@@ -16,25 +18,73 @@ func NewNotificationController(ns *service.NotificationService) *NotificationCon
 	return &NotificationController{notificationService: ns}
 }
 
-// GetNotifications retrieves paginated notifications for the authenticated user
+// parseNotificationFilter reads the type, project_id, is_read, from and to query parameters
+func parseNotificationFilter(c *fiber.Ctx) (service.NotificationFilter, error) {
+	var filter service.NotificationFilter
+
+	if types := c.Query("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types = append(filter.Types, t)
+			}
+		}
+	}
+
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			return filter, helper.Message400("Invalid project ID")
+		}
+		id := uint(projectID)
+		filter.ProjectID = &id
+	}
+
+	if isReadStr := c.Query("is_read"); isReadStr != "" {
+		isRead, err := strconv.ParseBool(isReadStr)
+		if err != nil {
+			return filter, helper.Message400("Invalid is_read value")
+		}
+		filter.IsRead = &isRead
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := helper.ParseDate(fromStr)
+		if err != nil {
+			return filter, helper.Message400("Invalid from date")
+		}
+		filter.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := helper.ParseDate(toStr)
+		if err != nil {
+			return filter, helper.Message400("Invalid to date")
+		}
+		// A date without a time includes that whole day
+		if _, err := time.Parse(time.RFC3339, toStr); err != nil {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+// GetNotifications retrieves filtered, cursor-paginated notifications for the authenticated user
 func (ctrl *NotificationController) GetNotifications(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 
-	// Get pagination parameters
-	limitStr := c.Query("limit", "20")
-	offsetStr := c.Query("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20
+	filter, err := parseNotificationFilter(c)
+	if err != nil {
+		return err
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
 	}
 
-	notifications, err := ctrl.notificationService.GetUserNotifications(userID, limit, offset)
+	notifications, nextCursor, err := ctrl.notificationService.GetUserNotifications(userID, filter, c.Query("cursor"), limit)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
@@ -42,7 +92,8 @@ func (ctrl *NotificationController) GetNotifications(c *fiber.Ctx) error {
 	return helper.Message200(c, fiber.Map{
 		"notifications": notifications,
 		"limit":         limit,
-		"offset":        offset,
+		"next_cursor":   nextCursor,
+		"has_more":      nextCursor != "",
 	}, "Notifications retrieved successfully")
 }
 
@@ -67,7 +118,7 @@ func (ctrl *NotificationController) GetUnreadNotifications(c *fiber.Ctx) error {
 	}, "Unread notifications retrieved successfully")
 }
 
-// GetUnreadCount retrieves the count of unread notifications
+// GetUnreadCount retrieves the count of unread notifications, in total and per type
 func (ctrl *NotificationController) GetUnreadCount(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 
@@ -76,8 +127,14 @@ func (ctrl *NotificationController) GetUnreadCount(c *fiber.Ctx) error {
 		return helper.Message400(err.Error())
 	}
 
+	byType, err := ctrl.notificationService.GetUnreadCountsByType(userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
 	return helper.Message200(c, fiber.Map{
 		"unread_count": count,
+		"by_type":      byType,
 	}, "Unread count retrieved successfully")
 }
 
@@ -97,6 +154,22 @@ func (ctrl *NotificationController) MarkAsRead(c *fiber.Ctx) error {
 	return helper.Message200(c, nil, "Notification marked as read")
 }
 
+// MarkAsUnread marks a specific notification as unread
+func (ctrl *NotificationController) MarkAsUnread(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	notificationID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid notification ID")
+	}
+
+	err = ctrl.notificationService.MarkAsUnread(uint(notificationID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Notification marked as unread")
+}
+
 // MarkAllAsRead marks all notifications as read for the authenticated user
 func (ctrl *NotificationController) MarkAllAsRead(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
@@ -125,12 +198,70 @@ func (ctrl *NotificationController) DeleteNotification(c *fiber.Ctx) error {
 	return helper.Message200(c, nil, "Notification deleted successfully")
 }
 
-// TestDeadlineNotifications manually triggers deadline notifications (for testing)
-func (ctrl *NotificationController) TestDeadlineNotifications(c *fiber.Ctx) error {
-	err := ctrl.notificationService.CheckAndNotifyApproachingDeadlines()
+// bulkNotificationRequest selects notifications for a bulk action by ID; without IDs the query filters apply
+type bulkNotificationRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+func parseBulkNotificationRequest(c *fiber.Ctx) ([]uint, service.NotificationFilter, error) {
+	var request bulkNotificationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return nil, service.NotificationFilter{}, helper.Message400("Invalid request body")
+		}
+	}
+
+	filter, err := parseNotificationFilter(c)
+	if err != nil {
+		return nil, filter, err
+	}
+
+	return request.IDs, filter, nil
+}
+
+// BulkMarkAsRead marks the selected notifications as read
+func (ctrl *NotificationController) BulkMarkAsRead(c *fiber.Ctx) error {
+	return ctrl.bulkSetReadState(c, true)
+}
+
+// BulkMarkAsUnread marks the selected notifications as unread
+func (ctrl *NotificationController) BulkMarkAsUnread(c *fiber.Ctx) error {
+	return ctrl.bulkSetReadState(c, false)
+}
+
+func (ctrl *NotificationController) bulkSetReadState(c *fiber.Ctx, isRead bool) error {
+	userID := c.Locals("user_id").(uint)
+
+	ids, filter, err := parseBulkNotificationRequest(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := ctrl.notificationService.BulkSetReadState(userID, ids, filter, isRead)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"updated": updated,
+	}, "Notifications updated successfully")
+}
+
+// BulkDelete deletes the selected notifications
+func (ctrl *NotificationController) BulkDelete(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	ids, filter, err := parseBulkNotificationRequest(c)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := ctrl.notificationService.BulkDelete(userID, ids, filter)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
 
-	return helper.Message200(c, nil, "Deadline notifications check completed")
+	return helper.Message200(c, fiber.Map{
+		"deleted": deleted,
+	}, "Notifications deleted successfully")
 }
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type OutboxController struct {
+	outboxService *service.OutboxService
+}
+
+func NewOutboxController(obs *service.OutboxService) *OutboxController {
+	return &OutboxController{outboxService: obs}
+}
+
+// GetMessages lists outbox messages, optionally filtered by status and topic
+func (ctrl *OutboxController) GetMessages(c *fiber.Ctx) error {
+	var messages []model.OutboxMessage
+	query := ctrl.outboxService.GetMessagesQuery(c.Query("status"), c.Query("topic"))
+
+	paginationData, err := helper.Paginate(query, c, &messages)
+	if err != nil {
+		return helper.Message500("Failed to retrieve outbox messages")
+	}
+
+	counts, err := ctrl.outboxService.GetStatusCounts()
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"messages":      messages,
+		"status_counts": counts,
+		"pagination":    paginationData,
+	}, "Outbox messages retrieved successfully")
+}
+
+// GetMessage retrieves a single outbox message
+func (ctrl *OutboxController) GetMessage(c *fiber.Ctx) error {
+	messageID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid message ID")
+	}
+
+	message, err := ctrl.outboxService.GetMessage(uint(messageID))
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, message, "Outbox message retrieved successfully")
+}
+
+// RetryMessage requeues a failed outbox message for immediate delivery
+func (ctrl *OutboxController) RetryMessage(c *fiber.Ctx) error {
+	messageID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid message ID")
+	}
+
+	message, err := ctrl.outboxService.RetryMessage(uint(messageID))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, message, "Outbox message queued for retry")
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectAccessController struct {
+	accessService *service.ProjectAccessService
+}
+
+func NewProjectAccessController(pas *service.ProjectAccessService) *ProjectAccessController {
+	return &ProjectAccessController{accessService: pas}
+}
+
+// GetAccess lists the owner and the users with access to a project
+func (ctrl *ProjectAccessController) GetAccess(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	access, err := ctrl.accessService.GetAccess(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, access, "Project access retrieved successfully")
+}
+
+// GetMyPermissions returns the permissions the authenticated user holds on a project
+func (ctrl *ProjectAccessController) GetMyPermissions(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	permissions, err := ctrl.accessService.GetMyPermissions(uint(projectID), userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{"permissions": permissions}, "Project permissions retrieved successfully")
+}
+
+// GrantAccess gives a user an access level on a project
+func (ctrl *ProjectAccessController) GrantAccess(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+	targetUserID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid user ID")
+	}
+
+	level := c.FormValue("level")
+	if level == "" {
+		return helper.Message400("Access level is required")
+	}
+
+	access, err := ctrl.accessService.GrantAccess(uint(projectID), userID, uint(targetUserID), level)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, access, "Project access granted successfully")
+}
+
+// RevokeAccess removes a user's access level on a project
+func (ctrl *ProjectAccessController) RevokeAccess(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+	targetUserID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid user ID")
+	}
+
+	if err := ctrl.accessService.RevokeAccess(uint(projectID), userID, uint(targetUserID)); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Project access revoked successfully")
+}
+
+// TransferOwnership makes another user the owner of a project
+func (ctrl *ProjectAccessController) TransferOwnership(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	newOwnerID, err := strconv.ParseUint(c.FormValue("user_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid user ID")
+	}
+
+	project, err := ctrl.accessService.TransferOwnership(uint(projectID), userID, uint(newOwnerID))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, project, "Project ownership transferred successfully")
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectActivityController struct {
+	activityService *service.ProjectActivityService
+}
+
+func NewProjectActivityController(pas *service.ProjectActivityService) *ProjectActivityController {
+	return &ProjectActivityController{activityService: pas}
+}
+
+// GetActivity returns the activity feed of a project, optionally filtered by comma-separated types
+func (ctrl *ProjectActivityController) GetActivity(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	var types []string
+	if typeStr := c.Query("type"); typeStr != "" {
+		types = strings.Split(typeStr, ",")
+	}
+
+	query, err := ctrl.activityService.GetActivityQuery(uint(projectID), userID, types)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	var activities []model.ProjectActivity
+	paginationData, err := helper.Paginate(query, c, &activities)
+	if err != nil {
+		return helper.Message500("Failed to retrieve project activity")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"activities": activities,
+		"pagination": paginationData,
+	}, "Project activity retrieved successfully")
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectAnalyticsController struct {
+	analyticsService *service.ProjectAnalyticsService
+}
+
+func NewProjectAnalyticsController(pas *service.ProjectAnalyticsService) *ProjectAnalyticsController {
+	return &ProjectAnalyticsController{analyticsService: pas}
+}
+
+// GetAnalytics returns the views, application funnel and daily series of a project
+// between the optional from and to dates
+func (ctrl *ProjectAnalyticsController) GetAnalytics(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	from, to, err := service.ParseAnalyticsRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	analytics, err := ctrl.analyticsService.GetAnalytics(uint(projectID), userID, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectPermissionDenied) {
+			return helper.Message403(err.Error())
+		}
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, analytics, "Project analytics retrieved successfully")
+}
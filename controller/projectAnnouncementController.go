@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"mime/multipart"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectAnnouncementController struct {
+	announcementService *service.ProjectAnnouncementService
+}
+
+func NewProjectAnnouncementController(pas *service.ProjectAnnouncementService) *ProjectAnnouncementController {
+	return &ProjectAnnouncementController{announcementService: pas}
+}
+
+func parseAnnouncementParams(c *fiber.Ctx) (uint, uint, error) {
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid project ID")
+	}
+	announcementID, err := strconv.ParseUint(c.Params("announcement_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid announcement ID")
+	}
+	return uint(projectID), uint(announcementID), nil
+}
+
+// CreateAnnouncement posts an announcement; files can be attached as "attachments"
+func (ctrl *ProjectAnnouncementController) CreateAnnouncement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		files = form.File["attachments"]
+	}
+
+	announcement, err := ctrl.announcementService.CreateAnnouncement(uint(projectID), userID,
+		c.FormValue("title"), c.FormValue("body"), c.FormValue("pinned") == "true", files)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, announcement, "Announcement posted successfully")
+}
+
+// GetAnnouncements lists the announcements of a project, pinned ones first
+func (ctrl *ProjectAnnouncementController) GetAnnouncements(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	query, err := ctrl.announcementService.GetAnnouncementsQuery(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	var announcements []*model.ProjectAnnouncement
+	paginationData, err := helper.Paginate(query, c, &announcements)
+	if err != nil {
+		return helper.Message500("Failed to retrieve announcements")
+	}
+	if err := ctrl.announcementService.DecorateAnnouncements(announcements, userID); err != nil {
+		return helper.Message500("Failed to retrieve announcements")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"announcements": announcements,
+		"pagination":    paginationData,
+	}, "Announcements retrieved successfully")
+}
+
+// GetAnnouncement returns an announcement with its comments
+func (ctrl *ProjectAnnouncementController) GetAnnouncement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	announcement, err := ctrl.announcementService.GetAnnouncement(projectID, announcementID, userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, announcement, "Announcement retrieved successfully")
+}
+
+// UpdateAnnouncement edits the title or body of an announcement
+func (ctrl *ProjectAnnouncementController) UpdateAnnouncement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	announcement, err := ctrl.announcementService.UpdateAnnouncement(projectID, announcementID, userID, c.FormValue("title"), c.FormValue("body"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, announcement, "Announcement updated successfully")
+}
+
+// PinAnnouncement pins an announcement, or unpins it with pinned=false
+func (ctrl *ProjectAnnouncementController) PinAnnouncement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	pinned := c.FormValue("pinned", "true") != "false"
+	announcement, err := ctrl.announcementService.PinAnnouncement(projectID, announcementID, userID, pinned)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	message := "Announcement pinned successfully"
+	if !pinned {
+		message = "Announcement unpinned successfully"
+	}
+	return helper.Message200(c, announcement, message)
+}
+
+// DeleteAnnouncement removes an announcement
+func (ctrl *ProjectAnnouncementController) DeleteAnnouncement(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.announcementService.DeleteAnnouncement(projectID, announcementID, userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Announcement deleted successfully")
+}
+
+// AddComment comments on an announcement
+func (ctrl *ProjectAnnouncementController) AddComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	comment, err := ctrl.announcementService.AddComment(projectID, announcementID, userID, c.FormValue("body"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, comment, "Comment added successfully")
+}
+
+// DeleteComment removes a comment from an announcement
+func (ctrl *ProjectAnnouncementController) DeleteComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := strconv.ParseUint(c.Params("comment_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid comment ID")
+	}
+
+	if err := ctrl.announcementService.DeleteComment(projectID, announcementID, uint(commentID), userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Comment deleted successfully")
+}
+
+// React adds a reaction to an announcement
+func (ctrl *ProjectAnnouncementController) React(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	announcement, err := ctrl.announcementService.React(projectID, announcementID, userID, c.FormValue("reaction"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, announcement, "Reaction added successfully")
+}
+
+// RemoveReaction takes back a reaction
+func (ctrl *ProjectAnnouncementController) RemoveReaction(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, announcementID, err := parseAnnouncementParams(c)
+	if err != nil {
+		return err
+	}
+
+	announcement, err := ctrl.announcementService.RemoveReaction(projectID, announcementID, userID, c.Params("reaction"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, announcement, "Reaction removed successfully")
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectChangeController struct {
+	changeService *service.ProjectChangeService
+}
+
+func NewProjectChangeController(pcs *service.ProjectChangeService) *ProjectChangeController {
+	return &ProjectChangeController{changeService: pcs}
+}
+
+// GetChanges returns the field-level edit history of a project, optionally filtered by field
+func (ctrl *ProjectChangeController) GetChanges(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	query, err := ctrl.changeService.GetChangesQuery(uint(projectID), userID, c.Query("field"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	var changes []model.ProjectChange
+	paginationData, err := helper.Paginate(query, c, &changes)
+	if err != nil {
+		return helper.Message500("Failed to retrieve project changes")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"changes":    changes,
+		"pagination": paginationData,
+	}, "Project changes retrieved successfully")
+}
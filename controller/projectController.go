@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +22,84 @@ func NewProjectController(ps *service.ProjectService) *ProjectController {
 	return &ProjectController{projectService: ps}
 }
 
+func parseOptionalInt64(value, name string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, helper.Message400("Invalid " + name)
+	}
+	return &number, nil
+}
+
+func parseOptionalInt(value, name string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, helper.Message400("Invalid " + name)
+	}
+	return &number, nil
+}
+
+func parseOptionalBool(value, name string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, helper.Message400("Invalid " + name)
+	}
+	return &flag, nil
+}
+
+// parseProjectTermsForm reads the typed budget and duration fields of stage 2
+func parseProjectTermsForm(c *fiber.Ctx) (service.ProjectTermsInput, error) {
+	var terms service.ProjectTermsInput
+	var err error
+	if terms.BudgetMin, err = parseOptionalInt64(c.FormValue("budget_min"), "budget_min"); err != nil {
+		return terms, err
+	}
+	if terms.BudgetMax, err = parseOptionalInt64(c.FormValue("budget_max"), "budget_max"); err != nil {
+		return terms, err
+	}
+	if terms.IsPaid, err = parseOptionalBool(c.FormValue("is_paid"), "is_paid"); err != nil {
+		return terms, err
+	}
+	if terms.DurationDays, err = parseOptionalInt(c.FormValue("duration_days"), "duration_days"); err != nil {
+		return terms, err
+	}
+	terms.BudgetCurrency = c.FormValue("budget_currency")
+	return terms, nil
+}
+
+// parseProjectTermsFilter reads the budget, pay, duration and hours filters of project listings
+func parseProjectTermsFilter(c *fiber.Ctx) (service.ProjectTermsFilter, error) {
+	filter := service.ProjectTermsFilter{Currency: c.Query("currency")}
+	var err error
+	if filter.BudgetMin, err = parseOptionalInt64(c.Query("budget_min"), "budget_min"); err != nil {
+		return filter, err
+	}
+	if filter.BudgetMax, err = parseOptionalInt64(c.Query("budget_max"), "budget_max"); err != nil {
+		return filter, err
+	}
+	if filter.Paid, err = parseOptionalBool(c.Query("paid"), "paid"); err != nil {
+		return filter, err
+	}
+	if filter.MinDurationDays, err = parseOptionalInt(c.Query("min_duration_days"), "min_duration_days"); err != nil {
+		return filter, err
+	}
+	if filter.MaxDurationDays, err = parseOptionalInt(c.Query("max_duration_days"), "max_duration_days"); err != nil {
+		return filter, err
+	}
+	if filter.MaxHoursPerWeek, err = parseOptionalInt(c.Query("max_hours_per_week"), "max_hours_per_week"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 func (ctrl *ProjectController) CreateStage1(c *fiber.Ctx) error {
 	creatorID := c.Locals("user_id").(uint)
 	title := c.FormValue("title")
@@ -57,7 +137,16 @@ func (ctrl *ProjectController) UpdateStage2(c *fiber.Ctx) error {
 	details.Budget = c.FormValue("budget")
 	details.RegistrationDeadline, _ = time.Parse(time.RFC3339, c.FormValue("registration_deadline"))
 
-	project, err := ctrl.projectService.CreateProjectStage2(uint(projectID), userID, details)
+	terms, err := parseProjectTermsForm(c)
+	if err != nil {
+		return err
+	}
+	location, err := parseGeoLocationForm(c)
+	if err != nil {
+		return err
+	}
+
+	project, err := ctrl.projectService.CreateProjectStage2(uint(projectID), userID, details, terms, location)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
@@ -70,10 +159,14 @@ func (ctrl *ProjectController) UpdateStage3(c *fiber.Ctx) error {
 	projectID, _ := strconv.ParseUint(c.Params("id"), 10, 32)
 
 	timeCommitment := c.FormValue("time_commitment")
+	hoursPerWeek, err := parseOptionalInt(c.FormValue("hours_per_week"), "hours_per_week")
+	if err != nil {
+		return err
+	}
 	skillNames, _ := helper.ParseStringSlice(c.FormValue("required_skills"))
 	conditions, _ := helper.ParseStringSlice(c.FormValue("conditions"))
 
-	project, err := ctrl.projectService.UpdateStage3(uint(projectID), userID, timeCommitment, skillNames, conditions)
+	project, err := ctrl.projectService.UpdateStage3(uint(projectID), userID, timeCommitment, hoursPerWeek, skillNames, conditions)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
@@ -126,12 +219,16 @@ func (ctrl *ProjectController) UpdateStage5(c *fiber.Ctx) error {
 		}
 	}
 
-	timelineRaw := c.FormValue("timeline")
-	var timelineData []service.TimelineDTO
+	// Milestones may be sent as "milestones" or, as before, "timeline"
+	timelineRaw := c.FormValue("milestones")
+	if timelineRaw == "" {
+		timelineRaw = c.FormValue("timeline")
+	}
+	var timelineData []service.MilestoneDTO
 
 	if timelineRaw != "" {
-		// Try to parse as JSON array of timeline objects
-		var timelineObjects []service.TimelineDTO
+		// Try to parse as JSON array of milestone objects
+		var timelineObjects []service.MilestoneDTO
 		if err := json.Unmarshal([]byte(timelineRaw), &timelineObjects); err == nil {
 			timelineData = timelineObjects
 		} else {
@@ -152,12 +249,9 @@ func (ctrl *ProjectController) UpdateStage5(c *fiber.Ctx) error {
 				}
 				timelineNames = cleanTimelines
 			}
-			// Convert string array to TimelineDTO array with default status
+			// Names alone keep the status of existing milestones; new ones start as not started
 			for _, name := range timelineNames {
-				timelineData = append(timelineData, service.TimelineDTO{
-					Name:   name,
-					Status: helper.GetDefaultTimelineStatus(),
-				})
+				timelineData = append(timelineData, service.MilestoneDTO{Name: name})
 			}
 		}
 	}
@@ -188,9 +282,98 @@ func (ctrl *ProjectController) UpdateStage5(c *fiber.Ctx) error {
 		return helper.Message400(err.Error())
 	}
 
+	if response, ok := project.(service.ProjectResponseForMarshal); ok && response.Status == model.ProjectStatusDraft {
+		return helper.Message200(c, project, "Stage 5 completed. The project stays a draft until its checklist passes; see GET /api/projects/"+c.Params("id")+"/checklist.")
+	}
 	return helper.Message200(c, project, "Project successfully published!")
 }
 
+// GetChecklist returns, stage by stage, what a project is missing before it can be published
+func (ctrl *ProjectController) GetChecklist(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	checklist, err := ctrl.projectService.GetChecklist(uint(projectID), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectPermissionDenied) {
+			return helper.Message403(err.Error())
+		}
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, checklist, "Project checklist retrieved successfully")
+}
+
+// parseProjectDocument reads a JSON project document. Unknown fields are rejected, so a typo
+// doesn't silently leave a field out.
+func parseProjectDocument(c *fiber.Ctx) (service.ProjectDocument, error) {
+	var doc service.ProjectDocument
+	decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return doc, service.ValidationErrors{typeErr.Field: "must be of type " + typeErr.Type.String()}
+		}
+		return doc, helper.Message400("Invalid JSON format: " + err.Error())
+	}
+	return doc, nil
+}
+
+// projectDocumentError responds with the field errors of a rejected document, or a plain error
+func projectDocumentError(c *fiber.Ctx, err error) error {
+	var fieldErrors service.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		return helper.Message422(c, fieldErrors, "Project is invalid")
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return err
+	}
+	return helper.Message400(err.Error())
+}
+
+// CreateProject creates a whole project from one JSON document instead of the five stages
+func (ctrl *ProjectController) CreateProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	doc, err := parseProjectDocument(c)
+	if err != nil {
+		return projectDocumentError(c, err)
+	}
+
+	project, err := ctrl.projectService.CreateProjectDocument(userID, doc)
+	if err != nil {
+		return projectDocumentError(c, err)
+	}
+
+	return helper.Message201(c, project, "Project created successfully")
+}
+
+// PatchProject changes the fields of a project sent in a JSON document, leaving the rest alone
+func (ctrl *ProjectController) PatchProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	doc, err := parseProjectDocument(c)
+	if err != nil {
+		return projectDocumentError(c, err)
+	}
+
+	project, err := ctrl.projectService.UpdateProjectDocument(uint(projectID), userID, doc)
+	if err != nil {
+		return projectDocumentError(c, err)
+	}
+
+	return helper.Message200(c, project, "Project updated successfully")
+}
+
 // GetTimelineStatusOptions returns available timeline status options for frontend selection
 func (ctrl *ProjectController) GetTimelineStatusOptions(c *fiber.Ctx) error {
 	options := helper.GetTimelineStatusOptions()
@@ -246,7 +429,18 @@ func (ctrl *ProjectController) GetMyMemberProjects(c *fiber.Ctx) error {
 }
 
 func (ctrl *ProjectController) GetAllProjects(c *fiber.Ctx) error {
-	projects, err := ctrl.projectService.GetAllProjects()
+	filter, err := parseProjectTermsFilter(c)
+	if err != nil {
+		return err
+	}
+	// The listing is public, so there is no user for near=me
+	geo, err := parseGeoFilter(c, 0)
+	if err != nil {
+		return err
+	}
+	geo.ExcludeRemote = true
+
+	projects, err := ctrl.projectService.GetAllProjects(filter, geo)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
@@ -260,7 +454,11 @@ func (ctrl *ProjectController) GetProjectByID(c *fiber.Ctx) error {
 		return helper.Message400("Invalid project ID")
 	}
 
-	project, err := ctrl.projectService.GetProjectByID(uint(projectID))
+	// The route is public; signed-in viewers are identified by the optional auth middleware
+	userID, _ := c.Locals("user_id").(uint)
+	viewer := service.ProjectViewer{UserID: userID, IPAddress: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent)}
+
+	project, err := ctrl.projectService.GetProjectByID(uint(projectID), viewer)
 	if err != nil {
 		return helper.Message400(err.Error())
 	}
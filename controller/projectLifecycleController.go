@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectLifecycleController struct {
+	lifecycleService *service.ProjectLifecycleService
+}
+
+func NewProjectLifecycleController(pls *service.ProjectLifecycleService) *ProjectLifecycleController {
+	return &ProjectLifecycleController{lifecycleService: pls}
+}
+
+// GetStatusOptions returns every project status together with the statuses it can move to
+func (ctrl *ProjectLifecycleController) GetStatusOptions(c *fiber.Ctx) error {
+	transitions := make(fiber.Map, len(service.ProjectStatuses))
+	for _, status := range service.ProjectStatuses {
+		transitions[status] = service.AllowedTransitions(status)
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"statuses":    service.ProjectStatuses,
+		"transitions": transitions,
+	}, "Project status options retrieved successfully")
+}
+
+// ChangeStatus moves a project to a new lifecycle status
+func (ctrl *ProjectLifecycleController) ChangeStatus(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	status := c.FormValue("status")
+	if status == "" {
+		return helper.Message400("Status is required")
+	}
+
+	project, err := ctrl.lifecycleService.ChangeStatus(uint(projectID), userID, status, c.FormValue("reason"))
+	if err != nil {
+		var notPublishable *service.ProjectNotPublishableError
+		if errors.As(err, &notPublishable) {
+			return helper.Message422(c, notPublishable.Fields(), err.Error())
+		}
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"id":                  project.ID,
+		"status":              project.Status,
+		"allowed_transitions": service.AllowedTransitions(project.Status),
+	}, "Project status updated successfully")
+}
+
+// GetStatusHistory returns the lifecycle transitions of a project
+func (ctrl *ProjectLifecycleController) GetStatusHistory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	history, err := ctrl.lifecycleService.GetStatusHistory(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, history, "Project status history retrieved successfully")
+}
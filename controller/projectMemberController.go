@@ -104,7 +104,7 @@ func (ctrl *ProjectMemberController) GetUserInvitations(c *fiber.Ctx) error {
 	return helper.Message200(c, invitations, "User invitations retrieved successfully")
 }
 
-// ReviewApplication allows project creator to accept or reject an application
+// ReviewApplication allows an owner, manager or reviewer to accept or reject an application
 func (ctrl *ProjectMemberController) ReviewApplication(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 	applicationID, err := strconv.ParseUint(c.Params("application_id"), 10, 32)
@@ -154,7 +154,7 @@ func (ctrl *ProjectMemberController) WithdrawApplication(c *fiber.Ctx) error {
 	return helper.Message200(c, nil, "Application withdrawn successfully")
 }
 
-// RemoveMember allows project creator to remove a member from the project
+// RemoveMember allows an owner or manager to remove a member from the project
 func (ctrl *ProjectMemberController) RemoveMember(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
@@ -175,7 +175,7 @@ func (ctrl *ProjectMemberController) RemoveMember(c *fiber.Ctx) error {
 	return helper.Message200(c, nil, "Member removed successfully")
 }
 
-// InviteMember allows project creator to invite a user to join the project
+// InviteMember allows an owner or manager to invite a user to join the project
 func (ctrl *ProjectMemberController) InviteMember(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectMilestoneController struct {
+	milestoneService *service.ProjectMilestoneService
+}
+
+func NewProjectMilestoneController(pms *service.ProjectMilestoneService) *ProjectMilestoneController {
+	return &ProjectMilestoneController{milestoneService: pms}
+}
+
+func parseMilestoneParams(c *fiber.Ctx) (uint, uint, error) {
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid project ID")
+	}
+	milestoneID, err := strconv.ParseUint(c.Params("milestone_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid milestone ID")
+	}
+	return uint(projectID), uint(milestoneID), nil
+}
+
+// parseIDList reads a comma-separated list of IDs; "none" is an empty list
+func parseIDList(value, name string) ([]uint, error) {
+	ids := []uint{}
+	if value == "none" {
+		return ids, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, helper.Message400("Invalid " + name)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// parseMilestoneForm reads the milestone fields present in the request form.
+// "none" clears the start date, due date or dependencies.
+func parseMilestoneForm(c *fiber.Ctx) (service.MilestoneInput, error) {
+	var data service.MilestoneInput
+
+	if name := c.FormValue("name"); name != "" {
+		data.Name = &name
+	}
+	if description := c.FormValue("description"); description != "" {
+		data.Description = &description
+	}
+	if status := c.FormValue("status"); status != "" {
+		data.Status = &status
+	}
+	if percentStr := c.FormValue("percent_complete"); percentStr != "" {
+		percent, err := strconv.Atoi(percentStr)
+		if err != nil {
+			return data, helper.Message400("Invalid percent complete")
+		}
+		data.PercentComplete = &percent
+	}
+	if positionStr := c.FormValue("position"); positionStr != "" {
+		position, err := strconv.Atoi(positionStr)
+		if err != nil {
+			return data, helper.Message400("Invalid position")
+		}
+		data.Position = &position
+	}
+
+	if startDateStr := c.FormValue("start_date"); startDateStr == "none" {
+		data.ClearStartDate = true
+	} else if startDateStr != "" {
+		startDate, err := helper.ParseDate(startDateStr)
+		if err != nil {
+			return data, helper.Message400("Invalid start date")
+		}
+		data.StartDate = &startDate
+	}
+	if dueDateStr := c.FormValue("due_date"); dueDateStr == "none" {
+		data.ClearDueDate = true
+	} else if dueDateStr != "" {
+		dueDate, err := helper.ParseDate(dueDateStr)
+		if err != nil {
+			return data, helper.Message400("Invalid due date")
+		}
+		data.DueDate = &dueDate
+	}
+
+	if dependsOn := c.FormValue("depends_on"); dependsOn != "" {
+		ids, err := parseIDList(dependsOn, "dependency ID")
+		if err != nil {
+			return data, err
+		}
+		data.DependsOn = ids
+	}
+
+	return data, nil
+}
+
+// GetMilestones lists the milestones of a project with its overall progress
+func (ctrl *ProjectMilestoneController) GetMilestones(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	milestones, err := ctrl.milestoneService.GetMilestones(uint(projectID), userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, milestones, "Milestones retrieved successfully")
+}
+
+// CreateMilestone adds a milestone to the project timeline
+func (ctrl *ProjectMilestoneController) CreateMilestone(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	data, err := parseMilestoneForm(c)
+	if err != nil {
+		return err
+	}
+
+	milestone, err := ctrl.milestoneService.CreateMilestone(uint(projectID), userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, milestone, "Milestone created successfully")
+}
+
+// UpdateMilestone changes a milestone
+func (ctrl *ProjectMilestoneController) UpdateMilestone(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, milestoneID, err := parseMilestoneParams(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := parseMilestoneForm(c)
+	if err != nil {
+		return err
+	}
+
+	milestone, err := ctrl.milestoneService.UpdateMilestone(projectID, milestoneID, userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, milestone, "Milestone updated successfully")
+}
+
+// ReorderMilestones puts the milestones in the order given by the comma-separated "order" field
+func (ctrl *ProjectMilestoneController) ReorderMilestones(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	order, err := parseIDList(c.FormValue("order"), "milestone ID")
+	if err != nil {
+		return err
+	}
+
+	milestones, err := ctrl.milestoneService.ReorderMilestones(uint(projectID), userID, order)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, milestones, "Milestones reordered successfully")
+}
+
+// DeleteMilestone removes a milestone from the project timeline
+func (ctrl *ProjectMilestoneController) DeleteMilestone(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, milestoneID, err := parseMilestoneParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.milestoneService.DeleteMilestone(projectID, milestoneID, userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Milestone deleted successfully")
+}
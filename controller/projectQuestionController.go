@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectQuestionController struct {
+	questionService *service.ProjectQuestionService
+}
+
+func NewProjectQuestionController(pqs *service.ProjectQuestionService) *ProjectQuestionController {
+	return &ProjectQuestionController{questionService: pqs}
+}
+
+func parseQuestionParams(c *fiber.Ctx) (uint, uint, error) {
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid project ID")
+	}
+	questionID, err := strconv.ParseUint(c.Params("question_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid question ID")
+	}
+	return uint(projectID), uint(questionID), nil
+}
+
+// AskQuestion asks a question on a project's public page
+func (ctrl *ProjectQuestionController) AskQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	question, err := ctrl.questionService.AskQuestion(uint(projectID), userID, c.FormValue("question"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, question, "Question asked successfully")
+}
+
+// GetQuestions lists the questions of a project, optionally only FAQ (faq=true) or unanswered (unanswered=true) ones
+func (ctrl *ProjectQuestionController) GetQuestions(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	filter := service.QuestionFilter{
+		FAQOnly:        c.Query("faq") == "true",
+		UnansweredOnly: c.Query("unanswered") == "true",
+	}
+
+	query, err := ctrl.questionService.GetQuestionsQuery(uint(projectID), userID, filter)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	var questions []*model.ProjectQuestion
+	paginationData, err := helper.Paginate(query, c, &questions)
+	if err != nil {
+		return helper.Message500("Failed to retrieve questions")
+	}
+	for _, question := range questions {
+		question.AskerName = question.Asker.Name
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"questions":  questions,
+		"pagination": paginationData,
+	}, "Questions retrieved successfully")
+}
+
+// AnswerQuestion answers a question or changes its answer
+func (ctrl *ProjectQuestionController) AnswerQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, questionID, err := parseQuestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	question, err := ctrl.questionService.AnswerQuestion(projectID, questionID, userID, c.FormValue("answer"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, question, "Question answered successfully")
+}
+
+// SetFAQ marks a question as FAQ, or removes the mark with faq=false
+func (ctrl *ProjectQuestionController) SetFAQ(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, questionID, err := parseQuestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	question, err := ctrl.questionService.SetFAQ(projectID, questionID, userID, c.FormValue("faq", "true") != "false")
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, question, "Question updated successfully")
+}
+
+// SetHidden hides a question, or shows it again with hidden=false
+func (ctrl *ProjectQuestionController) SetHidden(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, questionID, err := parseQuestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	question, err := ctrl.questionService.SetHidden(projectID, questionID, userID, c.FormValue("hidden", "true") != "false")
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, question, "Question updated successfully")
+}
+
+// DeleteQuestion removes a question
+func (ctrl *ProjectQuestionController) DeleteQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, questionID, err := parseQuestionParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.questionService.DeleteQuestion(projectID, questionID, userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Question deleted successfully")
+}
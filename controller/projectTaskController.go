@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectTaskController struct {
+	taskService *service.ProjectTaskService
+}
+
+func NewProjectTaskController(pts *service.ProjectTaskService) *ProjectTaskController {
+	return &ProjectTaskController{taskService: pts}
+}
+
+func parseTaskParams(c *fiber.Ctx) (uint, uint, error) {
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid project ID")
+	}
+	taskID, err := strconv.ParseUint(c.Params("task_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid task ID")
+	}
+	return uint(projectID), uint(taskID), nil
+}
+
+func parseOptionalID(value, name string) (*uint, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if value == "none" {
+		zero := uint(0)
+		return &zero, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, helper.Message400("Invalid " + name)
+	}
+	result := uint(id)
+	return &result, nil
+}
+
+// parseTaskForm reads the task fields present in the request form.
+// "none" clears the assignee, milestone or due date.
+func parseTaskForm(c *fiber.Ctx) (service.TaskDTO, error) {
+	var data service.TaskDTO
+
+	if title := c.FormValue("title"); title != "" {
+		data.Title = &title
+	}
+	if description := c.FormValue("description"); description != "" {
+		data.Description = &description
+	}
+	if status := c.FormValue("status"); status != "" {
+		data.Status = &status
+	}
+	if positionStr := c.FormValue("position"); positionStr != "" {
+		position, err := strconv.Atoi(positionStr)
+		if err != nil {
+			return data, helper.Message400("Invalid position")
+		}
+		data.Position = &position
+	}
+	if labels := c.FormValue("labels"); labels != "" {
+		data.Labels = strings.Split(labels, ",")
+		if labels == "none" {
+			data.Labels = []string{}
+		}
+	}
+
+	var err error
+	if data.AssigneeID, err = parseOptionalID(c.FormValue("assignee_id"), "assignee ID"); err != nil {
+		return data, err
+	}
+	if data.MilestoneID, err = parseOptionalID(c.FormValue("milestone_id"), "milestone ID"); err != nil {
+		return data, err
+	}
+
+	if dueDateStr := c.FormValue("due_date"); dueDateStr == "none" {
+		data.ClearDueDate = true
+	} else if dueDateStr != "" {
+		dueDate, err := helper.ParseDate(dueDateStr)
+		if err != nil {
+			return data, helper.Message400("Invalid due date")
+		}
+		data.DueDate = &dueDate
+	}
+
+	return data, nil
+}
+
+func parseTaskFilter(c *fiber.Ctx) (service.TaskFilter, error) {
+	filter := service.TaskFilter{
+		Status: c.Query("status"),
+		Label:  c.Query("label"),
+	}
+
+	if assigneeStr := c.Query("assignee_id"); assigneeStr != "" {
+		assigneeID, err := strconv.ParseUint(assigneeStr, 10, 32)
+		if err != nil {
+			return filter, helper.Message400("Invalid assignee ID")
+		}
+		id := uint(assigneeID)
+		filter.AssigneeID = &id
+	}
+	if milestoneStr := c.Query("milestone_id"); milestoneStr != "" {
+		milestoneID, err := strconv.ParseUint(milestoneStr, 10, 32)
+		if err != nil {
+			return filter, helper.Message400("Invalid milestone ID")
+		}
+		id := uint(milestoneID)
+		filter.MilestoneID = &id
+	}
+
+	return filter, nil
+}
+
+// CreateTask adds a task to the project board
+func (ctrl *ProjectTaskController) CreateTask(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	data, err := parseTaskForm(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := ctrl.taskService.CreateTask(uint(projectID), userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, task, "Task created successfully")
+}
+
+// GetTasks lists the tasks of a project, filtered by status, assignee_id, milestone_id or label
+func (ctrl *ProjectTaskController) GetTasks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := ctrl.taskService.GetTasks(uint(projectID), userID, filter)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, tasks, "Tasks retrieved successfully")
+}
+
+// GetBoard returns the tasks of a project grouped by status column
+func (ctrl *ProjectTaskController) GetBoard(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		return err
+	}
+
+	board, err := ctrl.taskService.GetBoard(uint(projectID), userID, filter)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, board, "Task board retrieved successfully")
+}
+
+// GetTask retrieves a task with its comments
+func (ctrl *ProjectTaskController) GetTask(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, taskID, err := parseTaskParams(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := ctrl.taskService.GetTask(projectID, taskID, userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, task, "Task retrieved successfully")
+}
+
+// UpdateTask changes a task or moves it on the board
+func (ctrl *ProjectTaskController) UpdateTask(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, taskID, err := parseTaskParams(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := parseTaskForm(c)
+	if err != nil {
+		return err
+	}
+
+	task, err := ctrl.taskService.UpdateTask(projectID, taskID, userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, task, "Task updated successfully")
+}
+
+// DeleteTask removes a task
+func (ctrl *ProjectTaskController) DeleteTask(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, taskID, err := parseTaskParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.taskService.DeleteTask(projectID, taskID, userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Task deleted successfully")
+}
+
+// AddComment comments on a task
+func (ctrl *ProjectTaskController) AddComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, taskID, err := parseTaskParams(c)
+	if err != nil {
+		return err
+	}
+
+	comment, err := ctrl.taskService.AddComment(projectID, taskID, userID, c.FormValue("body"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, comment, "Comment added successfully")
+}
+
+// DeleteComment removes one of the user's comments
+func (ctrl *ProjectTaskController) DeleteComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, taskID, err := parseTaskParams(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := strconv.ParseUint(c.Params("comment_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid comment ID")
+	}
+
+	if err := ctrl.taskService.DeleteComment(projectID, taskID, uint(commentID), userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Comment deleted successfully")
+}
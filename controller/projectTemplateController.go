@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectTemplateController struct {
+	templateService *service.ProjectTemplateService
+}
+
+func NewProjectTemplateController(pts *service.ProjectTemplateService) *ProjectTemplateController {
+	return &ProjectTemplateController{templateService: pts}
+}
+
+func parseStartDate(c *fiber.Ctx) (time.Time, error) {
+	startDateStr := c.FormValue("start_date")
+	if startDateStr == "" {
+		return time.Time{}, helper.Message400("Start date is required")
+	}
+	startDate, err := helper.ParseDate(startDateStr)
+	if err != nil {
+		return time.Time{}, helper.Message400("Invalid start date")
+	}
+	return startDate, nil
+}
+
+// SaveAsTemplate saves a project as a reusable template
+func (ctrl *ProjectTemplateController) SaveAsTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	template, err := ctrl.templateService.CreateTemplateFromProject(uint(projectID), userID, c.FormValue("name"), c.FormValue("description"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, template, "Project template created successfully")
+}
+
+// DuplicateProject copies a project into a new draft with shifted dates
+func (ctrl *ProjectTemplateController) DuplicateProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	startDate, err := parseStartDate(c)
+	if err != nil {
+		return err
+	}
+
+	project, err := ctrl.templateService.DuplicateProject(uint(projectID), userID, c.FormValue("title"), startDate)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, project, "Project duplicated successfully")
+}
+
+// GetTemplates lists the authenticated user's templates
+func (ctrl *ProjectTemplateController) GetTemplates(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	templates, err := ctrl.templateService.GetTemplates(userID)
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, templates, "Project templates retrieved successfully")
+}
+
+// GetTemplate retrieves a single template
+func (ctrl *ProjectTemplateController) GetTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	templateID, err := strconv.ParseUint(c.Params("template_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid template ID")
+	}
+
+	template, err := ctrl.templateService.GetTemplate(uint(templateID), userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	return helper.Message200(c, template, "Project template retrieved successfully")
+}
+
+// DeleteTemplate removes a template
+func (ctrl *ProjectTemplateController) DeleteTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	templateID, err := strconv.ParseUint(c.Params("template_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid template ID")
+	}
+
+	if err := ctrl.templateService.DeleteTemplate(uint(templateID), userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Project template deleted successfully")
+}
+
+// CreateFromTemplate creates a new draft project from a template
+func (ctrl *ProjectTemplateController) CreateFromTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	templateID, err := strconv.ParseUint(c.Params("template_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid template ID")
+	}
+
+	startDate, err := parseStartDate(c)
+	if err != nil {
+		return err
+	}
+
+	project, err := ctrl.templateService.CreateProjectFromTemplate(uint(templateID), userID, c.FormValue("title"), startDate)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, project, "Project created from template successfully")
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ProjectTrashController struct {
+	trashService *service.ProjectTrashService
+}
+
+func NewProjectTrashController(pts *service.ProjectTrashService) *ProjectTrashController {
+	return &ProjectTrashController{trashService: pts}
+}
+
+// GetDeletedProjects lists the authenticated user's projects that are in the trash
+func (ctrl *ProjectTrashController) GetDeletedProjects(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	projects, err := ctrl.trashService.GetDeletedProjects(userID)
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, projects, "Deleted projects retrieved successfully")
+}
+
+// RestoreProject takes a deleted project out of the trash
+func (ctrl *ProjectTrashController) RestoreProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	project, err := ctrl.trashService.RestoreProject(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, project, "Project restored successfully")
+}
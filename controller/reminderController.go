@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type ReminderController struct {
+	reminderService *service.ReminderService
+}
+
+func NewReminderController(rs *service.ReminderService) *ReminderController {
+	return &ReminderController{reminderService: rs}
+}
+
+// GetReminderSettings retrieves the reminder offsets of a project
+func (ctrl *ReminderController) GetReminderSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	setting, err := ctrl.reminderService.GetSettings(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, setting, "Reminder settings retrieved successfully")
+}
+
+// UpdateReminderSettings changes the reminder offsets of a project.
+// Offsets are comma-separated days before the date, e.g. "7,3,1"; "none" turns that reminder off.
+func (ctrl *ReminderController) UpdateReminderSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	var data service.ReminderSettingsDTO
+
+	if enabledStr := c.FormValue("enabled"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return helper.Message400("Invalid enabled value")
+		}
+		data.Enabled = &enabled
+	}
+
+	fields := map[string]*[]int{
+		"registration_offsets": &data.RegistrationOffsets,
+		"start_date_offsets":   &data.StartDateOffsets,
+		"end_date_offsets":     &data.EndDateOffsets,
+		"milestone_offsets":    &data.MilestoneOffsets,
+	}
+	for key, target := range fields {
+		value := c.FormValue(key)
+		if value == "" {
+			continue
+		}
+		if value == "none" {
+			*target = []int{}
+			continue
+		}
+		offsets, err := service.ParseReminderOffsets(value)
+		if err != nil {
+			return helper.Message400(err.Error())
+		}
+		*target = offsets
+	}
+
+	setting, err := ctrl.reminderService.UpdateSettings(uint(projectID), userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, setting, "Reminder settings updated successfully")
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type SavedProjectController struct {
+	savedProjectService *service.SavedProjectService
+}
+
+func NewSavedProjectController(sps *service.SavedProjectService) *SavedProjectController {
+	return &SavedProjectController{savedProjectService: sps}
+}
+
+// SaveProject bookmarks a public project for the current user
+func (ctrl *SavedProjectController) SaveProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	if err := ctrl.savedProjectService.SaveProject(userID, uint(projectID)); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Project saved successfully")
+}
+
+// UnsaveProject removes a project from the current user's saved projects
+func (ctrl *SavedProjectController) UnsaveProject(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	if err := ctrl.savedProjectService.UnsaveProject(userID, uint(projectID)); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Project removed from saved projects")
+}
+
+// GetSavedProjects lists the current user's saved projects
+func (ctrl *SavedProjectController) GetSavedProjects(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	var saved []*model.SavedProject
+	paginationData, err := helper.Paginate(ctrl.savedProjectService.GetSavedProjectsQuery(userID), c, &saved)
+	if err != nil {
+		return helper.Message500("Failed to retrieve saved projects")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"saved_projects": saved,
+		"pagination":     paginationData,
+	}, "Saved projects retrieved successfully")
+}
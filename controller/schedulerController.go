@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type SchedulerController struct {
+	schedulerService *service.SchedulerService
+}
+
+func NewSchedulerController(ss *service.SchedulerService) *SchedulerController {
+	return &SchedulerController{schedulerService: ss}
+}
+
+// GetJobs lists scheduled jobs with their next run and most recent result
+func (ctrl *SchedulerController) GetJobs(c *fiber.Ctx) error {
+	jobs, err := ctrl.schedulerService.GetJobs()
+	if err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, jobs, "Scheduled jobs retrieved successfully")
+}
+
+// GetJobRuns retrieves the paginated run history of a job
+func (ctrl *SchedulerController) GetJobRuns(c *fiber.Ctx) error {
+	query, err := ctrl.schedulerService.GetJobRunsQuery(c.Params("name"))
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	var runs []model.JobRun
+	paginationData, err := helper.Paginate(query, c, &runs)
+	if err != nil {
+		return helper.Message500("Failed to retrieve job runs")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"runs":       runs,
+		"pagination": paginationData,
+	}, "Job runs retrieved successfully")
+}
+
+// TriggerJob runs a job immediately and returns the recorded run
+func (ctrl *SchedulerController) TriggerJob(c *fiber.Ctx) error {
+	run, err := ctrl.schedulerService.TriggerJob(c.Params("name"))
+	if err != nil {
+		if err == service.ErrJobRunning {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, run, "Job triggered successfully")
+}
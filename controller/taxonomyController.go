@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/service"
+)
+
+type TaxonomyController struct {
+	taxonomyService *service.TaxonomyService
+}
+
+func NewTaxonomyController(ts *service.TaxonomyService) *TaxonomyController {
+	return &TaxonomyController{taxonomyService: ts}
+}
+
+// taxonomyError answers 404 for missing entries and 400 for anything else
+func taxonomyError(err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return helper.Message404(err.Error())
+	}
+	return helper.Message400(err.Error())
+}
+
+func parseTaxonomyID(c *fiber.Ctx) (uint, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, helper.Message400("Invalid entry ID")
+	}
+	return uint(id), nil
+}
+
+// Autocomplete suggests tags or benefits matching q, the most used first
+func (ctrl *TaxonomyController) Autocomplete(c *fiber.Ctx) error {
+	entries, err := ctrl.taxonomyService.Autocomplete(c.Params("kind"), c.Query("q"), c.QueryInt("limit"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"entries": entries,
+	}, "Suggestions retrieved successfully")
+}
+
+// GetEntries lists the tags or benefits with their usage counts and aliases
+func (ctrl *TaxonomyController) GetEntries(c *fiber.Ctx) error {
+	kind := c.Params("kind")
+	query, err := ctrl.taxonomyService.GetEntriesQuery(kind, c.Query("q"), c.Query("sort"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	var entries []service.TaxonomyEntry
+	paginationData, err := helper.Paginate(query, c, &entries)
+	if err != nil {
+		return helper.Message500("Failed to retrieve entries")
+	}
+	if err := ctrl.taxonomyService.LoadAliases(kind, entries); err != nil {
+		return helper.Message500(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"entries":    entries,
+		"pagination": paginationData,
+	}, "Entries retrieved successfully")
+}
+
+// GetDuplicates groups entries whose names only differ in case, spacing or punctuation
+func (ctrl *TaxonomyController) GetDuplicates(c *fiber.Ctx) error {
+	groups, err := ctrl.taxonomyService.FindDuplicates(c.Params("kind"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"groups": groups,
+	}, "Duplicate candidates retrieved successfully")
+}
+
+// RenameEntry renames a tag or benefit
+func (ctrl *TaxonomyController) RenameEntry(c *fiber.Ctx) error {
+	id, err := parseTaxonomyID(c)
+	if err != nil {
+		return err
+	}
+
+	entry, err := ctrl.taxonomyService.RenameEntry(c.Params("kind"), id, c.FormValue("name"))
+	if err != nil {
+		return taxonomyError(err)
+	}
+
+	return helper.Message200(c, entry, "Entry renamed successfully")
+}
+
+// MergeEntries folds the entries in source_ids into the entry in the path
+func (ctrl *TaxonomyController) MergeEntries(c *fiber.Ctx) error {
+	id, err := parseTaxonomyID(c)
+	if err != nil {
+		return err
+	}
+
+	var sourceIDs []uint
+	for _, value := range strings.Split(c.FormValue("source_ids"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		sourceID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return helper.Message400("Invalid source ID: " + value)
+		}
+		sourceIDs = append(sourceIDs, uint(sourceID))
+	}
+
+	entry, err := ctrl.taxonomyService.MergeEntries(c.Params("kind"), id, sourceIDs)
+	if err != nil {
+		return taxonomyError(err)
+	}
+
+	return helper.Message200(c, entry, "Entries merged successfully")
+}
+
+// AddAlias adds another spelling that resolves to an entry
+func (ctrl *TaxonomyController) AddAlias(c *fiber.Ctx) error {
+	id, err := parseTaxonomyID(c)
+	if err != nil {
+		return err
+	}
+
+	entry, err := ctrl.taxonomyService.AddAlias(c.Params("kind"), id, c.FormValue("alias"))
+	if err != nil {
+		return taxonomyError(err)
+	}
+
+	return helper.Message201(c, entry, "Alias added successfully")
+}
+
+// RemoveAlias removes an alias of an entry
+func (ctrl *TaxonomyController) RemoveAlias(c *fiber.Ctx) error {
+	id, err := parseTaxonomyID(c)
+	if err != nil {
+		return err
+	}
+	aliasID, err := strconv.ParseUint(c.Params("alias_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid alias ID")
+	}
+
+	if err := ctrl.taxonomyService.RemoveAlias(c.Params("kind"), id, uint(aliasID)); err != nil {
+		return taxonomyError(err)
+	}
+
+	return helper.Message200(c, nil, "Alias removed successfully")
+}
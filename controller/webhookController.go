@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
+)
+
+type WebhookController struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookController(ws *service.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: ws}
+}
+
+func parseWebhookParams(c *fiber.Ctx) (uint, uint, error) {
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid project ID")
+	}
+	webhookID, err := strconv.ParseUint(c.Params("webhook_id"), 10, 32)
+	if err != nil {
+		return 0, 0, helper.Message400("Invalid webhook ID")
+	}
+	return uint(projectID), uint(webhookID), nil
+}
+
+func splitEvents(value string) []string {
+	var events []string
+	for _, event := range strings.Split(value, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// CreateWebhook registers a webhook endpoint for a project; the signing secret is only shown once
+func (ctrl *WebhookController) CreateWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	url := c.FormValue("url")
+	if url == "" {
+		return helper.Message400("Webhook URL is required")
+	}
+
+	endpoint, secret, err := ctrl.webhookService.CreateEndpoint(uint(projectID), userID, url, splitEvents(c.FormValue("events")), c.FormValue("description"))
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, fiber.Map{
+		"webhook": endpoint,
+		"secret":  secret,
+	}, "Webhook created successfully. Store the secret now, it will not be shown again")
+}
+
+// GetWebhooks lists the webhook endpoints of a project
+func (ctrl *WebhookController) GetWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, err := strconv.ParseUint(c.Params("project_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid project ID")
+	}
+
+	endpoints, err := ctrl.webhookService.GetEndpoints(uint(projectID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"webhooks":         endpoints,
+		"available_events": model.WebhookEvents,
+	}, "Webhooks retrieved successfully")
+}
+
+// UpdateWebhook changes the URL, events, description or active state of a webhook
+func (ctrl *WebhookController) UpdateWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return err
+	}
+
+	var data service.WebhookEndpointDTO
+	if url := c.FormValue("url"); url != "" {
+		data.URL = &url
+	}
+	if events := c.FormValue("events"); events != "" {
+		data.Events = splitEvents(events)
+	}
+	if description := c.FormValue("description"); description != "" {
+		data.Description = &description
+	}
+	if isActiveStr := c.FormValue("is_active"); isActiveStr != "" {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return helper.Message400("Invalid is_active value")
+		}
+		data.IsActive = &isActive
+	}
+
+	endpoint, err := ctrl.webhookService.UpdateEndpoint(projectID, webhookID, userID, data)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, endpoint, "Webhook updated successfully")
+}
+
+// DeleteWebhook removes a webhook endpoint and its delivery log
+func (ctrl *WebhookController) DeleteWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.webhookService.DeleteEndpoint(projectID, webhookID, userID); err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message200(c, nil, "Webhook deleted successfully")
+}
+
+// GetDeliveries lists the delivery log of a webhook endpoint
+func (ctrl *WebhookController) GetDeliveries(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return err
+	}
+
+	query, err := ctrl.webhookService.GetDeliveriesQuery(projectID, webhookID, userID)
+	if err != nil {
+		return helper.Message404(err.Error())
+	}
+
+	var deliveries []model.WebhookDelivery
+	paginationData, err := helper.Paginate(query, c, &deliveries)
+	if err != nil {
+		return helper.Message500("Failed to retrieve webhook deliveries")
+	}
+
+	return helper.Message200(c, fiber.Map{
+		"deliveries": deliveries,
+		"pagination": paginationData,
+	}, "Webhook deliveries retrieved successfully")
+}
+
+// RedeliverDelivery queues a previously logged delivery to be sent again
+func (ctrl *WebhookController) RedeliverDelivery(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseUint(c.Params("delivery_id"), 10, 32)
+	if err != nil {
+		return helper.Message400("Invalid delivery ID")
+	}
+
+	delivery, err := ctrl.webhookService.Redeliver(projectID, webhookID, uint(deliveryID), userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, delivery, "Webhook redelivery queued")
+}
+
+// PingWebhook sends a test event to a webhook endpoint
+func (ctrl *WebhookController) PingWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+	projectID, webhookID, err := parseWebhookParams(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := ctrl.webhookService.Ping(projectID, webhookID, userID)
+	if err != nil {
+		return helper.Message400(err.Error())
+	}
+
+	return helper.Message201(c, delivery, "Webhook ping queued")
+}
@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodeCursor builds an opaque keyset cursor from the sort timestamp and ID of the last item on a page
+func EncodeCursor(t time.Time, id uint) string {
+	raw := fmt.Sprintf("%d:%d", t.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor
+func DecodeCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, errors.New("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, errors.New("invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return time.Time{}, 0, errors.New("invalid cursor")
+	}
+
+	return time.Unix(0, nanos), uint(id), nil
+}
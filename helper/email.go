@@ -9,7 +9,7 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
-func SendPasswordResetEmail(email, token string) {
+func SendPasswordResetEmail(email, token string) error {
 	emailHost := os.Getenv("EMAIL_HOST")
 	emailPortStr := os.Getenv("EMAIL_PORT")
 	emailUser := os.Getenv("EMAIL_USERNAME")
@@ -18,8 +18,7 @@ func SendPasswordResetEmail(email, token string) {
 
 	emailPort, err := strconv.Atoi(emailPortStr)
 	if err != nil {
-		log.Printf("Error: Could not parse EMAIL_PORT from .env file: %v", err)
-		return
+		return fmt.Errorf("could not parse EMAIL_PORT: %v", err)
 	}
 
 	m := gomail.NewMessage()
@@ -60,8 +59,9 @@ func SendPasswordResetEmail(email, token string) {
 	d := gomail.NewDialer(emailHost, emailPort, emailUser, emailPass)
 
 	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Could not send password reset email to %s: %v", email, err)
-	} else {
-		log.Printf("Password reset email sent successfully to %s", email)
+		return fmt.Errorf("could not send password reset email to %s: %w", email, err)
 	}
+
+	log.Printf("Password reset email sent successfully to %s", email)
+	return nil
 }
@@ -21,3 +21,12 @@ func Message404(msg string) error {
 func Message500(msg string) error {
 	return fiber.NewError(fiber.StatusInternalServerError, msg)
 }
+
+// Message422 responds with what is wrong with each invalid field of a request
+func Message422(c *fiber.Ctx, fields map[string]string, msg string) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+		"success": false,
+		"message": msg,
+		"errors":  fields,
+	})
+}
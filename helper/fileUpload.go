@@ -1,10 +1,11 @@
 package helper
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,19 +14,15 @@ import (
 )
 
 func GetUrlFile(filepath string) string {
-	AppURL := os.Getenv("PUBLIC_APP_URL")
-	if AppURL == "" {
-		AppURL = os.Getenv("APP_URL")
-	}
-
-	if filepath == "" {
+	// Private files are only reachable through signed links
+	if filepath == "" || IsPrivateFile(filepath) {
 		return ""
 	}
-	normalizedPath := strings.ReplaceAll(filepath, "\\", "/")
-	return fmt.Sprintf("%s/%s", AppURL, normalizedPath)
+	return GetStorage().URL(filepath)
 }
 
 func UploadFile(file *multipart.FileHeader, uploadType string) (string, error) {
+	isImage := true
 	if uploadType == "cv" {
 		if !isValidatePDFType(file.Filename) {
 			return "", fmt.Errorf("invalid file type. Only PDF files are allowed for CV upload")
@@ -33,18 +30,21 @@ func UploadFile(file *multipart.FileHeader, uploadType string) (string, error) {
 		if file.Size > 10*1024*1024 {
 			return "", fmt.Errorf("CV file too large, maximum is 10MB")
 		}
-	} else {
-		if !isValidateImageType(file.Filename) {
-			return "", fmt.Errorf("invalid file type. Only jpg, jpeg, png, gif are allowed")
+		isImage = false
+	} else if uploadType == "announcement" {
+		if !isValidateImageType(file.Filename) && !isValidatePDFType(file.Filename) {
+			return "", fmt.Errorf("invalid file type. Only jpg, jpeg, png and pdf files can be attached")
 		}
-		if file.Size > 2*1024*1024 {
-			return "", fmt.Errorf("file too large, maximum is only 2MB")
+		if file.Size > 10*1024*1024 {
+			return "", fmt.Errorf("attachment too large, maximum is 10MB")
+		}
+		isImage = isValidateImageType(file.Filename)
+	} else {
+		if file.Size > maxImageUploadSize {
+			return "", fmt.Errorf("image too large, maximum is 10MB")
 		}
 	}
 
-	ext := filepath.Ext(file.Filename)
-	newFilename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
-
 	var uploadDir string
 	switch uploadType {
 	case "profile":
@@ -52,17 +52,13 @@ func UploadFile(file *multipart.FileHeader, uploadType string) (string, error) {
 	case "post":
 		uploadDir = "storage/posts"
 	case "cv":
-		uploadDir = "storage/cv"
+		uploadDir = PrivateFilePrefix + "cv"
+	case "announcement":
+		uploadDir = PrivateFilePrefix + "announcements"
 	default:
 		uploadDir = "storage/temp"
 	}
 
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %v", err)
-	}
-
-	filePath := filepath.Join(uploadDir, newFilename)
-
 	src, err := file.Open()
 	if err != nil {
 		return "", fmt.Errorf("failed to open upload file: %v", err)
@@ -70,15 +66,25 @@ func UploadFile(file *multipart.FileHeader, uploadType string) (string, error) {
 
 	defer src.Close()
 
-	dst, err := os.Create(filePath)
+	data, err := io.ReadAll(src)
 	if err != nil {
-		return "", fmt.Errorf("failed to create file: %v", err)
+		return "", fmt.Errorf("failed to read upload file: %v", err)
 	}
 
-	defer dst.Close()
+	baseName := path.Join(uploadDir, fmt.Sprintf("%s_%d", uuid.New().String(), time.Now().Unix()))
+
+	// Images are decoded and re-encoded, which also strips their metadata
+	if isImage {
+		return saveImage(data, baseName)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", fmt.Errorf("invalid file type. The file is not a valid PDF")
+	}
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to save file: %v", err)
+	filePath := baseName + ".pdf"
+	if err := GetStorage().Put(filePath, data, "application/pdf"); err != nil {
+		return "", err
 	}
 
 	return filePath, nil
@@ -106,9 +112,17 @@ func DeleteFile(filePath string) error {
 		return nil
 	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil
+	storage := GetStorage()
+	if err := storage.Delete(filePath); err != nil {
+		return err
 	}
 
-	return os.Remove(filePath)
+	// Remove the resized variants stored next to images
+	if !isValidateImageType(filePath) {
+		return nil
+	}
+	for _, variant := range ImageVariants() {
+		storage.Delete(ImageVariantPath(filePath, variant))
+	}
+	return nil
 }
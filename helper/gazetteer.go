@@ -0,0 +1,242 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+)
+
+// gazetteerCity is a city in the built-in gazetteer. Coordinates are the city centre.
+type gazetteerCity struct {
+	name      string
+	region    string
+	latitude  float64
+	longitude float64
+	aliases   []string
+}
+
+// gazetteerRegion is a province; its coordinates are those of its capital
+type gazetteerRegion struct {
+	name    string
+	capital string
+	aliases []string
+}
+
+var gazetteerCities = []gazetteerCity{
+	{"Jakarta", "DKI Jakarta", -6.2088, 106.8456, []string{"jkt", "dki"}},
+	{"Jakarta Pusat", "DKI Jakarta", -6.1865, 106.8343, []string{"jakpus"}},
+	{"Jakarta Selatan", "DKI Jakarta", -6.2615, 106.8106, []string{"jaksel"}},
+	{"Jakarta Barat", "DKI Jakarta", -6.1674, 106.7637, []string{"jakbar"}},
+	{"Jakarta Timur", "DKI Jakarta", -6.2250, 106.9004, []string{"jaktim"}},
+	{"Jakarta Utara", "DKI Jakarta", -6.1384, 106.8636, []string{"jakut"}},
+	{"Bogor", "Jawa Barat", -6.5971, 106.8060, nil},
+	{"Depok", "Jawa Barat", -6.4025, 106.7942, nil},
+	{"Bekasi", "Jawa Barat", -6.2383, 106.9756, nil},
+	{"Bandung", "Jawa Barat", -6.9175, 107.6191, nil},
+	{"Cimahi", "Jawa Barat", -6.8722, 107.5425, nil},
+	{"Cirebon", "Jawa Barat", -6.7320, 108.5523, nil},
+	{"Sukabumi", "Jawa Barat", -6.9277, 106.9300, nil},
+	{"Tasikmalaya", "Jawa Barat", -7.3274, 108.2207, nil},
+	{"Karawang", "Jawa Barat", -6.3227, 107.3376, nil},
+	{"Purwakarta", "Jawa Barat", -6.5569, 107.4433, nil},
+	{"Garut", "Jawa Barat", -7.2167, 107.9000, nil},
+	{"Subang", "Jawa Barat", -6.5697, 107.7586, nil},
+	{"Tangerang", "Banten", -6.1783, 106.6319, nil},
+	{"Tangerang Selatan", "Banten", -6.2886, 106.7179, []string{"tangsel"}},
+	{"Serang", "Banten", -6.1200, 106.1503, nil},
+	{"Cilegon", "Banten", -6.0025, 106.0111, nil},
+	{"Semarang", "Jawa Tengah", -6.9667, 110.4167, nil},
+	{"Surakarta", "Jawa Tengah", -7.5755, 110.8243, []string{"solo"}},
+	{"Magelang", "Jawa Tengah", -7.4797, 110.2177, nil},
+	{"Salatiga", "Jawa Tengah", -7.3305, 110.5084, nil},
+	{"Tegal", "Jawa Tengah", -6.8694, 109.1402, nil},
+	{"Pekalongan", "Jawa Tengah", -6.8898, 109.6746, nil},
+	{"Purwokerto", "Jawa Tengah", -7.4245, 109.2396, []string{"banyumas"}},
+	{"Kudus", "Jawa Tengah", -6.8048, 110.8405, nil},
+	{"Jepara", "Jawa Tengah", -6.5887, 110.6684, nil},
+	{"Cilacap", "Jawa Tengah", -7.7267, 109.0094, nil},
+	{"Klaten", "Jawa Tengah", -7.7058, 110.6064, nil},
+	{"Yogyakarta", "DI Yogyakarta", -7.7956, 110.3695, []string{"jogja", "jogjakarta", "yogya", "jogya"}},
+	{"Sleman", "DI Yogyakarta", -7.7160, 110.3554, nil},
+	{"Bantul", "DI Yogyakarta", -7.8881, 110.3289, nil},
+	{"Surabaya", "Jawa Timur", -7.2575, 112.7521, []string{"sby"}},
+	{"Malang", "Jawa Timur", -7.9666, 112.6326, nil},
+	{"Batu", "Jawa Timur", -7.8671, 112.5239, nil},
+	{"Sidoarjo", "Jawa Timur", -7.4478, 112.7183, nil},
+	{"Gresik", "Jawa Timur", -7.1539, 112.6561, nil},
+	{"Kediri", "Jawa Timur", -7.8480, 112.0178, nil},
+	{"Madiun", "Jawa Timur", -7.6298, 111.5239, nil},
+	{"Jember", "Jawa Timur", -8.1724, 113.7005, nil},
+	{"Banyuwangi", "Jawa Timur", -8.2192, 114.3691, nil},
+	{"Mojokerto", "Jawa Timur", -7.4705, 112.4401, nil},
+	{"Probolinggo", "Jawa Timur", -7.7543, 113.2159, nil},
+	{"Pasuruan", "Jawa Timur", -7.6453, 112.9075, nil},
+	{"Blitar", "Jawa Timur", -8.0983, 112.1681, nil},
+	{"Tuban", "Jawa Timur", -6.8976, 112.0649, nil},
+	{"Bojonegoro", "Jawa Timur", -7.1502, 111.8817, nil},
+	{"Ponorogo", "Jawa Timur", -7.8651, 111.4696, nil},
+	{"Bangkalan", "Jawa Timur", -7.0455, 112.7351, []string{"madura"}},
+	{"Denpasar", "Bali", -8.6705, 115.2126, nil},
+	{"Kuta", "Bali", -8.7220, 115.1727, nil},
+	{"Ubud", "Bali", -8.5069, 115.2625, nil},
+	{"Mataram", "Nusa Tenggara Barat", -8.5833, 116.1167, []string{"lombok"}},
+	{"Kupang", "Nusa Tenggara Timur", -10.1772, 123.6070, nil},
+	{"Labuan Bajo", "Nusa Tenggara Timur", -8.4964, 119.8877, nil},
+	{"Banda Aceh", "Aceh", 5.5483, 95.3238, nil},
+	{"Lhokseumawe", "Aceh", 5.1801, 97.1507, nil},
+	{"Medan", "Sumatera Utara", 3.5952, 98.6722, nil},
+	{"Binjai", "Sumatera Utara", 3.6001, 98.4854, nil},
+	{"Pematangsiantar", "Sumatera Utara", 2.9595, 99.0687, []string{"siantar"}},
+	{"Padang", "Sumatera Barat", -0.9471, 100.4172, nil},
+	{"Bukittinggi", "Sumatera Barat", -0.3056, 100.3692, nil},
+	{"Pekanbaru", "Riau", 0.5071, 101.4478, nil},
+	{"Dumai", "Riau", 1.6653, 101.4470, nil},
+	{"Batam", "Kepulauan Riau", 1.0456, 104.0305, nil},
+	{"Tanjung Pinang", "Kepulauan Riau", 0.9186, 104.4554, []string{"tanjungpinang"}},
+	{"Jambi", "Jambi", -1.6101, 103.6131, nil},
+	{"Palembang", "Sumatera Selatan", -2.9761, 104.7754, nil},
+	{"Bengkulu", "Bengkulu", -3.7928, 102.2608, nil},
+	{"Bandar Lampung", "Lampung", -5.3971, 105.2668, nil},
+	{"Pangkal Pinang", "Kepulauan Bangka Belitung", -2.1316, 106.1169, []string{"pangkalpinang"}},
+	{"Pontianak", "Kalimantan Barat", -0.0263, 109.3425, nil},
+	{"Singkawang", "Kalimantan Barat", 0.9060, 108.9872, nil},
+	{"Palangka Raya", "Kalimantan Tengah", -2.2096, 113.9108, []string{"palangkaraya"}},
+	{"Banjarmasin", "Kalimantan Selatan", -3.3186, 114.5944, nil},
+	{"Banjarbaru", "Kalimantan Selatan", -3.4572, 114.8103, nil},
+	{"Samarinda", "Kalimantan Timur", -0.5022, 117.1536, nil},
+	{"Balikpapan", "Kalimantan Timur", -1.2379, 116.8529, nil},
+	{"Tanjung Selor", "Kalimantan Utara", 2.8375, 117.3653, nil},
+	{"Tarakan", "Kalimantan Utara", 3.3274, 117.5785, nil},
+	{"Makassar", "Sulawesi Selatan", -5.1477, 119.4327, nil},
+	{"Parepare", "Sulawesi Selatan", -4.0135, 119.6255, nil},
+	{"Mamuju", "Sulawesi Barat", -2.6748, 118.8885, nil},
+	{"Palu", "Sulawesi Tengah", -0.8917, 119.8707, nil},
+	{"Kendari", "Sulawesi Tenggara", -3.9985, 122.5130, nil},
+	{"Gorontalo", "Gorontalo", 0.5435, 123.0568, nil},
+	{"Manado", "Sulawesi Utara", 1.4748, 124.8421, nil},
+	{"Bitung", "Sulawesi Utara", 1.4405, 125.1217, nil},
+	{"Ambon", "Maluku", -3.6954, 128.1814, nil},
+	{"Sofifi", "Maluku Utara", 0.7370, 127.5580, nil},
+	{"Ternate", "Maluku Utara", 0.7893, 127.3774, nil},
+	{"Jayapura", "Papua", -2.5337, 140.7181, nil},
+	{"Manokwari", "Papua Barat", -0.8615, 134.0620, nil},
+	{"Sorong", "Papua Barat Daya", -0.8762, 131.2558, nil},
+	{"Nabire", "Papua Tengah", -3.3667, 135.4833, nil},
+	{"Timika", "Papua Tengah", -4.5467, 136.8833, nil},
+	{"Wamena", "Papua Pegunungan", -4.0958, 138.9461, nil},
+	{"Merauke", "Papua Selatan", -8.4932, 140.4018, nil},
+}
+
+var gazetteerRegions = []gazetteerRegion{
+	{"DKI Jakarta", "Jakarta", nil},
+	{"Jawa Barat", "Bandung", []string{"jabar", "west java"}},
+	{"Banten", "Serang", nil},
+	{"Jawa Tengah", "Semarang", []string{"jateng", "central java"}},
+	{"DI Yogyakarta", "Yogyakarta", []string{"diy"}},
+	{"Jawa Timur", "Surabaya", []string{"jatim", "east java"}},
+	{"Bali", "Denpasar", nil},
+	{"Nusa Tenggara Barat", "Mataram", []string{"ntb"}},
+	{"Nusa Tenggara Timur", "Kupang", []string{"ntt"}},
+	{"Aceh", "Banda Aceh", nil},
+	{"Sumatera Utara", "Medan", []string{"sumut", "north sumatra"}},
+	{"Sumatera Barat", "Padang", []string{"sumbar", "west sumatra"}},
+	{"Riau", "Pekanbaru", nil},
+	{"Kepulauan Riau", "Tanjung Pinang", []string{"kepri"}},
+	{"Jambi", "Jambi", nil},
+	{"Sumatera Selatan", "Palembang", []string{"sumsel", "south sumatra"}},
+	{"Bengkulu", "Bengkulu", nil},
+	{"Lampung", "Bandar Lampung", nil},
+	{"Kepulauan Bangka Belitung", "Pangkal Pinang", []string{"babel", "bangka belitung"}},
+	{"Kalimantan Barat", "Pontianak", []string{"kalbar"}},
+	{"Kalimantan Tengah", "Palangka Raya", []string{"kalteng"}},
+	{"Kalimantan Selatan", "Banjarbaru", []string{"kalsel"}},
+	{"Kalimantan Timur", "Samarinda", []string{"kaltim"}},
+	{"Kalimantan Utara", "Tanjung Selor", []string{"kaltara"}},
+	{"Sulawesi Selatan", "Makassar", []string{"sulsel"}},
+	{"Sulawesi Barat", "Mamuju", []string{"sulbar"}},
+	{"Sulawesi Tengah", "Palu", []string{"sulteng"}},
+	{"Sulawesi Tenggara", "Kendari", []string{"sultra"}},
+	{"Gorontalo", "Gorontalo", nil},
+	{"Sulawesi Utara", "Manado", []string{"sulut"}},
+	{"Maluku", "Ambon", nil},
+	{"Maluku Utara", "Sofifi", []string{"malut"}},
+	{"Papua", "Jayapura", nil},
+	{"Papua Barat", "Manokwari", nil},
+	{"Papua Barat Daya", "Sorong", nil},
+	{"Papua Tengah", "Nabire", nil},
+	{"Papua Pegunungan", "Wamena", nil},
+	{"Papua Selatan", "Merauke", nil},
+}
+
+// Gazetteer is a Geocoder backed by a built-in list of Indonesian cities and provinces. It needs
+// no external service but only knows the larger cities.
+type Gazetteer struct {
+	cities  map[string]*gazetteerCity
+	regions map[string]*gazetteerRegion
+	byName  map[string]*gazetteerCity
+}
+
+var defaultGazetteer = NewGazetteer()
+
+func NewGazetteer() *Gazetteer {
+	g := &Gazetteer{
+		cities:  map[string]*gazetteerCity{},
+		regions: map[string]*gazetteerRegion{},
+		byName:  map[string]*gazetteerCity{},
+	}
+	for i := range gazetteerCities {
+		city := &gazetteerCities[i]
+		g.byName[city.name] = city
+		g.cities[normalizePlaceName(city.name)] = city
+		for _, alias := range city.aliases {
+			g.cities[normalizePlaceName(alias)] = city
+		}
+	}
+	for i := range gazetteerRegions {
+		region := &gazetteerRegions[i]
+		g.regions[normalizePlaceName(region.name)] = region
+		for _, alias := range region.aliases {
+			g.regions[normalizePlaceName(alias)] = region
+		}
+	}
+	return g
+}
+
+// normalizePlaceName lowercases a name and turns everything but letters and digits into single spaces
+func normalizePlaceName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// longestMatch returns the longest name in names that appears as whole words in text
+func longestMatch[T any](text string, names map[string]*T) *T {
+	padded := " " + text + " "
+	var best *T
+	bestName := ""
+	for name, value := range names {
+		longer := len(name) > len(bestName) || (len(name) == len(bestName) && name < bestName)
+		if longer && strings.Contains(padded, " "+name+" ") {
+			best, bestName = value, name
+		}
+	}
+	return best
+}
+
+// Geocode finds the most specific city named in query, such as "Jakarta Selatan" in
+// "Kemang, Jakarta Selatan". When only a province is named, the place has no city and lies
+// at the provincial capital.
+func (g *Gazetteer) Geocode(query string) (*GeoPlace, error) {
+	text := normalizePlaceName(query)
+	if text == "" {
+		return nil, ErrPlaceNotFound
+	}
+
+	if city := longestMatch(text, g.cities); city != nil {
+		return &GeoPlace{City: city.name, Region: city.region, Latitude: city.latitude, Longitude: city.longitude}, nil
+	}
+	if region := longestMatch(text, g.regions); region != nil {
+		capital := g.byName[region.capital]
+		return &GeoPlace{Region: region.name, Latitude: capital.latitude, Longitude: capital.longitude}, nil
+	}
+	return nil, ErrPlaceNotFound
+}
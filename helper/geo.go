@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// earthRadiusKm is the mean radius of the earth used for distances
+const earthRadiusKm = 6371.0
+
+// ErrPlaceNotFound is returned when a geocoder doesn't recognise a place
+var ErrPlaceNotFound = errors.New("place not found")
+
+// GeoPlace is a place resolved from a free-text location
+type GeoPlace struct {
+	City      string
+	Region    string
+	Latitude  float64
+	Longitude float64
+}
+
+// Geocoder resolves free-text locations such as "Bandung, Jawa Barat" to a place
+type Geocoder interface {
+	Geocode(query string) (*GeoPlace, error)
+}
+
+var (
+	geocoder   Geocoder
+	geocoderMu sync.RWMutex
+)
+
+// GetGeocoder returns the geocoder in use, the built-in gazetteer of Indonesian cities by default
+func GetGeocoder() Geocoder {
+	geocoderMu.RLock()
+	defer geocoderMu.RUnlock()
+	if geocoder == nil {
+		return defaultGazetteer
+	}
+	return geocoder
+}
+
+// SetGeocoder replaces the geocoder, e.g. with one backed by a fuller place database
+func SetGeocoder(g Geocoder) {
+	geocoderMu.Lock()
+	defer geocoderMu.Unlock()
+	geocoder = g
+}
+
+// ValidCoordinates reports whether a latitude and longitude are within range
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// HaversineKm returns the great-circle distance between two points in kilometres
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLon := radians(lon2 - lon1)
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns the latitude and longitude ranges that contain every point within
+// radiusKm of the centre. It is used to narrow a query before the exact distance check.
+func BoundingBox(latitude, longitude, radiusKm float64) (minLat, maxLat, minLon, maxLon float64) {
+	deltaLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	minLat, maxLat = latitude-deltaLat, latitude+deltaLat
+
+	// Near the poles the box spans every longitude
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180
+	}
+	deltaLon := math.Asin(math.Min(1, math.Sin(radiusKm/earthRadiusKm)/math.Cos(radians(latitude)))) * 180 / math.Pi
+	return minLat, maxLat, longitude - deltaLon, longitude + deltaLon
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,113 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICSContentType is the media type of iCalendar files
+const ICSContentType = "text/calendar; charset=utf-8"
+
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405Z"
+	// icsLineLimit is the longest a content line may be, in octets, before it is folded
+	icsLineLimit = 75
+)
+
+// ICSEvent is an event of an iCalendar feed. All-day events use the dates of Start and End in
+// local time, with End inclusive. A stable UID and a growing Sequence let calendar apps update
+// the event in place when it changes.
+type ICSEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Cancelled    bool
+	Sequence     int64
+	LastModified time.Time
+}
+
+// icsEscape escapes text values as RFC 5545 requires
+func icsEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeICSLine writes a content line, folding it at 75 octets without splitting a character
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// BuildICS renders events as an iCalendar file named name
+func BuildICS(name string, events []ICSEvent) []byte {
+	var b strings.Builder
+	now := time.Now().UTC().Format(icsDateTimeLayout)
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Synergazing//Calendar//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, "X-WR-CALNAME:"+icsEscape(name))
+	// Ask subscribed calendars to refresh hourly, so changes to projects show up
+	writeICSLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICSLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	for _, event := range events {
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+event.UID)
+		writeICSLine(&b, "DTSTAMP:"+now)
+		if event.AllDay {
+			start := event.Start.In(time.Local)
+			end := event.End.In(time.Local)
+			if end.Before(start) {
+				end = start
+			}
+			// DTEND of an all-day event is the day after the last one
+			endDay := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, time.Local)
+			writeICSLine(&b, "DTSTART;VALUE=DATE:"+start.Format(icsDateLayout))
+			writeICSLine(&b, "DTEND;VALUE=DATE:"+endDay.Format(icsDateLayout))
+		} else {
+			writeICSLine(&b, "DTSTART:"+event.Start.UTC().Format(icsDateTimeLayout))
+			writeICSLine(&b, "DTEND:"+event.End.UTC().Format(icsDateTimeLayout))
+		}
+		writeICSLine(&b, "SUMMARY:"+icsEscape(event.Summary))
+		if event.Description != "" {
+			writeICSLine(&b, "DESCRIPTION:"+icsEscape(event.Description))
+		}
+		if event.Location != "" {
+			writeICSLine(&b, "LOCATION:"+icsEscape(event.Location))
+		}
+		if !event.LastModified.IsZero() {
+			writeICSLine(&b, "LAST-MODIFIED:"+event.LastModified.UTC().Format(icsDateTimeLayout))
+		}
+		writeICSLine(&b, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		if event.Cancelled {
+			writeICSLine(&b, "STATUS:CANCELLED")
+		} else {
+			writeICSLine(&b, "STATUS:CONFIRMED")
+		}
+		writeICSLine(&b, "TRANSP:TRANSPARENT")
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
@@ -0,0 +1,370 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+)
+
+// Image variant names
+const (
+	ImageVariantThumbnail = "thumbnail"
+	ImageVariantMedium    = "medium"
+	ImageVariantLarge     = "large"
+)
+
+const (
+	// maxImageUploadSize is the largest image accepted before it is re-encoded
+	maxImageUploadSize = 10 * 1024 * 1024
+	// maxImagePixels guards against images that are small on disk but huge once decoded
+	maxImagePixels = 40_000_000
+	// maxStoredImageSize is the longest side of the stored original
+	maxStoredImageSize = 2048
+	jpegQuality        = 85
+)
+
+type imageVariantSpec struct {
+	name   string
+	size   int
+	square bool
+}
+
+// imageVariantSpecs lists the variants stored next to every uploaded image
+var imageVariantSpecs = []imageVariantSpec{
+	{name: ImageVariantThumbnail, size: 150, square: true},
+	{name: ImageVariantMedium, size: 600},
+	{name: ImageVariantLarge, size: 1200},
+}
+
+// ImageVariants lists the variant names in size order
+func ImageVariants() []string {
+	names := make([]string, 0, len(imageVariantSpecs))
+	for _, spec := range imageVariantSpecs {
+		names = append(names, spec.name)
+	}
+	return names
+}
+
+// ImageVariantPath returns where a variant of the image stored at key is kept
+func ImageVariantPath(key, variant string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + variant + ext
+}
+
+// GetImageVariantURLs returns the public URL of every variant of an image, or nil without an image
+func GetImageVariantURLs(key string) map[string]string {
+	if key == "" {
+		return nil
+	}
+	urls := make(map[string]string, len(imageVariantSpecs))
+	for _, spec := range imageVariantSpecs {
+		urls[spec.name] = GetUrlFile(ImageVariantPath(key, spec.name))
+	}
+	return urls
+}
+
+// detectImageFormat identifies an image from its leading bytes; the file extension is not trusted
+func detectImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	}
+	return ""
+}
+
+// readJPEGOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when it has none
+func readJPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte before a marker
+			i++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			// Image data starts; EXIF always comes before it
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return parseExifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// parseExifOrientation reads the orientation tag from the first IFD of a TIFF structure
+func parseExifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// toRGBA copies any image into an RGBA image whose bounds start at 0,0
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// applyOrientation rotates or flips an image so it displays upright without its EXIF orientation
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			si := src.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// resizeImage scales src down to w x h by averaging the source pixels under each target pixel
+func resizeImage(src *image.RGBA, w, h int) *image.RGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	if w == sw && h == sh {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy0, sy1 := y*sh/h, (y+1)*sh/h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < w; x++ {
+			sx0, sx1 := x*sw/w, (x+1)*sw/w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				i := src.PixOffset(bounds.Min.X+sx0, bounds.Min.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					b += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// fitImage scales an image down so its longest side is at most size; smaller images are kept as they are
+func fitImage(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	if w >= h {
+		return resizeImage(src, size, max(1, h*size/w))
+	}
+	return resizeImage(src, max(1, w*size/h), size)
+}
+
+// squareImage crops the centre square of an image and scales it down to at most size x size
+func squareImage(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	side := min(w, h)
+	x0, y0 := (w-side)/2, (h-side)/2
+	cropped := src.SubImage(image.Rect(x0, y0, x0+side, y0+side)).(*image.RGBA)
+	return resizeImage(cropped, min(side, size), min(side, size))
+}
+
+// decodeImage validates an image by its content and decodes it upright
+func decodeImage(data []byte) (*image.RGBA, error) {
+	format := detectImageFormat(data)
+	if format == "" {
+		return nil, fmt.Errorf("invalid file type. Only jpg, jpeg and png images are allowed")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image is too large, maximum is %d megapixels", maxImagePixels/1_000_000)
+	}
+
+	var img image.Image
+	if format == "jpeg" {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		img, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+
+	rgba := toRGBA(img)
+	if format == "jpeg" {
+		rgba = applyOrientation(rgba, readJPEGOrientation(data))
+	}
+	return rgba, nil
+}
+
+// writeImage encodes an image as PNG or JPEG depending on the extension of key and stores it.
+// Encoding from decoded pixels drops EXIF and any other metadata of the upload.
+func writeImage(key string, img *image.RGBA) error {
+	var buf bytes.Buffer
+	var err error
+	contentType := "image/jpeg"
+	if strings.ToLower(path.Ext(key)) == ".png" {
+		contentType = "image/png"
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %v", err)
+	}
+	return GetStorage().Put(key, buf.Bytes(), contentType)
+}
+
+// writeImageVariants stores every variant of img next to key
+func writeImageVariants(key string, img *image.RGBA) error {
+	for _, spec := range imageVariantSpecs {
+		variant := fitImage(img, spec.size)
+		if spec.square {
+			variant = squareImage(img, spec.size)
+		}
+		if err := writeImage(ImageVariantPath(key, spec.name), variant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveImage decodes, normalises and stores an uploaded image with all its variants under baseKey
+// (without extension). Images with transparency are kept as PNG, everything else becomes JPEG.
+func saveImage(data []byte, baseKey string) (string, error) {
+	img, err := decodeImage(data)
+	if err != nil {
+		return "", err
+	}
+	img = fitImage(img, maxStoredImageSize)
+
+	key := baseKey + ".jpg"
+	if !img.Opaque() {
+		key = baseKey + ".png"
+	}
+
+	if err := writeImage(key, img); err != nil {
+		return "", err
+	}
+	if err := writeImageVariants(key, img); err != nil {
+		DeleteFile(key)
+		return "", err
+	}
+	return key, nil
+}
+
+// GenerateImageVariants creates the variants of an image stored before variants existed
+func GenerateImageVariants(key string) error {
+	data, err := GetStorage().Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %v", err)
+	}
+	img, err := decodeImage(data)
+	if err != nil {
+		return err
+	}
+	return writeImageVariants(key, fitImage(img, maxStoredImageSize))
+}
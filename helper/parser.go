@@ -3,6 +3,7 @@ package helper
 import (
 	"encoding/json"
 	"strconv"
+	"time"
 )
 
 func ParseStringSlice(jsonString string) ([]string, error) {
@@ -32,3 +33,11 @@ func StringToFloat(s string) (float64, error) {
 	}
 	return strconv.ParseFloat(s, 64)
 }
+
+// ParseDate parses an RFC3339 timestamp or a plain YYYY-MM-DD date
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
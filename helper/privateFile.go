@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrivateFilePrefix holds file classes that are never served statically, such as CVs.
+// They can only be fetched through a signed URL.
+const PrivateFilePrefix = "private/"
+
+var (
+	ErrInvalidFileSignature = errors.New("invalid file link")
+	ErrFileLinkExpired      = errors.New("file link has expired")
+)
+
+// IsPrivateFile reports whether a stored file belongs to a private file class
+func IsPrivateFile(filePath string) bool {
+	return strings.HasPrefix(normalizeStorageKey(filePath), PrivateFilePrefix)
+}
+
+// GetCVUrl returns the endpoint that hands out signed links to a user's CV, or "" without a CV
+func GetCVUrl(userID uint, cvFile string) string {
+	if cvFile == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/api/users/%d/cv", GetAppURL(), userID)
+}
+
+// SignedFile is what a signed file link grants: one viewer may read one file until it expires
+type SignedFile struct {
+	Path      string
+	OwnerID   uint
+	ViewerID  uint
+	ProjectID uint
+	ExpiresAt time.Time
+}
+
+func fileSigningSecret() []byte {
+	if secret := os.Getenv("FILE_SIGNING_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	return []byte(os.Getenv("JWT_SECRET"))
+}
+
+func (f SignedFile) signature() string {
+	mac := hmac.New(sha256.New, fileSigningSecret())
+	fmt.Fprintf(mac, "%s\n%d\n%d\n%d\n%d", normalizeStorageKey(f.Path), f.OwnerID, f.ViewerID, f.ProjectID, f.ExpiresAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignFileURL returns a link to /api/files/private that serves the file until it expires
+func SignFileURL(f SignedFile) string {
+	query := url.Values{
+		"path":      {normalizeStorageKey(f.Path)},
+		"owner":     {strconv.FormatUint(uint64(f.OwnerID), 10)},
+		"viewer":    {strconv.FormatUint(uint64(f.ViewerID), 10)},
+		"project":   {strconv.FormatUint(uint64(f.ProjectID), 10)},
+		"expires":   {strconv.FormatInt(f.ExpiresAt.Unix(), 10)},
+		"signature": {f.signature()},
+	}
+	return GetAppURL() + "/api/files/private?" + query.Encode()
+}
+
+// VerifySignedFile checks the query parameters of a signed file link and returns what it grants
+func VerifySignedFile(query func(key string, defaultValue ...string) string) (*SignedFile, error) {
+	owner, err1 := strconv.ParseUint(query("owner"), 10, 32)
+	viewer, err2 := strconv.ParseUint(query("viewer"), 10, 32)
+	project, err3 := strconv.ParseUint(query("project"), 10, 32)
+	expires, err4 := strconv.ParseInt(query("expires"), 10, 64)
+	if err := errors.Join(err1, err2, err3, err4); err != nil || query("path") == "" {
+		return nil, ErrInvalidFileSignature
+	}
+
+	file := &SignedFile{
+		Path:      query("path"),
+		OwnerID:   uint(owner),
+		ViewerID:  uint(viewer),
+		ProjectID: uint(project),
+		ExpiresAt: time.Unix(expires, 0),
+	}
+	if !IsPrivateFile(file.Path) || !hmac.Equal([]byte(file.signature()), []byte(query("signature"))) {
+		return nil, ErrInvalidFileSignature
+	}
+	if time.Now().After(file.ExpiresAt) {
+		return nil, ErrFileLinkExpired
+	}
+	return file, nil
+}
@@ -0,0 +1,243 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency is used for budgets that don't name a currency
+const DefaultCurrency = "IDR"
+
+var (
+	// amountPattern matches a number with an optional unit such as "5jt", "1.5 juta" or "500k"
+	amountPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)*)\s*(juta|jt|million|mio|ribu|rb|thousand|k)?\b`)
+	// thousandsPattern matches numbers written with thousands separators, e.g. "5.000.000" or "1,500"
+	thousandsPattern = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+
+	amountUnits = map[string]float64{
+		"juta": 1e6, "jt": 1e6, "million": 1e6, "mio": 1e6,
+		"ribu": 1e3, "rb": 1e3, "thousand": 1e3, "k": 1e3,
+	}
+	currencySymbols = []struct{ symbol, code string }{
+		{"rp", "IDR"}, {"idr", "IDR"}, {"usd", "USD"}, {"us$", "USD"}, {"$", "USD"},
+		{"sgd", "SGD"}, {"eur", "EUR"}, {"€", "EUR"},
+	}
+	unpaidPattern = regexp.MustCompile(`\b(unpaid|volunteer|sukarela|tidak dibayar|tanpa bayaran|gratis|free|non-paid|no pay)\b`)
+)
+
+// parseAmount turns "5.000.000", "1,5" or "2.5" into a number
+func parseAmount(number string) (float64, bool) {
+	if thousandsPattern.MatchString(number) {
+		number = strings.NewReplacer(".", "", ",", "").Replace(number)
+	} else {
+		number = strings.ReplaceAll(number, ",", ".")
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	return value, err == nil
+}
+
+// parseAmounts returns every amount in text with its unit applied. In a range such as
+// "5 - 10 juta" a unit written only after the upper end applies to the lower end too,
+// unless the bare lower number is already the larger one, as in "500 - 1k".
+func parseAmounts(text string) []float64 {
+	var amounts, bare []float64
+	var units []string
+	for _, match := range amountPattern.FindAllStringSubmatch(text, -1) {
+		value, ok := parseAmount(match[1])
+		if !ok {
+			continue
+		}
+		bare = append(bare, value)
+		units = append(units, match[2])
+		if unit, found := amountUnits[match[2]]; found {
+			value *= unit
+		}
+		amounts = append(amounts, value)
+	}
+
+	if len(amounts) == 2 && units[0] == "" && units[1] != "" && bare[0] < bare[1] {
+		amounts[0] *= amountUnits[units[1]]
+	}
+	return amounts
+}
+
+// ParsedBudget is a budget read from free text
+type ParsedBudget struct {
+	Min      *int64
+	Max      *int64
+	Currency string
+	IsPaid   bool
+}
+
+// ParseBudget reads budgets such as "Rp 5.000.000", "5-10 juta", "USD 500 - 1k" or "unpaid".
+// It reports false when nothing was recognised.
+func ParseBudget(text string) (ParsedBudget, bool) {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	budget := ParsedBudget{Currency: DefaultCurrency}
+	if lower == "" {
+		return budget, false
+	}
+
+	if unpaidPattern.MatchString(lower) {
+		return budget, true
+	}
+	for _, currency := range currencySymbols {
+		if strings.Contains(lower, currency.symbol) {
+			budget.Currency = currency.code
+			break
+		}
+	}
+
+	amounts := parseAmounts(lower)
+	if len(amounts) == 0 {
+		return budget, false
+	}
+
+	low, high := amounts[0], amounts[0]
+	if len(amounts) > 1 {
+		high = amounts[1]
+	}
+	if low > high {
+		low, high = high, low
+	}
+	if high <= 0 {
+		return budget, true
+	}
+
+	minValue, maxValue := int64(math.Round(low)), int64(math.Round(high))
+	budget.Min, budget.Max = &minValue, &maxValue
+	budget.IsPaid = true
+	return budget, true
+}
+
+// FormatBudget describes a typed budget, e.g. "IDR 5,000,000 - 10,000,000" or "Unpaid"
+func FormatBudget(min, max *int64, currency string, isPaid *bool) string {
+	if isPaid != nil && !*isPaid {
+		return "Unpaid"
+	}
+	if min == nil && max == nil {
+		return ""
+	}
+	if min == nil || max == nil || *min == *max {
+		value := min
+		if value == nil {
+			value = max
+		}
+		return fmt.Sprintf("%s %s", currency, formatThousands(*value))
+	}
+	return fmt.Sprintf("%s %s - %s", currency, formatThousands(*min), formatThousands(*max))
+}
+
+func formatThousands(value int64) string {
+	digits := strconv.FormatInt(value, 10)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+var periodPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*(?:-\s*(\d+(?:[.,]\d+)?)\s*)?(hari|days?|minggu|weeks?|bulan|months?|tahun|years?)\b`)
+
+var periodDays = map[string]float64{
+	"hari": 1, "day": 1, "days": 1,
+	"minggu": 7, "week": 7, "weeks": 7,
+	"bulan": 30, "month": 30, "months": 30,
+	"tahun": 365, "year": 365, "years": 365,
+}
+
+// ParseDurationDays reads durations such as "3 months", "2-3 bulan" or "10 days" as a number of
+// days, taking the upper end of a range
+func ParseDurationDays(text string) (int, bool) {
+	match := periodPattern.FindStringSubmatch(strings.ToLower(text))
+	if match == nil {
+		return 0, false
+	}
+	number := match[1]
+	if match[2] != "" {
+		number = match[2]
+	}
+	value, ok := parseAmount(number)
+	if !ok || value <= 0 {
+		return 0, false
+	}
+	return int(math.Round(value * periodDays[match[3]])), true
+}
+
+// DurationDaysBetween returns the whole days from start to end, or 0 when either is missing
+func DurationDaysBetween(start, end time.Time) int {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return int(math.Round(end.Sub(start).Hours() / 24))
+}
+
+// FormatDurationDays describes a number of days, e.g. "3 months", "2 weeks" or "10 days"
+func FormatDurationDays(days int) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case days <= 0:
+		return ""
+	case days%365 == 0:
+		return plural(days/365, "year")
+	case days%30 == 0:
+		return plural(days/30, "month")
+	case days%7 == 0:
+		return plural(days/7, "week")
+	}
+	return plural(days, "day")
+}
+
+var hoursPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*(?:-\s*(\d+(?:[.,]\d+)?)\s*)?(?:jam|hours?|hrs?|h)\b(?:\s*(?:/|per|a|sehari|seminggu|sebulan)?\s*(hari|day|minggu|week|bulan|month))?`)
+
+// ParseHoursPerWeek reads commitments such as "10 hours/week", "2-3 jam per hari" or "full time"
+// as hours per week, taking the upper end of a range. Hours per day assume a five-day week.
+func ParseHoursPerWeek(text string) (int, bool) {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "full time"), strings.Contains(lower, "full-time"), strings.Contains(lower, "fulltime"):
+		return 40, true
+	case strings.Contains(lower, "part time"), strings.Contains(lower, "part-time"), strings.Contains(lower, "parttime"):
+		return 20, true
+	}
+
+	match := hoursPattern.FindStringSubmatch(lower)
+	if match == nil {
+		return 0, false
+	}
+	number := match[1]
+	if match[2] != "" {
+		number = match[2]
+	}
+	value, ok := parseAmount(number)
+	if !ok || value <= 0 {
+		return 0, false
+	}
+
+	switch match[3] {
+	case "hari", "day":
+		value *= 5
+	case "bulan", "month":
+		value /= 4
+	}
+	hours := int(math.Round(value))
+	if hours < 1 || hours > MaxHoursPerWeek {
+		return 0, false
+	}
+	return hours, true
+}
+
+// MaxHoursPerWeek is the most hours a week can hold
+const MaxHoursPerWeek = 168
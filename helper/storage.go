@@ -0,0 +1,118 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFileNotFound is returned when a key does not exist in a storage backend
+var ErrFileNotFound = errors.New("file not found")
+
+// Storage is a backend that keeps uploaded files. Keys are slash separated paths such as
+// "storage/profiles/<name>.jpg", which is also what gets saved in the database.
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	Exists(key string) (bool, error)
+	// List returns every key under prefix
+	List(prefix string) ([]string, error)
+	// URL returns the public URL of a key
+	URL(key string) string
+	// Presign returns a URL that gives access to a key until it expires
+	Presign(key string, expires time.Duration) (string, error)
+}
+
+// Storage drivers selectable with STORAGE_DRIVER
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+var (
+	defaultStorage     Storage
+	defaultStorageOnce sync.Once
+)
+
+// GetStorage returns the backend configured with STORAGE_DRIVER, local by default
+func GetStorage() Storage {
+	defaultStorageOnce.Do(func() {
+		driver := os.Getenv("STORAGE_DRIVER")
+		if driver == "" {
+			driver = StorageDriverLocal
+		}
+		storage, err := NewStorage(driver)
+		if err != nil {
+			log.Fatalf("Failed to set up file storage: %v", err)
+		}
+		defaultStorage = storage
+	})
+	return defaultStorage
+}
+
+// NewStorage builds a storage backend from its environment configuration
+func NewStorage(driver string) (Storage, error) {
+	switch driver {
+	case StorageDriverLocal:
+		return NewLocalStorage("."), nil
+	case StorageDriverS3:
+		return NewS3StorageFromEnv()
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, use %s or %s", driver, StorageDriverLocal, StorageDriverS3)
+	}
+}
+
+// normalizeStorageKey turns a stored file path into a storage key
+func normalizeStorageKey(key string) string {
+	return strings.TrimPrefix(path.Clean(strings.ReplaceAll(key, "\\", "/")), "/")
+}
+
+// FileContentType guesses the content type of a stored file from its extension
+func FileContentType(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// ReadFile returns the contents of a file in the configured storage
+func ReadFile(filePath string) ([]byte, error) {
+	return GetStorage().Get(filePath)
+}
+
+// CopyStorage copies every file under prefix from one backend to another, skipping files the
+// destination already has, and returns how many were copied
+func CopyStorage(from, to Storage, prefix string) (int, error) {
+	keys, err := from.List(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	copied := 0
+	for _, key := range keys {
+		exists, err := to.Exists(key)
+		if err != nil {
+			return copied, fmt.Errorf("failed to check %s: %v", key, err)
+		}
+		if exists {
+			continue
+		}
+
+		data, err := from.Get(key)
+		if err != nil {
+			return copied, fmt.Errorf("failed to read %s: %v", key, err)
+		}
+		if err := to.Put(key, data, FileContentType(key)); err != nil {
+			return copied, fmt.Errorf("failed to write %s: %v", key, err)
+		}
+		copied++
+	}
+	return copied, nil
+}
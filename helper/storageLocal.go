@@ -0,0 +1,117 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps files on the local disk under Root. The app serves them through
+// app.Static, so this only works with a single instance.
+type LocalStorage struct {
+	Root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{Root: root}
+}
+
+// filePath maps a key to a path under Root, refusing keys that escape it
+func (s *LocalStorage) filePath(key string) (string, error) {
+	key = normalizeStorageKey(key)
+	if key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save file: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Get(key string) ([]byte, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) Exists(key string) (bool, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *LocalStorage) List(prefix string) ([]string, error) {
+	dir, err := s.filePath(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Root, filePath)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+		return nil
+	})
+	return keys, err
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return fmt.Sprintf("%s/%s", GetAppURL(), normalizeStorageKey(key))
+}
+
+// Presign returns the public URL; local files are served to anyone who knows their name
+func (s *LocalStorage) Presign(key string, expires time.Duration) (string, error) {
+	return s.URL(key), nil
+}
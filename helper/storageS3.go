@@ -0,0 +1,307 @@
+package helper
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm        = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// S3Storage keeps files in a bucket of any S3-compatible service (AWS S3, MinIO, R2, ...).
+// Requests are signed with AWS Signature Version 4.
+type S3Storage struct {
+	Endpoint  *url.URL
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is the base URL objects are served from, e.g. a CDN; defaults to the bucket URL
+	PublicURL string
+	// PathStyle addresses the bucket as endpoint/bucket instead of bucket.endpoint, as MinIO expects
+	PathStyle bool
+	Client    *http.Client
+}
+
+func NewS3Storage(endpoint, region, bucket, accessKey, secretKey string) (*S3Storage, error) {
+	if endpoint == "" || bucket == "" || accessKey == "" || secretKey == "" {
+		return nil, errors.New("S3 storage needs an endpoint, bucket, access key and secret key")
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3Storage{
+		Endpoint:  parsed,
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Client:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// NewS3StorageFromEnv configures S3 storage from the S3_* environment variables
+func NewS3StorageFromEnv() (*S3Storage, error) {
+	storage, err := NewS3Storage(
+		os.Getenv("S3_ENDPOINT"),
+		os.Getenv("S3_REGION"),
+		os.Getenv("S3_BUCKET"),
+		os.Getenv("S3_ACCESS_KEY"),
+		os.Getenv("S3_SECRET_KEY"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	storage.PublicURL = strings.TrimSuffix(os.Getenv("S3_PUBLIC_URL"), "/")
+	storage.PathStyle, _ = strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+	return storage, nil
+}
+
+// s3Escape percent-encodes a string the way Signature Version 4 expects; slashes are kept
+// when encoding paths
+func s3Escape(value string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery sorts and encodes query parameters for signing
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(key, false)+"="+s3Escape(value, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// objectURL returns the request URL of a key, or of the bucket itself for an empty key
+func (s *S3Storage) objectURL(key string, query url.Values) *url.URL {
+	u := *s.Endpoint
+	objectPath := "/" + normalizeStorageKey(key)
+	if key == "" {
+		objectPath = "/"
+	}
+	if s.PathStyle {
+		objectPath = "/" + s.Bucket + objectPath
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(s.Endpoint.Path, "/") + objectPath
+	u.RawPath = s3Escape(u.Path, true)
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+func (s *S3Storage) credentialScope(date string) string {
+	return date + "/" + s.Region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request made at amzDate
+func (s *S3Storage) signature(canonicalRequest, amzDate string) string {
+	date := amzDate[:8]
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		s.credentialScope(date),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// do sends a signed request and returns the body of a successful response
+func (s *S3Storage) do(method, key string, query url.Values, body []byte, contentType string) ([]byte, error) {
+	u := s.objectURL(key, query)
+	payloadHash := sha256Hex(body)
+	amzDate := time.Now().UTC().Format("20060102T150405Z")
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKey, s.credentialScope(amzDate[:8]), signedHeaders, s.signature(canonicalRequest, amzDate)))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 response: %v", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrFileNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("S3 %s %s failed: %s: %s", method, key, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	_, err := s.do(http.MethodPut, key, nil, data, contentType)
+	return err
+}
+
+func (s *S3Storage) Get(key string) ([]byte, error) {
+	return s.do(http.MethodGet, key, nil, nil, "")
+}
+
+func (s *S3Storage) Delete(key string) error {
+	_, err := s.do(http.MethodDelete, key, nil, nil, "")
+	if errors.Is(err, ErrFileNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *S3Storage) Exists(key string) (bool, error) {
+	_, err := s.do(http.MethodHead, key, nil, nil, "")
+	if errors.Is(err, ErrFileNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Storage) List(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {normalizeStorageKey(prefix)}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		data, err := s.do(http.MethodGet, "", query, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		if err := xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("invalid S3 list response: %v", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.PublicURL != "" {
+		return s.PublicURL + "/" + s3Escape(normalizeStorageKey(key), true)
+	}
+	return s.objectURL(key, nil).String()
+}
+
+// Presign returns a query-signed GET URL valid for expires, at most seven days
+func (s *S3Storage) Presign(key string, expires time.Duration) (string, error) {
+	if expires < time.Second || expires > s3MaxPresignExpiry {
+		return "", fmt.Errorf("presigned URLs must expire between 1s and %v", s3MaxPresignExpiry)
+	}
+
+	amzDate := time.Now().UTC().Format("20060102T150405Z")
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.AccessKey + "/" + s.credentialScope(amzDate[:8])},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.Itoa(int(expires.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u := s.objectURL(key, query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	u.RawQuery += "&X-Amz-Signature=" + s.signature(canonicalRequest, amzDate)
+	return u.String(), nil
+}
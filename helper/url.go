@@ -34,6 +34,15 @@ func GetFrontendURL() string {
 	return "http://localhost:3000"
 }
 
+// GetAppURL returns the public base URL of this API
+func GetAppURL() string {
+	appURL := os.Getenv("PUBLIC_APP_URL")
+	if appURL == "" {
+		appURL = os.Getenv("APP_URL")
+	}
+	return appURL
+}
+
 // BuildOAuthSuccessURL builds the OAuth success redirect URL with query parameters
 func BuildOAuthSuccessURL(token string, userID uint, userName, userEmail string) string {
 	frontendURL := GetFrontendURL()
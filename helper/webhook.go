@@ -0,0 +1,144 @@
+package helper
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// WebhookSignatureHeader is the header carrying the delivery signature
+const WebhookSignatureHeader = "X-Synergazing-Signature"
+
+// SignWebhookPayload returns the signature header value for body: "t=<unix>,v1=<hex HMAC-SHA256 of "<unix>.<body>">"
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyWebhookSignature checks a signature header against body and rejects timestamps older than tolerance
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration) error {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return errors.New("invalid signature timestamp")
+			}
+			timestamp = ts
+		case "v1":
+			signature = kv[1]
+		}
+	}
+
+	if timestamp == 0 || signature == "" {
+		return errors.New("malformed signature header")
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(timestamp, 0)) > tolerance {
+		return errors.New("signature timestamp is too old")
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(fmt.Sprintf("t=%d,v1=%s", timestamp, signature))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+var (
+	// ErrWebhookHostNotAllowed is returned for webhook URLs that point into the server's own network
+	ErrWebhookHostNotAllowed = errors.New("webhook URL must not point to a loopback, private or link-local address")
+	// ErrWebhookRedirect is returned when a webhook endpoint answers with a redirect
+	ErrWebhookRedirect = errors.New("webhook endpoint redirected; redirects are not followed")
+)
+
+// webhookAllowLocalhost lets webhooks reach loopback addresses, for testing against a local receiver.
+// Private and link-local addresses stay blocked.
+func webhookAllowLocalhost() bool {
+	return os.Getenv("WEBHOOK_ALLOW_LOCALHOST") == "true"
+}
+
+// webhookIPAllowed reports whether webhooks may be delivered to ip
+func webhookIPAllowed(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return webhookAllowLocalhost()
+	}
+	return !(ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// ValidateWebhookURL checks that urlString is an http or https URL whose host resolves only to
+// addresses webhooks may be delivered to
+func ValidateWebhookURL(urlString string) error {
+	if !IsValidHTTPURL(urlString) {
+		return errors.New("webhook URL must be a valid http or https URL")
+	}
+	parsed, _ := url.Parse(urlString)
+	host := parsed.Hostname()
+	if host == "" {
+		return errors.New("webhook URL must have a host")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("webhook host %s could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if !webhookIPAllowed(addr.IP) {
+			return ErrWebhookHostNotAllowed
+		}
+	}
+	return nil
+}
+
+// NewWebhookHTTPClient returns the client webhooks are delivered with. The address is checked
+// again when dialing, after DNS resolution, so a host that later resolves to an internal address
+// is still refused. Redirects are not followed, and proxies from the environment are not used,
+// since they would hide the real destination from the check.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !webhookIPAllowed(ip) {
+				return ErrWebhookHostNotAllowed
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrWebhookRedirect
+		},
+	}
+}
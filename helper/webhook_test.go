@@ -0,0 +1,107 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookSignatureRoundTrip(t *testing.T) {
+	body := []byte(`{"event":"ping","project_id":1}`)
+	header := SignWebhookPayload("whsec_test", time.Now().Unix(), body)
+
+	if err := VerifyWebhookSignature("whsec_test", header, body, 5*time.Minute); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+	if err := VerifyWebhookSignature("whsec_test", header, []byte(`{"event":"ping","project_id":2}`), 5*time.Minute); err == nil {
+		t.Fatal("tampered body accepted")
+	}
+	if err := VerifyWebhookSignature("whsec_other", header, body, 5*time.Minute); err == nil {
+		t.Fatal("signature accepted with the wrong secret")
+	}
+
+	old := SignWebhookPayload("whsec_test", time.Now().Add(-time.Hour).Unix(), body)
+	if err := VerifyWebhookSignature("whsec_test", old, body, 5*time.Minute); err == nil {
+		t.Fatal("stale signature accepted")
+	}
+}
+
+func TestValidateWebhookURLRefusesInternalHosts(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "")
+
+	for _, target := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		if err := ValidateWebhookURL(target); !errors.Is(err, ErrWebhookHostNotAllowed) {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ErrWebhookHostNotAllowed", target, err)
+		}
+	}
+
+	if err := ValidateWebhookURL("ftp://example.com/hook"); err == nil {
+		t.Error("non-http URL accepted")
+	}
+}
+
+func TestValidateWebhookURLAllowLocalhost(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+
+	if err := ValidateWebhookURL("http://127.0.0.1/hook"); err != nil {
+		t.Errorf("loopback refused with WEBHOOK_ALLOW_LOCALHOST=true: %v", err)
+	}
+	// Only loopback is opened up, private ranges stay blocked
+	if err := ValidateWebhookURL("http://10.0.0.1/hook"); !errors.Is(err, ErrWebhookHostNotAllowed) {
+		t.Errorf("private address allowed with WEBHOOK_ALLOW_LOCALHOST=true: %v", err)
+	}
+}
+
+func TestWebhookHTTPClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "")
+	client := NewWebhookHTTPClient(time.Second)
+	if _, err := client.Post(server.URL, "application/json", strings.NewReader("{}")); !errors.Is(err, ErrWebhookHostNotAllowed) {
+		t.Fatalf("dial to loopback = %v, want ErrWebhookHostNotAllowed", err)
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("dial to loopback with WEBHOOK_ALLOW_LOCALHOST=true failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestWebhookHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+
+	followed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/target" {
+			followed = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Redirect(w, r, "/target", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	client := NewWebhookHTTPClient(time.Second)
+	if _, err := client.Post(server.URL+"/hook", "application/json", strings.NewReader("{}")); !errors.Is(err, ErrWebhookRedirect) {
+		t.Fatalf("redirect = %v, want ErrWebhookRedirect", err)
+	}
+	if followed {
+		t.Fatal("redirect was followed")
+	}
+}
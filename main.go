@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
@@ -57,14 +58,35 @@ func main() {
 			log.Println("Cleaning up expired OTPs...")
 			migrations.CleanupExpiredOTPs(db)
 			return
+		case "image-variants":
+			log.Println("Generating missing image variants...")
+			if err := migrations.GenerateMissingImageVariants(db); err != nil {
+				log.Fatalf("Failed to generate image variants: %v", err)
+			}
+			return
+		case "geocode":
+			log.Println("Geocoding project and profile locations...")
+			if err := migrations.GeocodeLocations(db); err != nil {
+				log.Fatalf("Failed to geocode locations: %v", err)
+			}
+			return
+		case "migrate-storage":
+			if len(os.Args) < 4 {
+				log.Fatal("Please provide the source and target storage: e.g., `go run main.go migrate-storage local s3`")
+			}
+			if err := migrations.MigrateStorage(os.Args[2], os.Args[3]); err != nil {
+				log.Fatalf("Failed to migrate storage: %v", err)
+			}
+			return
 		}
 	}
 
 	log.Println("Running with auto migration...")
 	migrations.AutoMigrate(db)
 
-	go startOTPCleanupRoutine()
-	go startNotificationRoutine()
+	scheduler := service.NewSchedulerService(db)
+	go scheduler.Start(context.Background())
+	go startOutboxWorker()
 
 	app := fiber.New()
 
@@ -114,12 +136,25 @@ func main() {
 	})
 	routes.SetupAuthRoutes(app)
 	routes.SetupProjectRoutes(app)
+	routes.SetupCalendarRoutes(app)
+	routes.SetupTaxonomyRoutes(app)
 	routes.SetupProfileRoutes(app)
+	routes.SetupFileAccessRoutes(app)
 	routes.SetupUserRoutes(app)
 	routes.SkillRoutes(app)
 	routes.SetupChatRoutes(app)
 	routes.SetupNotificationRoutes(app)
 	routes.SetupProjectMemberRoutes(app)
+	routes.SetupProjectAccessRoutes(app)
+	routes.SetupProjectTaskRoutes(app)
+	routes.SetupProjectMilestoneRoutes(app)
+	routes.SetupProjectAnnouncementRoutes(app)
+	routes.SetupProjectQuestionRoutes(app)
+	routes.SetupProjectAnalyticsRoutes(app)
+	routes.SetupWebhookRoutes(app)
+	routes.SetupReminderRoutes(app)
+	routes.SetupOutboxRoutes(app)
+	routes.SetupSchedulerRoutes(app)
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Hello World - GORM Connected!")
@@ -135,38 +170,24 @@ func main() {
 
 }
 
-func startOTPCleanupRoutine() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-
-	otpService := service.NewOTPService()
-	otpService.CleanupExpiredOTPs()
-	log.Println("Initial OTP cleanup completed")
-
-	for range ticker.C {
-		otpService.CleanupExpiredOTPs()
-	}
-}
-
-func startNotificationRoutine() {
-	ticker := time.NewTicker(24 * time.Hour) // Check daily
+func startOutboxWorker() {
+	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
 	db := config.GetDB()
-	notificationService := service.NewNotificationService(db)
-
-	// Initial check
-	if err := notificationService.CheckAndNotifyApproachingDeadlines(); err != nil {
-		log.Printf("Error in initial deadline notification check: %v", err)
-	} else {
-		log.Println("Initial deadline notification check completed")
-	}
+	outboxService := service.NewOutboxService(db)
 
 	for range ticker.C {
-		if err := notificationService.CheckAndNotifyApproachingDeadlines(); err != nil {
-			log.Printf("Error checking approaching deadlines: %v", err)
-		} else {
-			log.Println("Deadline notification check completed")
+		// Keep draining while full batches come back
+		for {
+			processed, err := outboxService.ProcessPending(50)
+			if err != nil {
+				log.Printf("Error processing outbox: %v", err)
+				break
+			}
+			if processed < 50 {
+				break
+			}
 		}
 	}
 }
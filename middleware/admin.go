@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/model"
+)
+
+// AdminMiddleware allows the request through only when the authenticated user has the admin role.
+// It must run after AuthMiddleware.
+func AdminMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "Missing authenticated user",
+			})
+		}
+
+		var user model.Users
+		if err := config.GetDB().Preload("Role").First(&user, userID).Error; err != nil {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "User not found",
+			})
+		}
+
+		for _, role := range user.Role {
+			if role.Name == "admin" {
+				return c.Next()
+			}
+		}
+
+		return c.Status(403).JSON(fiber.Map{
+			"error": "Admin access required",
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/helper"
+)
+
+// OptionalAuthMiddleware identifies the user on public routes: a valid bearer token sets
+// user_id and user_email like AuthMiddleware, while a missing or invalid one is ignored.
+func OptionalAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return c.Next()
+		}
+
+		claims, err := helper.VerifyJWTToken(strings.TrimPrefix(authHeader, "Bearer "))
+		if err == nil {
+			c.Locals("user_id", claims.UserID)
+			c.Locals("user_email", claims.Email)
+		}
+		return c.Next()
+	}
+}
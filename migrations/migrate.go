@@ -3,44 +3,99 @@ package migrations
 import (
 	"fmt"
 	"log"
+	"path"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
 	"synergazing.com/synergazing/model"
+	"synergazing.com/synergazing/service"
 )
 
 var modelMap = map[string]interface{}{
-	"users":                &model.Users{},
-	"profiles":             &model.Profiles{},
-	"role":                 &model.Role{},
-	"permission":           &model.Permission{},
-	"socialauth":           &model.SocialAuth{},
-	"skill":                &model.Skill{},
-	"userskill":            &model.UserSkill{},
-	"project":              &model.Project{},
-	"projectcondition":     &model.ProjectCondition{},
-	"tag":                  &model.Tag{},
-	"benefit":              &model.Benefit{},
-	"timeline":             &model.Timeline{},
-	"projecttag":           &model.ProjectTag{},
-	"projectbenefit":       &model.ProjectBenefit{},
-	"projecttimeline":      &model.ProjectTimeline{},
-	"projectrequiredskill": &model.ProjectRequiredSkill{},
-	"projectrole":          &model.ProjectRole{},
-	"projectroleskill":     &model.ProjectRoleSkill{},
-	"projectmember":        &model.ProjectMember{},
-	"projectmemberskill":   &model.ProjectMemberSkill{},
-	"chat":                 &model.Chat{},
-	"chats":                &model.Chat{},
-	"message":              &model.Message{},
-	"messages":             &model.Message{},
-	"otp":                  &model.OTP{},
-	"otps":                 &model.OTP{},
-	"notification":         &model.Notification{},
-	"notifications":        &model.Notification{},
-	"projectapplication":   &model.ProjectApplication{},
-	"projectapplications":  &model.ProjectApplication{},
+	"users":                 &model.Users{},
+	"profiles":              &model.Profiles{},
+	"role":                  &model.Role{},
+	"permission":            &model.Permission{},
+	"socialauth":            &model.SocialAuth{},
+	"skill":                 &model.Skill{},
+	"userskill":             &model.UserSkill{},
+	"project":               &model.Project{},
+	"projectcondition":      &model.ProjectCondition{},
+	"tag":                   &model.Tag{},
+	"benefit":               &model.Benefit{},
+	"timeline":              "timelines",
+	"projecttag":            &model.ProjectTag{},
+	"projectbenefit":        &model.ProjectBenefit{},
+	"projecttimeline":       "project_timelines",
+	"projectrequiredskill":  &model.ProjectRequiredSkill{},
+	"projectrole":           &model.ProjectRole{},
+	"projectroleskill":      &model.ProjectRoleSkill{},
+	"projectmember":         &model.ProjectMember{},
+	"projectmemberskill":    &model.ProjectMemberSkill{},
+	"chat":                  &model.Chat{},
+	"chats":                 &model.Chat{},
+	"message":               &model.Message{},
+	"messages":              &model.Message{},
+	"otp":                   &model.OTP{},
+	"otps":                  &model.OTP{},
+	"notification":          &model.Notification{},
+	"notifications":         &model.Notification{},
+	"projectapplication":    &model.ProjectApplication{},
+	"projectapplications":   &model.ProjectApplication{},
+	"outbox":                &model.OutboxMessage{},
+	"outboxmessages":        &model.OutboxMessage{},
+	"scheduledjob":          &model.ScheduledJob{},
+	"scheduledjobs":         &model.ScheduledJob{},
+	"jobrun":                &model.JobRun{},
+	"jobruns":               &model.JobRun{},
+	"webhookendpoint":       &model.WebhookEndpoint{},
+	"webhookendpoints":      &model.WebhookEndpoint{},
+	"webhookdelivery":       &model.WebhookDelivery{},
+	"webhookdeliveries":     &model.WebhookDelivery{},
+	"reminder":              &model.ProjectReminderSetting{},
+	"remindersettings":      &model.ProjectReminderSetting{},
+	"reminderlog":           &model.ProjectReminderLog{},
+	"reminderlogs":          &model.ProjectReminderLog{},
+	"statushistory":         &model.ProjectStatusHistory{},
+	"statushistories":       &model.ProjectStatusHistory{},
+	"projectchange":         &model.ProjectChange{},
+	"projectchanges":        &model.ProjectChange{},
+	"projecttemplate":       &model.ProjectTemplate{},
+	"projecttemplates":      &model.ProjectTemplate{},
+	"projectaccess":         &model.ProjectAccess{},
+	"projectaccesses":       &model.ProjectAccess{},
+	"projecttask":           &model.ProjectTask{},
+	"projecttasks":          &model.ProjectTask{},
+	"taskcomment":           &model.ProjectTaskComment{},
+	"taskcomments":          &model.ProjectTaskComment{},
+	"projectactivity":       &model.ProjectActivity{},
+	"projectactivities":     &model.ProjectActivity{},
+	"milestone":             &model.ProjectMilestone{},
+	"milestones":            &model.ProjectMilestone{},
+	"milestonedependency":   &model.ProjectMilestoneDependency{},
+	"milestonedependencies": &model.ProjectMilestoneDependency{},
+	"announcement":          &model.ProjectAnnouncement{},
+	"announcements":         &model.ProjectAnnouncement{},
+	"announcementfile":      &model.ProjectAnnouncementAttachment{},
+	"announcementfiles":     &model.ProjectAnnouncementAttachment{},
+	"announcementcomment":   &model.ProjectAnnouncementComment{},
+	"announcementcomments":  &model.ProjectAnnouncementComment{},
+	"announcementreaction":  &model.ProjectAnnouncementReaction{},
+	"announcementreactions": &model.ProjectAnnouncementReaction{},
+	"question":              &model.ProjectQuestion{},
+	"questions":             &model.ProjectQuestion{},
+	"fileaccesslog":         &model.FileAccessLog{},
+	"fileaccesslogs":        &model.FileAccessLog{},
+	"projectview":           &model.ProjectView{},
+	"projectviews":          &model.ProjectView{},
+	"savedproject":          &model.SavedProject{},
+	"savedprojects":         &model.SavedProject{},
+	"calendarfeed":          &model.CalendarFeed{},
+	"calendarfeeds":         &model.CalendarFeed{},
+	"taxonomyalias":         &model.TaxonomyAlias{},
+	"taxonomyaliases":       &model.TaxonomyAlias{},
 }
 
 func AutoMigrate(db *gorm.DB) {
@@ -51,27 +106,60 @@ func AutoMigrate(db *gorm.DB) {
 		log.Fatalf("Failed to create custom enums: %v", err)
 	}
 
+	// The typed project terms are parsed from the free-text ones when their columns are first added
+	backfillTerms := db.Migrator().HasTable(&model.Project{}) && !db.Migrator().HasColumn(&model.Project{}, "hours_per_week")
+	// Likewise, locations are geocoded when the structured location columns are first added
+	geocodeLocations := db.Migrator().HasTable(&model.Project{}) && !db.Migrator().HasColumn(&model.Project{}, "location_mode")
+
 	err := db.AutoMigrate(
-		&model.Users{}, &model.Role{}, &model.Permission{}, &model.Skill{}, &model.Tag{}, &model.Benefit{}, &model.Timeline{}, &model.OTP{},
+		&model.Users{}, &model.Role{}, &model.Permission{}, &model.Skill{}, &model.Tag{}, &model.Benefit{}, &model.OTP{}, &model.OutboxMessage{}, &model.ScheduledJob{}, &model.JobRun{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to migrate primary tables: %v", err)
 	}
 
 	err = db.AutoMigrate(
-		&model.Profiles{}, &model.SocialAuth{}, &model.UserSkill{}, &model.Project{}, &model.Chat{}, &model.Notification{},
+		&model.Profiles{}, &model.SocialAuth{}, &model.UserSkill{}, &model.Project{}, &model.Chat{}, &model.Notification{}, &model.ProjectTemplate{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to migrate dependent tables: %v", err)
 	}
 
 	err = db.AutoMigrate(
-		&model.ProjectCondition{}, &model.ProjectRequiredSkill{}, &model.ProjectTag{}, &model.ProjectBenefit{}, &model.ProjectTimeline{}, &model.ProjectRole{}, &model.ProjectRoleSkill{}, &model.ProjectMember{}, &model.ProjectMemberSkill{}, &model.Message{}, &model.ProjectApplication{},
+		&model.ProjectCondition{}, &model.ProjectRequiredSkill{}, &model.ProjectTag{}, &model.ProjectBenefit{}, &model.ProjectMilestone{}, &model.ProjectMilestoneDependency{}, &model.ProjectRole{}, &model.ProjectRoleSkill{}, &model.ProjectMember{}, &model.ProjectMemberSkill{}, &model.Message{}, &model.ProjectApplication{}, &model.WebhookEndpoint{}, &model.WebhookDelivery{}, &model.ProjectReminderSetting{}, &model.ProjectReminderLog{}, &model.ProjectStatusHistory{}, &model.ProjectChange{}, &model.ProjectAccess{}, &model.ProjectTask{}, &model.ProjectTaskComment{}, &model.ProjectActivity{}, &model.ProjectAnnouncement{}, &model.ProjectAnnouncementAttachment{}, &model.ProjectAnnouncementComment{}, &model.ProjectAnnouncementReaction{}, &model.ProjectQuestion{}, &model.FileAccessLog{}, &model.ProjectView{}, &model.SavedProject{}, &model.CalendarFeed{}, &model.TaxonomyAlias{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to migrate final tables: %v", err)
 	}
 
+	if err := BackfillNotificationEventTimes(db); err != nil {
+		log.Fatalf("Failed to backfill notification event times: %v", err)
+	}
+
+	if err := MigrateTimelinesToMilestones(db); err != nil {
+		log.Fatalf("Failed to migrate project timelines to milestones: %v", err)
+	}
+
+	if err := MoveCVsToPrivateStorage(db); err != nil {
+		log.Fatalf("Failed to move CVs to private storage: %v", err)
+	}
+
+	if err := MoveAnnouncementAttachmentsToPrivateStorage(db); err != nil {
+		log.Fatalf("Failed to move announcement attachments to private storage: %v", err)
+	}
+
+	if backfillTerms {
+		if err := BackfillProjectTerms(db); err != nil {
+			log.Fatalf("Failed to backfill project budgets and durations: %v", err)
+		}
+	}
+
+	if geocodeLocations {
+		if err := GeocodeLocations(db); err != nil {
+			log.Fatalf("Failed to geocode locations: %v", err)
+		}
+	}
+
 	fmt.Println("Success run Auto-migrate")
 }
 
@@ -95,7 +183,7 @@ func MigrateFresh(db *gorm.DB) {
 	}
 
 	modelsToDrop := []interface{}{
-		&model.ProjectMemberSkill{}, &model.ProjectMember{}, &model.ProjectRoleSkill{}, &model.ProjectCondition{}, &model.ProjectRequiredSkill{}, &model.ProjectTag{}, &model.ProjectBenefit{}, &model.ProjectTimeline{}, &model.ProjectRole{}, &model.Message{}, &model.Notification{}, &model.ProjectApplication{},
+		&model.TaxonomyAlias{}, &model.CalendarFeed{}, &model.SavedProject{}, &model.ProjectView{}, &model.FileAccessLog{}, &model.ProjectQuestion{}, &model.ProjectAnnouncementReaction{}, &model.ProjectAnnouncementComment{}, &model.ProjectAnnouncementAttachment{}, &model.ProjectAnnouncement{}, &model.ProjectMilestoneDependency{}, &model.ProjectActivity{}, &model.ProjectTaskComment{}, &model.ProjectTask{}, &model.ProjectAccess{}, &model.WebhookDelivery{}, &model.WebhookEndpoint{}, &model.ProjectReminderSetting{}, &model.ProjectReminderLog{}, &model.ProjectStatusHistory{}, &model.ProjectChange{}, &model.ProjectMemberSkill{}, &model.ProjectMember{}, &model.ProjectRoleSkill{}, &model.ProjectCondition{}, &model.ProjectRequiredSkill{}, &model.ProjectTag{}, &model.ProjectBenefit{}, &model.ProjectMilestone{}, "project_timelines", &model.ProjectRole{}, &model.Message{}, &model.Notification{}, &model.ProjectApplication{},
 	}
 	if err := tx.Migrator().DropTable(modelsToDrop...); err != nil {
 		tx.Rollback()
@@ -103,7 +191,7 @@ func MigrateFresh(db *gorm.DB) {
 	}
 
 	modelsToDrop = []interface{}{
-		&model.Profiles{}, &model.SocialAuth{}, &model.UserSkill{}, &model.Project{}, &model.Chat{}, &model.OTP{},
+		&model.Profiles{}, &model.SocialAuth{}, &model.UserSkill{}, &model.Project{}, &model.Chat{}, &model.OTP{}, &model.ProjectTemplate{}, &model.OutboxMessage{}, &model.ScheduledJob{}, &model.JobRun{},
 	}
 	if err := tx.Migrator().DropTable(modelsToDrop...); err != nil {
 		tx.Rollback()
@@ -111,7 +199,7 @@ func MigrateFresh(db *gorm.DB) {
 	}
 
 	modelsToDrop = []interface{}{
-		&model.Users{}, &model.Role{}, &model.Permission{}, &model.Skill{}, &model.Tag{}, &model.Benefit{}, &model.Timeline{},
+		&model.Users{}, &model.Role{}, &model.Permission{}, &model.Skill{}, &model.Tag{}, &model.Benefit{}, "timelines",
 	}
 	if err := tx.Migrator().DropTable(modelsToDrop...); err != nil {
 		tx.Rollback()
@@ -188,3 +276,326 @@ func CleanupExpiredOTPs(db *gorm.DB) {
 		log.Println("No expired OTP records found")
 	}
 }
+
+// BackfillNotificationEventTimes sets last_event_at on notifications created before grouping existed
+func BackfillNotificationEventTimes(db *gorm.DB) error {
+	return db.Exec("UPDATE notifications SET last_event_at = created_at WHERE last_event_at IS NULL OR last_event_at = '0001-01-01 00:00:00'").Error
+}
+
+// MigrateTimelinesToMilestones moves the legacy project_timelines rows, which pointed at globally
+// shared timeline names, into project-owned milestones and re-links tasks to them. The legacy
+// tables are dropped afterwards, so this only does work once.
+func MigrateTimelinesToMilestones(db *gorm.DB) error {
+	if !db.Migrator().HasTable("project_timelines") {
+		return nil
+	}
+
+	fmt.Println("Migrating project timelines to milestones...")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Due dates were only added to timeline items shortly before milestones replaced them, so
+		// databases that skipped that version have no due_date column
+		dueDate, order := "NULL::timestamptz", "t.id"
+		if tx.Migrator().HasColumn("project_timelines", "due_date") {
+			dueDate, order = "pt.due_date", "pt.due_date NULLS LAST, t.id"
+		}
+
+		// Items of projects that were purged are left behind
+		err := tx.Exec(`INSERT INTO project_milestones (project_id, name, description, position, due_date, status, percent_complete, completed_at, created_at, updated_at)
+			SELECT pt.project_id, t.name, '',
+				ROW_NUMBER() OVER (PARTITION BY pt.project_id ORDER BY ` + order + `) - 1,
+				` + dueDate + `, pt.timeline_status,
+				CASE WHEN pt.timeline_status = 'done' THEN 100 ELSE 0 END,
+				CASE WHEN pt.timeline_status = 'done' THEN NOW() END,
+				NOW(), NOW()
+			FROM project_timelines pt
+			JOIN timelines t ON t.id = pt.timeline_id
+			WHERE pt.project_id IN (SELECT id FROM projects)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to copy timeline items: %v", err)
+		}
+
+		if tx.Migrator().HasColumn("project_tasks", "timeline_id") {
+			err = tx.Exec(`UPDATE project_tasks SET milestone_id = m.id
+				FROM timelines t, project_milestones m
+				WHERE t.id = project_tasks.timeline_id
+				AND m.project_id = project_tasks.project_id
+				AND m.name = t.name
+				AND project_tasks.milestone_id IS NULL`).Error
+			if err != nil {
+				return fmt.Errorf("failed to re-link tasks: %v", err)
+			}
+
+			if err := tx.Exec("ALTER TABLE project_tasks DROP COLUMN timeline_id").Error; err != nil {
+				return fmt.Errorf("failed to drop project_tasks.timeline_id: %v", err)
+			}
+		}
+
+		if err := tx.Migrator().DropTable("project_timelines", "timelines"); err != nil {
+			return fmt.Errorf("failed to drop legacy timeline tables: %v", err)
+		}
+
+		fmt.Println("Project timelines migrated to milestones")
+		return nil
+	})
+}
+
+// MoveCVsToPrivateStorage moves CVs uploaded under the publicly served storage/cv directory into
+// private storage, so they can only be downloaded through signed links. Moved rows no longer
+// match, so this only does work once.
+func MoveCVsToPrivateStorage(db *gorm.DB) error {
+	var profiles []model.Profiles
+	if err := db.Select("id", "cv_file").Where("cv_file LIKE ?", "storage/cv/%").Find(&profiles).Error; err != nil {
+		return fmt.Errorf("failed to load CVs: %v", err)
+	}
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Moving %d CVs to private storage...\n", len(profiles))
+
+	storage := helper.GetStorage()
+	for _, profile := range profiles {
+		privatePath := helper.PrivateFilePrefix + "cv/" + path.Base(profile.CVFile)
+
+		data, err := storage.Get(profile.CVFile)
+		if err != nil {
+			// The file is gone, so the link was already broken
+			log.Printf("Clearing missing CV %s: %v", profile.CVFile, err)
+			privatePath = ""
+		} else if err := storage.Put(privatePath, data, "application/pdf"); err != nil {
+			return fmt.Errorf("failed to move %s: %v", profile.CVFile, err)
+		}
+
+		if err := db.Model(&model.Profiles{}).Where("id = ?", profile.ID).Update("cv_file", privatePath).Error; err != nil {
+			return fmt.Errorf("failed to update CV of profile %d: %v", profile.ID, err)
+		}
+		storage.Delete(profile.CVFile)
+	}
+	return nil
+}
+
+// MoveAnnouncementAttachmentsToPrivateStorage moves attachments uploaded under the publicly served
+// storage/announcements directory into private storage, so only project members can download
+// them. Resized variants of images move along. Moved rows no longer match, so this only does
+// work once.
+func MoveAnnouncementAttachmentsToPrivateStorage(db *gorm.DB) error {
+	var attachments []model.ProjectAnnouncementAttachment
+	if err := db.Select("id", "file_path").Where("file_path LIKE ?", "storage/announcements/%").Find(&attachments).Error; err != nil {
+		return fmt.Errorf("failed to load announcement attachments: %v", err)
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Moving %d announcement attachments to private storage...\n", len(attachments))
+
+	storage := helper.GetStorage()
+	for _, attachment := range attachments {
+		privatePath := helper.PrivateFilePrefix + "announcements/" + path.Base(attachment.FilePath)
+
+		files := map[string]string{attachment.FilePath: privatePath}
+		for _, variant := range helper.ImageVariants() {
+			files[helper.ImageVariantPath(attachment.FilePath, variant)] = helper.ImageVariantPath(privatePath, variant)
+		}
+		for from, to := range files {
+			data, err := storage.Get(from)
+			if err != nil {
+				// PDFs have no variants, and a missing file was already a broken link
+				continue
+			}
+			if err := storage.Put(to, data, helper.FileContentType(to)); err != nil {
+				return fmt.Errorf("failed to move %s: %v", from, err)
+			}
+		}
+
+		if err := db.Model(&model.ProjectAnnouncementAttachment{}).Where("id = ?", attachment.ID).Update("file_path", privatePath).Error; err != nil {
+			return fmt.Errorf("failed to update attachment %d: %v", attachment.ID, err)
+		}
+		helper.DeleteFile(attachment.FilePath)
+	}
+	return nil
+}
+
+// BackfillProjectTerms fills the typed budget, duration and weekly hours of existing projects by
+// parsing their free-text budget, duration and time commitment. Durations are taken from the start
+// and end dates when both are set. Fields that are already filled are left alone, and text that
+// can't be parsed leaves the typed field empty.
+func BackfillProjectTerms(db *gorm.DB) error {
+	fmt.Println("Backfilling project budgets, durations and weekly hours...")
+
+	var projects []model.Project
+	parsed := 0
+	err := db.Unscoped().
+		Select("id", "budget", "budget_min", "budget_max", "budget_currency", "is_paid", "duration", "duration_days", "start_date", "end_date", "time_commitment", "hours_per_week").
+		FindInBatches(&projects, 200, func(tx *gorm.DB, batch int) error {
+			for _, project := range projects {
+				updates := map[string]interface{}{}
+
+				if project.BudgetMin == nil && project.BudgetMax == nil && project.IsPaid == nil {
+					if budget, ok := helper.ParseBudget(project.Budget); ok {
+						updates["budget_min"] = budget.Min
+						updates["budget_max"] = budget.Max
+						updates["budget_currency"] = budget.Currency
+						updates["is_paid"] = budget.IsPaid
+					}
+				}
+				if project.DurationDays == 0 {
+					days := helper.DurationDaysBetween(project.StartDate, project.EndDate)
+					if days == 0 {
+						days, _ = helper.ParseDurationDays(project.Duration)
+					}
+					if days > 0 {
+						updates["duration_days"] = days
+					}
+				}
+				if project.HoursPerWeek == nil {
+					if hours, ok := helper.ParseHoursPerWeek(project.TimeCommitment); ok {
+						updates["hours_per_week"] = hours
+					}
+				}
+
+				if len(updates) == 0 {
+					continue
+				}
+				if err := db.Unscoped().Model(&model.Project{}).Where("id = ?", project.ID).UpdateColumns(updates).Error; err != nil {
+					return fmt.Errorf("failed to update project %d: %v", project.ID, err)
+				}
+				parsed++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backfilled terms of %d projects\n", parsed)
+	return nil
+}
+
+// GeocodeLocations fills the city, region, coordinates and location mode of projects and
+// profiles from their free-text location. Only rows with a location but none of the structured
+// fields are touched, so it can be re-run after plugging in a better geocoder.
+func GeocodeLocations(db *gorm.DB) error {
+	const unresolved = "location <> '' AND city = '' AND region = '' AND latitude IS NULL AND location_mode = ''"
+
+	fmt.Println("Geocoding project and profile locations...")
+
+	var projects []model.Project
+	resolved := 0
+	err := db.Unscoped().Select("id", "location").Where(unresolved).
+		FindInBatches(&projects, 200, func(tx *gorm.DB, batch int) error {
+			for _, project := range projects {
+				location, err := service.ResolveGeoLocation(project.Location, service.GeoLocationInput{})
+				if err != nil {
+					return err
+				}
+				if location == (model.GeoLocation{}) {
+					continue
+				}
+				if err := db.Unscoped().Model(&model.Project{}).Where("id = ?", project.ID).UpdateColumns(geoLocationColumns(location)).Error; err != nil {
+					return fmt.Errorf("failed to update project %d: %v", project.ID, err)
+				}
+				resolved++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	var profiles []model.Profiles
+	err = db.Select("id", "location").Where(unresolved).
+		FindInBatches(&profiles, 200, func(tx *gorm.DB, batch int) error {
+			for _, profile := range profiles {
+				location, err := service.ResolveProfileGeoLocation(profile.Location, service.GeoLocationInput{})
+				if err != nil {
+					return err
+				}
+				if location == (model.GeoLocation{}) {
+					continue
+				}
+				if err := db.Model(&model.Profiles{}).Where("id = ?", profile.ID).UpdateColumns(geoLocationColumns(location)).Error; err != nil {
+					return fmt.Errorf("failed to update profile %d: %v", profile.ID, err)
+				}
+				resolved++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Geocoded %d locations\n", resolved)
+	return nil
+}
+
+func geoLocationColumns(location model.GeoLocation) map[string]interface{} {
+	return map[string]interface{}{
+		"city":          location.City,
+		"region":        location.Region,
+		"latitude":      location.Latitude,
+		"longitude":     location.Longitude,
+		"location_mode": location.LocationMode,
+	}
+}
+
+// GenerateMissingImageVariants creates the resized variants of profile pictures and project
+// covers uploaded before the image pipeline existed. Images that already have variants are skipped.
+func GenerateMissingImageVariants(db *gorm.DB) error {
+	var paths []string
+	if err := db.Model(&model.Profiles{}).Where("profile_picture <> ''").Pluck("profile_picture", &paths).Error; err != nil {
+		return fmt.Errorf("failed to load profile pictures: %v", err)
+	}
+	var covers []string
+	if err := db.Model(&model.Project{}).Where("picture_url <> ''").Pluck("picture_url", &covers).Error; err != nil {
+		return fmt.Errorf("failed to load project covers: %v", err)
+	}
+	paths = append(paths, covers...)
+
+	storage := helper.GetStorage()
+	generated := 0
+	for _, path := range paths {
+		if exists, err := storage.Exists(helper.ImageVariantPath(path, helper.ImageVariantThumbnail)); err == nil && exists {
+			continue
+		}
+		if err := helper.GenerateImageVariants(path); err != nil {
+			log.Printf("Skipping image %s: %v", path, err)
+			continue
+		}
+		generated++
+	}
+
+	fmt.Printf("Generated variants for %d of %d images\n", generated, len(paths))
+	return nil
+}
+
+// MigrateStorage copies every uploaded file, public and private, from one storage driver to another. Stored paths stay
+// the same, so after switching STORAGE_DRIVER the database needs no changes. Files already present
+// in the target are skipped, so an interrupted run can be repeated.
+func MigrateStorage(from, to string) error {
+	if from == to {
+		return fmt.Errorf("source and target storage are both %s", from)
+	}
+	source, err := helper.NewStorage(from)
+	if err != nil {
+		return err
+	}
+	target, err := helper.NewStorage(to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copying files from %s to %s storage...\n", from, to)
+	copied := 0
+	for _, prefix := range []string{"storage", strings.TrimSuffix(helper.PrivateFilePrefix, "/")} {
+		count, err := helper.CopyStorage(source, target, prefix)
+		copied += count
+		if err != nil {
+			return fmt.Errorf("stopped after copying %d files: %v", copied, err)
+		}
+	}
+
+	fmt.Printf("Copied %d files\n", copied)
+	return nil
+}
@@ -18,6 +18,9 @@ type Profiles struct {
 	Interests string `json:"interests" gorm:"type:text"`
 	Academic  string `json:"academic" gorm:"type:text"`
 
+	// GeoLocation is where the user is based and the location mode they prefer
+	GeoLocation
+
 	WebsiteURL   string `json:"website_url" gorm:"type:text"`
 	GithubURL    string `json:"github_url" gorm:"type:text"`
 	LinkedInURL  string `json:"linkedin_url" gorm:"type:text"`
@@ -37,12 +40,14 @@ func (Profiles) TableName() string {
 func (p Profiles) MarshalJSON() ([]byte, error) {
 	type Alias Profiles
 	return json.Marshal(&struct {
-		ProfilePicture string `json:"profile_picture"`
-		CVFile         string `json:"cv_file"`
+		ProfilePicture         string            `json:"profile_picture"`
+		ProfilePictureVariants map[string]string `json:"profile_picture_variants,omitempty"`
+		CVFile                 string            `json:"cv_file"`
 		*Alias
 	}{
-		ProfilePicture: helper.GetUrlFile(p.ProfilePicture),
-		CVFile:         helper.GetUrlFile(p.CVFile),
-		Alias:          (*Alias)(&p),
+		ProfilePicture:         helper.GetUrlFile(p.ProfilePicture),
+		ProfilePictureVariants: helper.GetImageVariantURLs(p.ProfilePicture),
+		CVFile:                 helper.GetCVUrl(p.UserID, p.CVFile),
+		Alias:                  (*Alias)(&p),
 	})
 }
@@ -34,17 +34,6 @@ func (Benefit) TableName() string {
 	return "benefits"
 }
 
-type Timeline struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"unique;not null"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-func (Timeline) TableName() string {
-	return "timelines"
-}
-
 type ProjectRequiredSkill struct {
 	ProjectID uint  `json:"project_id" gorm:"primaryKey"`
 	SkillID   uint  `json:"skill_id" gorm:"primaryKey"`
@@ -74,14 +63,3 @@ type ProjectBenefit struct {
 func (ProjectBenefit) TableName() string {
 	return "project_benefits"
 }
-
-type ProjectTimeline struct {
-	ProjectID      uint     `json:"project_id" gorm:"primaryKey"`
-	TimelineID     uint     `json:"timeline_id" gorm:"primaryKey"`
-	TimelineStatus string   `json:"timeline_status" gorm:"type:timeline_status;default:'not-started';not null"`
-	Timeline       Timeline `json:"timeline" gorm:"foreignKey:TimelineID"`
-}
-
-func (ProjectTimeline) TableName() string {
-	return "project_timelines"
-}
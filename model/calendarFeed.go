@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// CalendarFeed holds the secret token of a user's iCalendar feed. The token is the only
+// authorization of the feed URL, since calendar apps can't log in; resetting it revokes old URLs.
+type CalendarFeed struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	Token     string    `json:"-" gorm:"type:varchar(80);not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	User Users `json:"-" gorm:"foreignKey:UserID"`
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Private file classes
+const (
+	FileTypeCV                     = "cv"
+	FileTypeAnnouncementAttachment = "announcement_attachment"
+)
+
+// FileAccessLog records every download of a private file through a signed link
+type FileAccessLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	OwnerID   uint      `json:"owner_id" gorm:"not null;index"`
+	ViewerID  uint      `json:"viewer_id" gorm:"not null"`
+	ProjectID *uint     `json:"project_id,omitempty"`
+	FileType  string    `json:"file_type" gorm:"type:varchar(20);not null"`
+	FilePath  string    `json:"-" gorm:"type:text;not null"`
+	IPAddress string    `json:"ip_address" gorm:"type:varchar(64)"`
+	UserAgent string    `json:"user_agent" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Viewer  Users    `json:"-" gorm:"foreignKey:ViewerID"`
+	Project *Project `json:"-" gorm:"foreignKey:ProjectID;constraint:OnDelete:SET NULL"`
+
+	ViewerName   string `json:"viewer_name" gorm:"-"`
+	ProjectTitle string `json:"project_title,omitempty" gorm:"-"`
+}
+
+func (FileAccessLog) TableName() string {
+	return "file_access_logs"
+}
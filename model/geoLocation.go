@@ -0,0 +1,23 @@
+package model
+
+// Location modes of projects, and the mode collaborators prefer
+const (
+	LocationModeRemote = "remote"
+	LocationModeHybrid = "hybrid"
+	LocationModeOnsite = "onsite"
+)
+
+// GeoLocation is the structured side of a free-text location, embedded in projects and
+// profiles. City and region are normalised by the geocoder; coordinates are optional.
+type GeoLocation struct {
+	City         string   `json:"city" gorm:"type:varchar(100);not null;default:'';index"`
+	Region       string   `json:"region" gorm:"type:varchar(100);not null;default:'';index"`
+	Latitude     *float64 `json:"latitude" gorm:"index"`
+	Longitude    *float64 `json:"longitude"`
+	LocationMode string   `json:"location_mode" gorm:"type:varchar(10);not null;default:''"`
+}
+
+// HasCoordinates reports whether the location can be used in radius searches
+func (g GeoLocation) HasCoordinates() bool {
+	return g.Latitude != nil && g.Longitude != nil
+}
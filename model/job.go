@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type ScheduledJob struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"uniqueIndex;not null"`
+	Schedule   string     `json:"schedule" gorm:"not null"`
+	Enabled    bool       `json:"enabled" gorm:"not null;default:true"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (ScheduledJob) TableName() string {
+	return "scheduled_jobs"
+}
+
+type JobRun struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	JobName    string     `json:"job_name" gorm:"not null;index"`
+	Trigger    string     `json:"trigger" gorm:"not null"`
+	Status     string     `json:"status" gorm:"not null"`
+	Instance   string     `json:"instance"`
+	StartedAt  time.Time  `json:"started_at" gorm:"not null;index"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty" gorm:"type:text"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// Job run status constants
+const (
+	JobRunStatusRunning = "running"
+	JobRunStatusSuccess = "success"
+	JobRunStatusFailed  = "failed"
+)
+
+// Job run trigger constants
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
@@ -3,14 +3,20 @@ package model
 import "time"
 
 type Notification struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	ProjectID *uint     `json:"project_id,omitempty"`
-	Type      string    `json:"type" gorm:"not null"`
-	Title     string    `json:"title" gorm:"not null"`
-	Message   string    `json:"message" gorm:"type:text;not null"`
-	IsRead    bool      `json:"is_read" gorm:"default:false"`
-	Data      string    `json:"data,omitempty" gorm:"type:text"`
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	UserID    uint   `json:"user_id" gorm:"not null;index:idx_notifications_user_event,priority:1"`
+	ProjectID *uint  `json:"project_id,omitempty"`
+	Type      string `json:"type" gorm:"not null"`
+	Title     string `json:"title" gorm:"not null"`
+	Message   string `json:"message" gorm:"type:text;not null"`
+	IsRead    bool   `json:"is_read" gorm:"default:false"`
+	Data      string `json:"data,omitempty" gorm:"type:text"`
+
+	// Grouping: events with the same group key inside the aggregation window are folded into one row
+	GroupKey    string    `json:"group_key,omitempty" gorm:"index"`
+	EventCount  int       `json:"event_count" gorm:"not null;default:1"`
+	LastEventAt time.Time `json:"last_event_at" gorm:"index;index:idx_notifications_user_event,priority:2"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -35,4 +41,16 @@ const (
 	NotificationTypeProjectCompleted    = "project_completed"
 	NotificationTypeRoleAssigned        = "role_assigned"
 	NotificationTypeInvitationReceived  = "invitation_received"
+	NotificationTypeProjectStarting     = "project_starting"
+	NotificationTypeProjectEnding       = "project_ending"
+	NotificationTypeMilestoneDue        = "milestone_due"
+	NotificationTypeAccessGranted       = "project_access_granted"
+	NotificationTypeOwnershipTransfer   = "ownership_transferred"
+	NotificationTypeTaskAssigned        = "task_assigned"
+	NotificationTypeTaskUpdated         = "task_updated"
+	NotificationTypeTaskCommented       = "task_commented"
+	NotificationTypeAnnouncement        = "project_announcement"
+	NotificationTypeAnnouncementComment = "announcement_commented"
+	NotificationTypeQuestionAsked       = "question_asked"
+	NotificationTypeQuestionAnswered    = "question_answered"
 )
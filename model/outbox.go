@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type OutboxMessage struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Topic         string     `json:"topic" gorm:"not null;index"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"`
+	Status        string     `json:"status" gorm:"not null;default:'pending';index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts   int        `json:"max_attempts" gorm:"not null;default:8"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+// Outbox status constants
+const (
+	OutboxStatusPending    = "pending"
+	OutboxStatusProcessing = "processing"
+	OutboxStatusDelivered  = "delivered"
+	OutboxStatusDead       = "dead"
+)
+
+// Outbox topic constants
+const (
+	OutboxTopicOTPEmail                  = "email.otp"
+	OutboxTopicPasswordResetEmail        = "email.password_reset"
+	OutboxTopicNotifyUserRegistered      = "notification.user_registered"
+	OutboxTopicNotifyUserAccepted        = "notification.user_accepted"
+	OutboxTopicNotifyUserRejected        = "notification.user_rejected"
+	OutboxTopicNotifyRoleAssigned        = "notification.role_assigned"
+	OutboxTopicNotifyInvitation          = "notification.invitation_received"
+	OutboxTopicNotifyStatusChange        = "notification.project_status_change"
+	OutboxTopicNotifyProjectUpdated      = "notification.project_updated"
+	OutboxTopicNotifyTaskAssigned        = "notification.task_assigned"
+	OutboxTopicNotifyTaskUpdated         = "notification.task_updated"
+	OutboxTopicNotifyTaskCommented       = "notification.task_commented"
+	OutboxTopicNotifyAnnouncement        = "notification.project_announcement"
+	OutboxTopicNotifyAnnouncementComment = "notification.announcement_commented"
+	OutboxTopicNotifyQuestionAsked       = "notification.question_asked"
+	OutboxTopicNotifyQuestionAnswered    = "notification.question_answered"
+	OutboxTopicWebhookDelivery           = "webhook.delivery"
+)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"gorm.io/gorm"
 	"synergazing.com/synergazing/helper"
 )
 
@@ -21,17 +22,28 @@ type Project struct {
 	PictureURL  string `json:"picture_url" gorm:"type:text"`
 
 	Duration             string    `json:"duration"`
+	DurationDays         int       `json:"duration_days" gorm:"not null;default:0;index"`
 	TotalTeam            int       `json:"total_team"`
 	StartDate            time.Time `json:"start_date"`
 	EndDate              time.Time `json:"end_date"`
 	Location             string    `json:"location"`
-	Budget               string    `json:"budget"`
 	RegistrationDeadline time.Time `json:"registration_deadline"`
 
+	GeoLocation
+
+	// Budget is the free-text description; the typed fields below are used for filtering
+	Budget         string `json:"budget"`
+	BudgetMin      *int64 `json:"budget_min"`
+	BudgetMax      *int64 `json:"budget_max"`
+	BudgetCurrency string `json:"budget_currency" gorm:"type:varchar(3);not null;default:'IDR'"`
+	IsPaid         *bool  `json:"is_paid" gorm:"index"`
+
 	TimeCommitment string `json:"time_commitment"`
+	HoursPerWeek   *int   `json:"hours_per_week"`
 
-	Benefits []*ProjectBenefit  `json:"benefits" gorm:"foreignKey:ProjectID"`
-	Timeline []*ProjectTimeline `json:"timeline" gorm:"foreignKey:ProjectID"`
+	Benefits   []*ProjectBenefit   `json:"benefits" gorm:"foreignKey:ProjectID"`
+	Milestones []*ProjectMilestone `json:"milestones" gorm:"foreignKey:ProjectID"`
+	Questions  []*ProjectQuestion  `json:"questions,omitempty" gorm:"foreignKey:ProjectID"`
 
 	RequiredSkills []*ProjectRequiredSkill `json:"required_skills" gorm:"foreignKey:ProjectID"`
 	Conditions     []*ProjectCondition     `json:"conditions" gorm:"foreignKey:ProjectID"`
@@ -39,21 +51,34 @@ type Project struct {
 	Members        []*ProjectMember        `json:"members" gorm:"foreignKey:ProjectID"`
 	Tags           []*ProjectTag           `json:"tags" gorm:"foreignKey:ProjectID"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 func (Project) TableName() string {
 	return "projects"
 }
 
+// Project lifecycle status constants
+const (
+	ProjectStatusDraft      = "draft"
+	ProjectStatusPublished  = "published"
+	ProjectStatusInProgress = "in_progress"
+	ProjectStatusCompleted  = "completed"
+	ProjectStatusArchived   = "archived"
+	ProjectStatusCancelled  = "cancelled"
+)
+
 func (p Project) MarshalJSON() ([]byte, error) {
 	type Alias Project
 	return json.Marshal(&struct {
-		PictureURL string `json:"picture_url"`
+		PictureURL      string            `json:"picture_url"`
+		PictureVariants map[string]string `json:"picture_variants,omitempty"`
 		*Alias
 	}{
-		PictureURL: helper.GetUrlFile(p.PictureURL),
-		Alias:      (*Alias)(&p),
+		PictureURL:      helper.GetUrlFile(p.PictureURL),
+		PictureVariants: helper.GetImageVariantURLs(p.PictureURL),
+		Alias:           (*Alias)(&p),
 	})
 }
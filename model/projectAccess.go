@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// ProjectAccess grants a user a management level on a project. The project creator is the
+// primary owner and has no row; co-owners, managers and reviewers are stored here.
+type ProjectAccess struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_project_access_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_project_access_user;index"`
+	Level     string    `json:"level" gorm:"not null"`
+	GrantedBy uint      `json:"granted_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
+	User    Users   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (ProjectAccess) TableName() string {
+	return "project_accesses"
+}
+
+// Project access level constants
+const (
+	ProjectAccessOwner    = "owner"
+	ProjectAccessManager  = "manager"
+	ProjectAccessReviewer = "reviewer"
+)
+
+// Project permission constants
+const (
+	ProjectPermissionEdit               = "project.edit"
+	ProjectPermissionDelete             = "project.delete"
+	ProjectPermissionManageMembers      = "members.manage"
+	ProjectPermissionReviewApplications = "applications.review"
+	ProjectPermissionManageAccess       = "access.manage"
+	ProjectPermissionManageWebhooks     = "webhooks.manage"
+	ProjectPermissionTransferOwnership  = "ownership.transfer"
+	ProjectPermissionViewAnalytics      = "analytics.view"
+)
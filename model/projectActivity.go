@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// ProjectActivity is an entry in a project's activity feed
+type ProjectActivity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;index:idx_project_activities_feed"`
+	ActorID   uint      `json:"actor_id" gorm:"not null"`
+	Type      string    `json:"type" gorm:"not null"`
+	Message   string    `json:"message" gorm:"type:text;not null"`
+	Data      string    `json:"data,omitempty" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_project_activities_feed"`
+
+	Actor Users `json:"actor" gorm:"foreignKey:ActorID"`
+}
+
+func (ProjectActivity) TableName() string {
+	return "project_activities"
+}
+
+// Activity type constants
+const (
+	ActivityTypeTaskCreated       = "task.created"
+	ActivityTypeTaskUpdated       = "task.updated"
+	ActivityTypeTaskStatusChanged = "task.status_changed"
+	ActivityTypeTaskAssigned      = "task.assigned"
+	ActivityTypeTaskCommented     = "task.commented"
+	ActivityTypeTaskDeleted       = "task.deleted"
+	ActivityTypeStatusChanged     = "project.status_changed"
+	ActivityTypeAnnouncement      = "announcement.posted"
+)
@@ -0,0 +1,93 @@
+package model
+
+import "time"
+
+// ProjectAnnouncement is a post in a project's announcements feed
+type ProjectAnnouncement struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ProjectID uint       `json:"project_id" gorm:"not null;index:idx_project_announcements_feed"`
+	AuthorID  uint       `json:"author_id" gorm:"not null"`
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	Pinned    bool       `json:"pinned" gorm:"not null;default:false"`
+	PinnedAt  *time.Time `json:"pinned_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index:idx_project_announcements_feed"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Author      Users                            `json:"author" gorm:"foreignKey:AuthorID"`
+	Attachments []*ProjectAnnouncementAttachment `json:"attachments" gorm:"foreignKey:AnnouncementID"`
+	Comments    []*ProjectAnnouncementComment    `json:"comments,omitempty" gorm:"foreignKey:AnnouncementID"`
+
+	// Filled in when the announcement is read
+	CommentCount int64            `json:"comment_count" gorm:"-"`
+	Reactions    map[string]int64 `json:"reactions" gorm:"-"`
+	MyReactions  []string         `json:"my_reactions" gorm:"-"`
+}
+
+func (ProjectAnnouncement) TableName() string {
+	return "project_announcements"
+}
+
+// ProjectAnnouncementAttachment is a file uploaded with an announcement
+type ProjectAnnouncementAttachment struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	AnnouncementID uint      `json:"announcement_id" gorm:"not null;index"`
+	FileName       string    `json:"file_name" gorm:"not null"`
+	FilePath       string    `json:"-" gorm:"not null"`
+	Size           int64     `json:"size"`
+	URL            string    `json:"url" gorm:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (ProjectAnnouncementAttachment) TableName() string {
+	return "project_announcement_attachments"
+}
+
+// ProjectAnnouncementComment is a comment on an announcement
+type ProjectAnnouncementComment struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	AnnouncementID uint      `json:"announcement_id" gorm:"not null;index"`
+	UserID         uint      `json:"user_id" gorm:"not null"`
+	Body           string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	User Users `json:"user" gorm:"foreignKey:UserID"`
+}
+
+func (ProjectAnnouncementComment) TableName() string {
+	return "project_announcement_comments"
+}
+
+// ProjectAnnouncementReaction is one user's reaction to an announcement
+type ProjectAnnouncementReaction struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	AnnouncementID uint      `json:"announcement_id" gorm:"not null;uniqueIndex:idx_announcement_reaction"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_announcement_reaction"`
+	Reaction       string    `json:"reaction" gorm:"not null;uniqueIndex:idx_announcement_reaction"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (ProjectAnnouncementReaction) TableName() string {
+	return "project_announcement_reactions"
+}
+
+// Announcement reaction constants
+const (
+	AnnouncementReactionLike      = "like"
+	AnnouncementReactionLove      = "love"
+	AnnouncementReactionCelebrate = "celebrate"
+	AnnouncementReactionLaugh     = "laugh"
+	AnnouncementReactionWow       = "wow"
+	AnnouncementReactionSad       = "sad"
+)
+
+// AnnouncementReactions lists the reactions members can leave on an announcement
+var AnnouncementReactions = []string{
+	AnnouncementReactionLike,
+	AnnouncementReactionLove,
+	AnnouncementReactionCelebrate,
+	AnnouncementReactionLaugh,
+	AnnouncementReactionWow,
+	AnnouncementReactionSad,
+}
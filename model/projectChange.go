@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ProjectChange records a single field edited through the project stages
+type ProjectChange struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;index"`
+	ChangedBy uint      `json:"changed_by" gorm:"not null"`
+	Stage     int       `json:"stage" gorm:"not null"`
+	Field     string    `json:"field" gorm:"not null"`
+	OldValue  string    `json:"old_value" gorm:"type:text"`
+	NewValue  string    `json:"new_value" gorm:"type:text"`
+	Material  bool      `json:"material" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+
+	Project       Project `json:"-" gorm:"foreignKey:ProjectID"`
+	ChangedByUser Users   `json:"changed_by_user,omitempty" gorm:"foreignKey:ChangedBy"`
+}
+
+func (ProjectChange) TableName() string {
+	return "project_changes"
+}
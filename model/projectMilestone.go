@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// ProjectMilestone is a dated step of a project's timeline. Milestones are owned by their
+// project, ordered by Position, and may depend on other milestones of the same project.
+type ProjectMilestone struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	ProjectID       uint       `json:"project_id" gorm:"not null;index"`
+	Name            string     `json:"name" gorm:"not null"`
+	Description     string     `json:"description" gorm:"type:text"`
+	Position        int        `json:"position" gorm:"not null;default:0"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	DueDate         *time.Time `json:"due_date,omitempty"`
+	Status          string     `json:"status" gorm:"type:timeline_status;default:'not-started';not null"`
+	PercentComplete int        `json:"percent_complete" gorm:"not null;default:0"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	Dependencies []*ProjectMilestoneDependency `json:"dependencies" gorm:"foreignKey:MilestoneID"`
+}
+
+func (ProjectMilestone) TableName() string {
+	return "project_milestones"
+}
+
+// ProjectMilestoneDependency records that a milestone cannot start before another one is done
+type ProjectMilestoneDependency struct {
+	MilestoneID uint `json:"milestone_id" gorm:"primaryKey"`
+	DependsOnID uint `json:"depends_on_id" gorm:"primaryKey;index"`
+}
+
+func (ProjectMilestoneDependency) TableName() string {
+	return "project_milestone_dependencies"
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// ProjectQuestion is a question asked on a project's public page, with the project team's answer
+type ProjectQuestion struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ProjectID  uint       `json:"project_id" gorm:"not null;index"`
+	AskerID    uint       `json:"asker_id" gorm:"not null;index"`
+	Question   string     `json:"question" gorm:"type:text;not null"`
+	Answer     string     `json:"answer,omitempty" gorm:"type:text"`
+	AnsweredBy *uint      `json:"answered_by,omitempty"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	IsFAQ      bool       `json:"is_faq" gorm:"not null;default:false"`
+	Hidden     bool       `json:"hidden" gorm:"not null;default:false"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Only the asker's name is shown publicly
+	Asker     Users  `json:"-" gorm:"foreignKey:AskerID"`
+	AskerName string `json:"asker_name" gorm:"-"`
+}
+
+func (ProjectQuestion) TableName() string {
+	return "project_questions"
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ProjectStatusHistory records every lifecycle transition of a project
+type ProjectStatusHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProjectID  uint      `json:"project_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status" gorm:"not null"`
+	ToStatus   string    `json:"to_status" gorm:"not null"`
+	ChangedBy  uint      `json:"changed_by" gorm:"not null"`
+	Reason     string    `json:"reason,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Project       Project `json:"-" gorm:"foreignKey:ProjectID"`
+	ChangedByUser Users   `json:"changed_by_user,omitempty" gorm:"foreignKey:ChangedBy"`
+}
+
+func (ProjectStatusHistory) TableName() string {
+	return "project_status_histories"
+}
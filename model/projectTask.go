@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// ProjectTask is a card on a project's task board. It can be attached to one of the
+// project's milestones, whose status and progress then roll up from its tasks.
+type ProjectTask struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ProjectID   uint       `json:"project_id" gorm:"not null;index:idx_project_tasks_board"`
+	MilestoneID *uint      `json:"milestone_id,omitempty" gorm:"index"`
+	Title       string     `json:"title" gorm:"not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"not null;default:'todo';index:idx_project_tasks_board"`
+	Position    int        `json:"position" gorm:"not null;default:0"`
+	Labels      string     `json:"labels"` // comma-separated
+	AssigneeID  *uint      `json:"assignee_id,omitempty" gorm:"index"`
+	CreatedBy   uint       `json:"created_by" gorm:"not null"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Project   Project               `json:"-" gorm:"foreignKey:ProjectID"`
+	Milestone *ProjectMilestone     `json:"milestone,omitempty" gorm:"foreignKey:MilestoneID"`
+	Assignee  *Users                `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	Creator   Users                 `json:"creator" gorm:"foreignKey:CreatedBy"`
+	Comments  []*ProjectTaskComment `json:"comments,omitempty" gorm:"foreignKey:TaskID"`
+}
+
+func (ProjectTask) TableName() string {
+	return "project_tasks"
+}
+
+// ProjectTaskComment is a comment on a task
+type ProjectTaskComment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TaskID    uint      `json:"task_id" gorm:"not null;index"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	User Users `json:"user" gorm:"foreignKey:UserID"`
+}
+
+func (ProjectTaskComment) TableName() string {
+	return "project_task_comments"
+}
+
+// Task status constants, in board column order
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusInReview   = "in_review"
+	TaskStatusDone       = "done"
+)
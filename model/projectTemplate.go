@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ProjectTemplate is a reusable project blueprint. Content holds a JSON document with the
+// project fields, roles, skills, conditions, benefits, timeline and tags, with dates stored
+// as day offsets from the start date so they can be shifted onto a new start date.
+type ProjectTemplate struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	CreatorID       uint      `json:"creator_id" gorm:"not null;index"`
+	SourceProjectID *uint     `json:"source_project_id,omitempty"`
+	Name            string    `json:"name" gorm:"not null"`
+	Description     string    `json:"description" gorm:"type:text"`
+	Content         string    `json:"-" gorm:"type:text;not null"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	Creator Users `json:"-" gorm:"foreignKey:CreatorID"`
+}
+
+func (ProjectTemplate) TableName() string {
+	return "project_templates"
+}
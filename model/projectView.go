@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ProjectView is a view of a public project page. Each viewer is counted once per project per
+// day; signed-in viewers are keyed by user, anonymous ones by a hash of their IP and user agent.
+type ProjectView struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_project_view_daily"`
+	ViewDate  time.Time `json:"view_date" gorm:"type:date;not null;uniqueIndex:idx_project_view_daily"`
+	ViewerKey string    `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:idx_project_view_daily"`
+	ViewerID  *uint     `json:"viewer_id,omitempty" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
+}
+
+func (ProjectView) TableName() string {
+	return "project_views"
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ProjectReminderSetting holds the reminder offsets, in days before each date, chosen for a project
+type ProjectReminderSetting struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	ProjectID           uint      `json:"project_id" gorm:"not null;uniqueIndex"`
+	Enabled             bool      `json:"enabled" gorm:"not null;default:true"`
+	RegistrationOffsets string    `json:"registration_offsets" gorm:"not null;default:'7,3,1'"`
+	StartDateOffsets    string    `json:"start_date_offsets" gorm:"not null;default:'3,1'"`
+	EndDateOffsets      string    `json:"end_date_offsets" gorm:"not null;default:'7,1'"`
+	MilestoneOffsets    string    `json:"milestone_offsets" gorm:"not null;default:'3,1'"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
+}
+
+func (ProjectReminderSetting) TableName() string {
+	return "project_reminder_settings"
+}
+
+// ProjectReminderLog records each reminder sent so the same reminder is never sent twice
+type ProjectReminderLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProjectID  uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_reminder_log_unique,priority:1"`
+	Kind       string    `json:"kind" gorm:"not null;uniqueIndex:idx_reminder_log_unique,priority:2"`
+	TargetID   uint      `json:"target_id" gorm:"not null;default:0;uniqueIndex:idx_reminder_log_unique,priority:3"`
+	TargetDate time.Time `json:"target_date" gorm:"type:date;not null;uniqueIndex:idx_reminder_log_unique,priority:4"`
+	OffsetDays int       `json:"offset_days" gorm:"not null;uniqueIndex:idx_reminder_log_unique,priority:5"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+func (ProjectReminderLog) TableName() string {
+	return "project_reminder_logs"
+}
+
+// Reminder kind constants
+const (
+	ReminderKindRegistrationDeadline = "registration_deadline"
+	ReminderKindStartDate            = "start_date"
+	ReminderKindEndDate              = "end_date"
+	ReminderKindMilestone            = "milestone"
+)
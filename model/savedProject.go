@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// SavedProject is a project a user bookmarked to come back to, e.g. before its registration closes
+type SavedProject struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_saved_project_user"`
+	ProjectID uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_saved_project_user;index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Project Project `json:"project" gorm:"foreignKey:ProjectID"`
+}
+
+func (SavedProject) TableName() string {
+	return "saved_projects"
+}
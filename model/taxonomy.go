@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Kinds of shared taxonomy entries that projects pick by name
+const (
+	TaxonomyKindTag     = "tags"
+	TaxonomyKindBenefit = "benefits"
+)
+
+// TaxonomyAlias sends another spelling of a tag or benefit to the entry it stands for, so
+// find-or-create reuses the entry instead of adding a near-duplicate. Key is the alias lowercased
+// with its whitespace collapsed.
+type TaxonomyAlias struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Kind      string    `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_taxonomy_alias_key"`
+	Key       string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_taxonomy_alias_key"`
+	Alias     string    `json:"alias" gorm:"type:varchar(255);not null"`
+	TargetID  uint      `json:"target_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TaxonomyAlias) TableName() string {
+	return "taxonomy_aliases"
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+type WebhookEndpoint struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProjectID   uint      `json:"project_id" gorm:"not null;index"`
+	CreatedBy   uint      `json:"created_by" gorm:"not null"`
+	URL         string    `json:"url" gorm:"type:text;not null"`
+	Secret      string    `json:"-" gorm:"not null"`
+	Events      string    `json:"events" gorm:"type:text;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	IsActive    bool      `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	EndpointID     uint       `json:"endpoint_id" gorm:"not null;index"`
+	Event          string     `json:"event" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:'pending'"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body,omitempty" gorm:"type:text"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	DurationMs     int64      `json:"duration_ms"`
+	RedeliveryOf   *uint      `json:"redelivery_of,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Endpoint WebhookEndpoint `json:"-" gorm:"foreignKey:EndpointID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Webhook delivery status constants
+const (
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed"
+)
+
+// Webhook event constants
+const (
+	WebhookEventPing                 = "ping"
+	WebhookEventApplicationSubmitted = "application.submitted"
+	WebhookEventMemberAccepted       = "member.accepted"
+	WebhookEventMemberRemoved        = "member.removed"
+	WebhookEventProjectStatusChanged = "project.status_changed"
+	WebhookEventTimelineUpdated      = "timeline.updated"
+)
+
+// WebhookEvents lists the events endpoints can subscribe to
+var WebhookEvents = []string{
+	WebhookEventApplicationSubmitted,
+	WebhookEventMemberAccepted,
+	WebhookEventMemberRemoved,
+	WebhookEventProjectStatusChanged,
+	WebhookEventTimelineUpdated,
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+// SetupCalendarRoutes must run before any route group on /api, whose middleware would
+// otherwise require a login for the public feed
+func SetupCalendarRoutes(app *fiber.App) {
+	db := config.GetDB()
+	calendarController := controller.NewCalendarController(service.NewCalendarService(db))
+	savedProjectController := controller.NewSavedProjectController(service.NewSavedProjectService(db))
+
+	// Public route - the feed token is its own authorization, since calendar apps can't log in
+	app.Get("/api/calendar/:token.ics", calendarController.GetUserCalendar)
+
+	// Protected routes
+	calendar := app.Group("/api/calendar", middleware.AuthMiddleware())
+
+	calendar.Get("/feed", calendarController.GetFeedLink)
+	calendar.Post("/feed/reset", calendarController.ResetFeedLink)
+
+	app.Get("/api/saved-projects", middleware.AuthMiddleware(), savedProjectController.GetSavedProjects)
+
+	saved := app.Group("/api/projects/:project_id/save", middleware.AuthMiddleware())
+
+	saved.Post("/", savedProjectController.SaveProject)
+	saved.Delete("/", savedProjectController.UnsaveProject)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupFileAccessRoutes(app *fiber.App) {
+	fileAccessController := controller.NewFileAccessController(service.NewFileAccessService(config.GetDB()))
+
+	// Public route - signed links carry their own authorization
+	app.Get("/api/files/private", fileAccessController.GetPrivateFile)
+
+	// Protected routes
+	files := app.Group("/api", middleware.AuthMiddleware())
+
+	files.Get("/users/:id/cv", fileAccessController.GetCVLink)
+	files.Get("/profile/cv/views", fileAccessController.GetCVViews)
+}
@@ -21,11 +21,18 @@ func SetupNotificationRoutes(app *fiber.App) {
 
 	notifications.Get("/count", notificationController.GetUnreadCount)
 
-	notifications.Put("/:id/read", notificationController.MarkAsRead)
+	// Bulk actions take {"ids": [...]} or the same filters as the list endpoint
+	notifications.Put("/bulk/read", notificationController.BulkMarkAsRead)
+
+	notifications.Put("/bulk/unread", notificationController.BulkMarkAsUnread)
+
+	notifications.Post("/bulk/delete", notificationController.BulkDelete)
 
 	notifications.Put("/read-all", notificationController.MarkAllAsRead)
 
-	notifications.Delete("/:id", notificationController.DeleteNotification)
+	notifications.Put("/:id/read", notificationController.MarkAsRead)
+
+	notifications.Put("/:id/unread", notificationController.MarkAsUnread)
 
-	notifications.Post("/test-deadlines", notificationController.TestDeadlineNotifications)
+	notifications.Delete("/:id", notificationController.DeleteNotification)
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupOutboxRoutes(app *fiber.App) {
+	db := config.GetDB()
+	outboxService := service.NewOutboxService(db)
+	outboxController := controller.NewOutboxController(outboxService)
+
+	// Admin routes - authentication and admin role required
+	outbox := app.Group("/api/admin/outbox", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	outbox.Get("/", outboxController.GetMessages)
+	outbox.Get("/:id", outboxController.GetMessage)
+	outbox.Post("/:id/retry", outboxController.RetryMessage)
+}
@@ -13,28 +13,53 @@ func SetupProjectRoutes(app *fiber.App) {
 	skillService := service.NewSkillService(db)
 	tagService := service.NewTagService(db)
 	benefitService := service.NewBenefitService(db)
-	timelineService := service.NewTimelineService(db)
-	ProjectService := service.NewProjectService(db, skillService, tagService, benefitService, timelineService)
+	outboxService := service.NewOutboxService(db)
+	webhookService := service.NewWebhookService(db, outboxService)
+	ProjectService := service.NewProjectService(db, skillService, tagService, benefitService, webhookService)
 	projectController := controller.NewProjectController(ProjectService)
+	lifecycleService := service.NewProjectLifecycleService(db, outboxService, webhookService)
+	lifecycleController := controller.NewProjectLifecycleController(lifecycleService)
+	changeController := controller.NewProjectChangeController(service.NewProjectChangeService(db, outboxService))
+	templateController := controller.NewProjectTemplateController(service.NewProjectTemplateService(db, ProjectService))
+	trashController := controller.NewProjectTrashController(service.NewProjectTrashService(db))
+	calendarController := controller.NewCalendarController(service.NewCalendarService(db))
 
 	// Register specific public routes FIRST to avoid conflicts with protected /:id route
 	app.Get("/api/projects/all", projectController.GetAllProjects)
-	app.Get("/api/projects/public/:id", projectController.GetProjectByID)
+	app.Get("/api/projects/public/:id", middleware.OptionalAuthMiddleware(), projectController.GetProjectByID)
+	app.Get("/api/projects/public/:id/calendar.ics", middleware.OptionalAuthMiddleware(), calendarController.GetProjectCalendar)
 	app.Get("/api/projects/timeline-status-options", projectController.GetTimelineStatusOptions)
+	app.Get("/api/projects/status-options", lifecycleController.GetStatusOptions)
 
 	// Protected routes - authentication required
 	project := app.Group("/api/projects", middleware.AuthMiddleware())
 
+	project.Post("/", projectController.CreateProject)
 	project.Post("/stage1", projectController.CreateStage1)
+
+	// Templates - registered before /:id routes
+	project.Get("/templates", templateController.GetTemplates)
+	project.Get("/templates/:template_id", templateController.GetTemplate)
+	project.Delete("/templates/:template_id", templateController.DeleteTemplate)
+	project.Post("/templates/:template_id/create", templateController.CreateFromTemplate)
+	project.Post("/:id/template", templateController.SaveAsTemplate)
+	project.Post("/:id/duplicate", templateController.DuplicateProject)
 	project.Put("/:id/stage2", projectController.UpdateStage2)
 	project.Put("/:id/stage3", projectController.UpdateStage3)
 	project.Put("/:id/stage4", projectController.UpdateStage4)
 	project.Put("/:id/stage5", projectController.UpdateStage5)
+	project.Patch("/:id", projectController.PatchProject)
+	project.Put("/:id/status", lifecycleController.ChangeStatus)
+	project.Get("/:id/status-history", lifecycleController.GetStatusHistory)
+	project.Get("/:id/changes", changeController.GetChanges)
+	project.Get("/:id/checklist", projectController.GetChecklist)
 
 	project.Get("/", projectController.GetUserProjects)
 	project.Get("/created", projectController.GetMyCreatedProjects)
 	project.Get("/member", projectController.GetMyMemberProjects)
+	project.Get("/deleted", trashController.GetDeletedProjects)
 	project.Get("/:id", projectController.GetUserProject)
 	project.Get("/:id/capacity", projectController.GetProjectTeamCapacity)
 	project.Delete("/:id", projectController.DeleteProject)
+	project.Post("/:id/restore", trashController.RestoreProject)
 }
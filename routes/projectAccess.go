@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectAccessRoutes(app *fiber.App) {
+	db := config.GetDB()
+	accessService := service.NewProjectAccessService(db)
+	accessController := controller.NewProjectAccessController(accessService)
+
+	// Protected routes - owners manage access, everyone on the project can see it
+	access := app.Group("/api/projects/:project_id", middleware.AuthMiddleware())
+
+	access.Get("/access", accessController.GetAccess)
+	access.Get("/access/me", accessController.GetMyPermissions)
+	access.Put("/access/:user_id", accessController.GrantAccess)
+	access.Delete("/access/:user_id", accessController.RevokeAccess)
+	access.Post("/transfer-ownership", accessController.TransferOwnership)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectAnalyticsRoutes(app *fiber.App) {
+	db := config.GetDB()
+	analyticsController := controller.NewProjectAnalyticsController(service.NewProjectAnalyticsService(db))
+
+	// Protected routes - only owners see analytics. Views are counted by /api/projects/public/:id.
+	analytics := app.Group("/api/projects/:project_id/analytics", middleware.AuthMiddleware())
+
+	analytics.Get("/", analyticsController.GetAnalytics)
+}
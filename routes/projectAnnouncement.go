@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectAnnouncementRoutes(app *fiber.App) {
+	db := config.GetDB()
+	outboxService := service.NewOutboxService(db)
+	announcementController := controller.NewProjectAnnouncementController(service.NewProjectAnnouncementService(db, outboxService))
+
+	// Protected routes - owners and managers post, everyone on the project reads, comments and reacts
+	announcements := app.Group("/api/projects/:project_id/announcements", middleware.AuthMiddleware())
+
+	announcements.Get("/", announcementController.GetAnnouncements)
+	announcements.Post("/", announcementController.CreateAnnouncement)
+	announcements.Get("/:announcement_id", announcementController.GetAnnouncement)
+	announcements.Put("/:announcement_id", announcementController.UpdateAnnouncement)
+	announcements.Delete("/:announcement_id", announcementController.DeleteAnnouncement)
+	announcements.Put("/:announcement_id/pin", announcementController.PinAnnouncement)
+	announcements.Post("/:announcement_id/comments", announcementController.AddComment)
+	announcements.Delete("/:announcement_id/comments/:comment_id", announcementController.DeleteComment)
+	announcements.Post("/:announcement_id/reactions", announcementController.React)
+	announcements.Delete("/:announcement_id/reactions/:reaction", announcementController.RemoveReaction)
+}
@@ -11,7 +11,9 @@ import (
 func SetupProjectMemberRoutes(app *fiber.App) {
 	db := config.GetDB()
 	notificationService := service.NewNotificationService(db)
-	projectMemberService := service.NewProjectMemberService(db, notificationService)
+	outboxService := service.NewOutboxService(db)
+	webhookService := service.NewWebhookService(db, outboxService)
+	projectMemberService := service.NewProjectMemberService(db, notificationService, outboxService, webhookService)
 	projectMemberController := controller.NewProjectMemberController(projectMemberService)
 
 	// Protected routes - authentication required
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectMilestoneRoutes(app *fiber.App) {
+	db := config.GetDB()
+	webhookService := service.NewWebhookService(db, service.NewOutboxService(db))
+	milestoneController := controller.NewProjectMilestoneController(service.NewProjectMilestoneService(db, webhookService))
+
+	// Protected routes - anyone who can see the project reads milestones, editors change them
+	milestones := app.Group("/api/projects/:project_id/milestones", middleware.AuthMiddleware())
+
+	milestones.Get("/", milestoneController.GetMilestones)
+	milestones.Post("/", milestoneController.CreateMilestone)
+	milestones.Put("/reorder", milestoneController.ReorderMilestones)
+	milestones.Put("/:milestone_id", milestoneController.UpdateMilestone)
+	milestones.Delete("/:milestone_id", milestoneController.DeleteMilestone)
+}
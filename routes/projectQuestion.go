@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectQuestionRoutes(app *fiber.App) {
+	db := config.GetDB()
+	outboxService := service.NewOutboxService(db)
+	questionController := controller.NewProjectQuestionController(service.NewProjectQuestionService(db, outboxService))
+
+	// Protected routes - any logged-in user can ask, owners and managers answer and moderate.
+	// Visible questions are also part of the public project at /api/projects/public/:id.
+	questions := app.Group("/api/projects/:project_id/questions", middleware.AuthMiddleware())
+
+	questions.Get("/", questionController.GetQuestions)
+	questions.Post("/", questionController.AskQuestion)
+	questions.Put("/:question_id/answer", questionController.AnswerQuestion)
+	questions.Put("/:question_id/faq", questionController.SetFAQ)
+	questions.Put("/:question_id/hide", questionController.SetHidden)
+	questions.Delete("/:question_id", questionController.DeleteQuestion)
+}
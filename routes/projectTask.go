@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupProjectTaskRoutes(app *fiber.App) {
+	db := config.GetDB()
+	outboxService := service.NewOutboxService(db)
+	taskController := controller.NewProjectTaskController(service.NewProjectTaskService(db, outboxService))
+	activityController := controller.NewProjectActivityController(service.NewProjectActivityService(db))
+
+	// Protected routes - the owner, users with project access and accepted members work on the board
+	api := app.Group("/api/projects/:project_id", middleware.AuthMiddleware())
+
+	api.Get("/activity", activityController.GetActivity)
+
+	api.Post("/tasks", taskController.CreateTask)
+	api.Get("/tasks", taskController.GetTasks)
+	api.Get("/tasks/board", taskController.GetBoard)
+	api.Get("/tasks/:task_id", taskController.GetTask)
+	api.Put("/tasks/:task_id", taskController.UpdateTask)
+	api.Delete("/tasks/:task_id", taskController.DeleteTask)
+	api.Post("/tasks/:task_id/comments", taskController.AddComment)
+	api.Delete("/tasks/:task_id/comments/:comment_id", taskController.DeleteComment)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupReminderRoutes(app *fiber.App) {
+	db := config.GetDB()
+	reminderService := service.NewReminderService(db)
+	reminderController := controller.NewReminderController(reminderService)
+
+	// Protected routes - owners and managers can change reminder settings
+	reminders := app.Group("/api/projects/:project_id/reminders", middleware.AuthMiddleware())
+
+	reminders.Get("/", reminderController.GetReminderSettings)
+	reminders.Put("/", reminderController.UpdateReminderSettings)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupSchedulerRoutes(app *fiber.App) {
+	db := config.GetDB()
+	schedulerService := service.NewSchedulerService(db)
+	schedulerController := controller.NewSchedulerController(schedulerService)
+
+	// Admin routes - authentication and admin role required
+	jobs := app.Group("/api/admin/jobs", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	jobs.Get("/", schedulerController.GetJobs)
+	jobs.Get("/:name/runs", schedulerController.GetJobRuns)
+	jobs.Post("/:name/trigger", schedulerController.TriggerJob)
+}
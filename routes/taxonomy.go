@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupTaxonomyRoutes(app *fiber.App) {
+	db := config.GetDB()
+	taxonomyService := service.NewTaxonomyService(db)
+	taxonomyController := controller.NewTaxonomyController(taxonomyService)
+
+	// Public autocomplete for the tag and benefit inputs
+	app.Get("/api/taxonomy/:kind", taxonomyController.Autocomplete)
+
+	// Admin routes - authentication and admin role required
+	taxonomy := app.Group("/api/admin/taxonomy", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+
+	taxonomy.Get("/:kind", taxonomyController.GetEntries)
+	taxonomy.Get("/:kind/duplicates", taxonomyController.GetDuplicates)
+	taxonomy.Put("/:kind/:id", taxonomyController.RenameEntry)
+	taxonomy.Post("/:kind/:id/merge", taxonomyController.MergeEntries)
+	taxonomy.Post("/:kind/:id/aliases", taxonomyController.AddAlias)
+	taxonomy.Delete("/:kind/:id/aliases/:alias_id", taxonomyController.RemoveAlias)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"synergazing.com/synergazing/config"
+	"synergazing.com/synergazing/controller"
+	"synergazing.com/synergazing/middleware"
+	"synergazing.com/synergazing/service"
+)
+
+func SetupWebhookRoutes(app *fiber.App) {
+	db := config.GetDB()
+	outboxService := service.NewOutboxService(db)
+	webhookService := service.NewWebhookService(db, outboxService)
+	webhookController := controller.NewWebhookController(webhookService)
+
+	// Protected routes - only project owners can manage webhooks
+	webhooks := app.Group("/api/projects/:project_id/webhooks", middleware.AuthMiddleware())
+
+	webhooks.Post("/", webhookController.CreateWebhook)
+	webhooks.Get("/", webhookController.GetWebhooks)
+	webhooks.Put("/:webhook_id", webhookController.UpdateWebhook)
+	webhooks.Delete("/:webhook_id", webhookController.DeleteWebhook)
+	webhooks.Post("/:webhook_id/ping", webhookController.PingWebhook)
+	webhooks.Get("/:webhook_id/deliveries", webhookController.GetDeliveries)
+	webhooks.Post("/:webhook_id/deliveries/:delivery_id/redeliver", webhookController.RedeliverDelivery)
+}
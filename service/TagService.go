@@ -13,12 +13,24 @@ func NewTagService(db *gorm.DB) *TagService {
 	return &TagService{DB: db}
 }
 
+// findOrCreate returns the tags called names, resolving aliases, and creates the missing ones.
+// Names that resolve to the same tag are returned once.
 func (s *TagService) findOrCreate(tx *gorm.DB, names []string) ([]*model.Tag, error) {
 	var tags []*model.Tag
+	seen := make(map[uint]bool)
 
 	for _, name := range names {
 		var tag model.Tag
-		if err := tx.Where("LOWER(name) = LOWER(?)", name).First(&tag).Error; err != nil {
+		targetID, err := resolveTaxonomyAlias(tx, model.TaxonomyKindTag, name)
+		if err != nil {
+			return nil, err
+		}
+		if targetID != 0 {
+			err = tx.First(&tag, targetID).Error
+		} else {
+			err = tx.Where("LOWER(name) = LOWER(?)", name).First(&tag).Error
+		}
+		if err != nil {
 			if err == gorm.ErrRecordNotFound {
 				tag = model.Tag{Name: name}
 				if err := tx.Create(&tag).Error; err != nil {
@@ -28,6 +40,10 @@ func (s *TagService) findOrCreate(tx *gorm.DB, names []string) ([]*model.Tag, er
 				return nil, err
 			}
 		}
+		if seen[tag.ID] {
+			continue
+		}
+		seen[tag.ID] = true
 		tags = append(tags, &tag)
 	}
 	return tags, nil
@@ -41,12 +57,24 @@ func NewBenefitService(db *gorm.DB) *BenefitService {
 	return &BenefitService{DB: db}
 }
 
+// findOrCreate returns the benefits called names, resolving aliases, and creates the missing ones.
+// Names that resolve to the same benefit are returned once.
 func (s *BenefitService) findOrCreate(tx *gorm.DB, names []string) ([]*model.Benefit, error) {
 	var benefits []*model.Benefit
+	seen := make(map[uint]bool)
 
 	for _, name := range names {
 		var benefit model.Benefit
-		if err := tx.Where("LOWER(name) = LOWER(?)", name).First(&benefit).Error; err != nil {
+		targetID, err := resolveTaxonomyAlias(tx, model.TaxonomyKindBenefit, name)
+		if err != nil {
+			return nil, err
+		}
+		if targetID != 0 {
+			err = tx.First(&benefit, targetID).Error
+		} else {
+			err = tx.Where("LOWER(name) = LOWER(?)", name).First(&benefit).Error
+		}
+		if err != nil {
 			if err == gorm.ErrRecordNotFound {
 				benefit = model.Benefit{Name: name}
 				if err := tx.Create(&benefit).Error; err != nil {
@@ -56,35 +84,11 @@ func (s *BenefitService) findOrCreate(tx *gorm.DB, names []string) ([]*model.Ben
 				return nil, err
 			}
 		}
+		if seen[benefit.ID] {
+			continue
+		}
+		seen[benefit.ID] = true
 		benefits = append(benefits, &benefit)
 	}
 	return benefits, nil
 }
-
-type TimelineService struct {
-	DB *gorm.DB
-}
-
-func NewTimelineService(db *gorm.DB) *TimelineService {
-	return &TimelineService{DB: db}
-}
-
-func (s *TimelineService) findOrCreate(tx *gorm.DB, names []string) ([]*model.Timeline, error) {
-	var timelines []*model.Timeline
-
-	for _, name := range names {
-		var timeline model.Timeline
-		if err := tx.Where("LOWER(name) = LOWER(?)", name).First(&timeline).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				timeline = model.Timeline{Name: name}
-				if err := tx.Create(&timeline).Error; err != nil {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
-		}
-		timelines = append(timelines, &timeline)
-	}
-	return timelines, nil
-}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"gorm.io/gorm"
 	"synergazing.com/synergazing/config"
 	"synergazing.com/synergazing/helper"
@@ -14,6 +17,10 @@ type ReadyUserResponse struct {
 	ProfilePicture string             `json:"profile_picture"`
 	AboutMe        string             `json:"about_me"`
 	Location       string             `json:"location"`
+	City           string             `json:"city"`
+	Region         string             `json:"region"`
+	LocationMode   string             `json:"location_mode"`
+	DistanceKm     *float64           `json:"distance_km,omitempty"`
 	Interests      string             `json:"interests"`
 	Academic       string             `json:"academic"`
 	Skills         []*model.UserSkill `json:"skills"`
@@ -27,6 +34,9 @@ type UserProfileResponse struct {
 	CVFile         string             `json:"cv_file"`
 	AboutMe        string             `json:"about_me"`
 	Location       string             `json:"location"`
+	City           string             `json:"city"`
+	Region         string             `json:"region"`
+	LocationMode   string             `json:"location_mode"`
 	Interests      string             `json:"interests"`
 	Academic       string             `json:"academic"`
 	WebsiteURL     string             `json:"website_url"`
@@ -108,6 +118,19 @@ func GetReadyUsers() ([]ReadyUserResponse, error) {
 	return response, nil
 }
 
+// GetUserGeoLocation returns the location on a user's profile, or nil without a profile
+func GetUserGeoLocation(userID uint) (*model.GeoLocation, error) {
+	var profile model.Profiles
+	err := config.DB.Select("city", "region", "latitude", "longitude", "location_mode").Where("user_id = ?", userID).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load profile: %v", err)
+	}
+	return &profile.GeoLocation, nil
+}
+
 func GetUserProfileByID(userID uint) (*UserProfileResponse, error) {
 	var user model.Users
 	var profile model.Profiles
@@ -129,9 +152,12 @@ func GetUserProfileByID(userID uint) (*UserProfileResponse, error) {
 		ID:             user.ID,
 		Name:           user.Name,
 		ProfilePicture: helper.GetUrlFile(profile.ProfilePicture),
-		CVFile:         helper.GetUrlFile(profile.CVFile),
+		CVFile:         helper.GetCVUrl(user.ID, profile.CVFile),
 		AboutMe:        profile.AboutMe,
 		Location:       profile.Location,
+		City:           profile.City,
+		Region:         profile.Region,
+		LocationMode:   profile.LocationMode,
 		Interests:      profile.Interests,
 		Academic:       profile.Academic,
 		WebsiteURL:     profile.WebsiteURL,
@@ -149,7 +175,9 @@ func GetReadyUsersPaginated() *gorm.DB {
 	return config.DB.Preload("UserSkills.Skill").Where("status_collaboration = ?", "ready")
 }
 
-func GetReadyUsersPaginatedWithTransform(page, perPage int) ([]ReadyUserResponse, *helper.PaginationData, error) {
+// GetReadyUsersPaginatedWithTransform lists users who are ready to collaborate, filtered by
+// location and nearest first when geo is a radius search
+func GetReadyUsersPaginatedWithTransform(page, perPage int, geo GeoFilter) ([]ReadyUserResponse, *helper.PaginationData, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -161,8 +189,17 @@ func GetReadyUsersPaginatedWithTransform(page, perPage int) ([]ReadyUserResponse
 	var profiles []model.Profiles
 	var totalRecords int64
 
+	// Location filters need the profile of each user
+	readyUsers := func() *gorm.DB {
+		query := config.DB.Model(&model.Users{}).Where("users.status_collaboration = ?", "ready")
+		if geo.HasCenter() || geo.City != "" || geo.Region != "" || len(geo.Modes) > 0 {
+			query = geo.Apply(query.Joins("JOIN profiles ON profiles.user_id = users.id"), "profiles")
+		}
+		return query
+	}
+
 	// Get total count
-	countResult := config.DB.Model(&model.Users{}).Where("status_collaboration = ?", "ready").Count(&totalRecords)
+	countResult := readyUsers().Count(&totalRecords)
 	if countResult.Error != nil {
 		return nil, nil, countResult.Error
 	}
@@ -172,7 +209,7 @@ func GetReadyUsersPaginatedWithTransform(page, perPage int) ([]ReadyUserResponse
 	offset := (page - 1) * perPage
 
 	// Get paginated users with ready status
-	result := config.DB.Preload("UserSkills.Skill").Where("status_collaboration = ?", "ready").
+	result := readyUsers().Preload("UserSkills.Skill").
 		Limit(perPage).Offset(offset).Find(&users)
 	if result.Error != nil {
 		return nil, nil, result.Error
@@ -217,6 +254,10 @@ func GetReadyUsersPaginatedWithTransform(page, perPage int) ([]ReadyUserResponse
 			readyUser.ProfilePicture = helper.GetUrlFile(profile.ProfilePicture)
 			readyUser.AboutMe = profile.AboutMe
 			readyUser.Location = profile.Location
+			readyUser.City = profile.City
+			readyUser.Region = profile.Region
+			readyUser.LocationMode = profile.LocationMode
+			readyUser.DistanceKm = geo.DistanceKm(profile.GeoLocation)
 			readyUser.Interests = profile.Interests
 			readyUser.Academic = profile.Academic
 		}
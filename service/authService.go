@@ -15,12 +15,14 @@ import (
 )
 
 type AuthService struct {
-	OTPService *OTPService
+	OTPService    *OTPService
+	OutboxService *OutboxService
 }
 
 func NewAuthService(otpService *OTPService) *AuthService {
 	return &AuthService{
-		OTPService: otpService,
+		OTPService:    otpService,
+		OutboxService: otpService.OutboxService,
 	}
 }
 
@@ -212,12 +214,27 @@ func (s *AuthService) ForgotPassword(email string) error {
 
 	user.PasswordResetToken = token
 	user.PasswordResetAt = time.Now().Add(time.Minute * 5)
-	if err := db.Save(&user).Error; err != nil {
+
+	tx := db.Begin()
+	if err := tx.Save(&user).Error; err != nil {
+		tx.Rollback()
 		log.Printf("Database error saving reset token: %v", err)
 		return errors.New("failed to save reset token")
 	}
 
-	go helper.SendPasswordResetEmail(user.Email, token)
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicPasswordResetEmail, PasswordResetEmailPayload{
+		Email: user.Email,
+		Token: token,
+	}); err != nil {
+		tx.Rollback()
+		log.Printf("Database error queueing reset email: %v", err)
+		return errors.New("failed to send reset email")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Printf("Database error saving reset token: %v", err)
+		return errors.New("failed to save reset token")
+	}
 
 	return nil
 }
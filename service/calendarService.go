@@ -0,0 +1,243 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// calendarUIDDomain scopes event UIDs, so they stay unique across calendars
+const calendarUIDDomain = "synergazing.com"
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarFeedLink is where calendar apps subscribe to a user's feed. WebcalURL opens the
+// subscription dialog of most calendar apps directly.
+type CalendarFeedLink struct {
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcal_url"`
+}
+
+type CalendarService struct {
+	DB *gorm.DB
+}
+
+func NewCalendarService(db *gorm.DB) *CalendarService {
+	return &CalendarService{DB: db}
+}
+
+func generateCalendarToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "cal_" + hex.EncodeToString(b), nil
+}
+
+func calendarFeedLink(feed *model.CalendarFeed) *CalendarFeedLink {
+	url := fmt.Sprintf("%s/api/calendar/%s.ics", helper.GetAppURL(), feed.Token)
+	webcal := url
+	if i := strings.Index(url, "://"); i >= 0 {
+		webcal = "webcal" + url[i:]
+	}
+	return &CalendarFeedLink{URL: url, WebcalURL: webcal}
+}
+
+// GetFeedLink returns the user's feed URL, creating the feed on first use
+func (s *CalendarService) GetFeedLink(userID uint) (*CalendarFeedLink, error) {
+	var feed model.CalendarFeed
+	err := s.DB.Where("user_id = ?", userID).First(&feed).Error
+	if err == nil {
+		return calendarFeedLink(&feed), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load calendar feed: %v", err)
+	}
+	return s.ResetFeedLink(userID)
+}
+
+// ResetFeedLink gives the user's feed a new token. Calendars subscribed to the old URL stop
+// receiving updates.
+func (s *CalendarService) ResetFeedLink(userID uint) (*CalendarFeedLink, error) {
+	token, err := generateCalendarToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %v", err)
+	}
+
+	var feed model.CalendarFeed
+	err = s.DB.Where("user_id = ?", userID).First(&feed).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		feed = model.CalendarFeed{UserID: userID, Token: token}
+		if err := s.DB.Create(&feed).Error; err != nil {
+			return nil, fmt.Errorf("failed to create calendar feed: %v", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load calendar feed: %v", err)
+	default:
+		feed.Token = token
+		if err := s.DB.Save(&feed).Error; err != nil {
+			return nil, fmt.Errorf("failed to reset calendar feed: %v", err)
+		}
+	}
+	return calendarFeedLink(&feed), nil
+}
+
+// GetUserCalendar renders the feed behind token: registration deadlines of projects the user
+// saved or has a pending application to, and the dates and milestones of projects they belong to
+func (s *CalendarService) GetUserCalendar(token string) ([]byte, error) {
+	var feed model.CalendarFeed
+	if token == "" || s.DB.Where("token = ?", token).First(&feed).Error != nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	userID := feed.UserID
+
+	var deadlineProjectIDs []uint
+	if err := s.DB.Model(&model.SavedProject{}).
+		Where("user_id = ?", userID).
+		Pluck("project_id", &deadlineProjectIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load saved projects: %v", err)
+	}
+	var appliedProjectIDs []uint
+	if err := s.DB.Model(&model.ProjectApplication{}).
+		Where("user_id = ? AND status = ?", userID, model.ApplicationStatusPending).
+		Pluck("project_id", &appliedProjectIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applications: %v", err)
+	}
+	deadlineProjectIDs = append(deadlineProjectIDs, appliedProjectIDs...)
+
+	var deadlineProjects []model.Project
+	if len(deadlineProjectIDs) > 0 {
+		if err := s.DB.Where("id IN ? AND status NOT IN ?", deadlineProjectIDs, []string{model.ProjectStatusDraft, model.ProjectStatusArchived}).
+			Order("id").Find(&deadlineProjects).Error; err != nil {
+			return nil, fmt.Errorf("failed to load projects: %v", err)
+		}
+	}
+
+	// Archived projects drop out of the feed, which removes their events from subscribed calendars
+	var memberProjects []model.Project
+	if err := s.DB.Preload("Milestones", orderMilestones).
+		Where("status <> ?", model.ProjectStatusArchived).
+		Where("creator_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ? AND status = ?) OR "+projectAccessCondition,
+			userID, userID, "accepted", userID).
+		Order("id").Find(&memberProjects).Error; err != nil {
+		return nil, fmt.Errorf("failed to load projects: %v", err)
+	}
+
+	var events []helper.ICSEvent
+	for i := range deadlineProjects {
+		if event, ok := registrationDeadlineEvent(&deadlineProjects[i]); ok {
+			events = append(events, event)
+		}
+	}
+	for i := range memberProjects {
+		events = append(events, projectDateEvents(&memberProjects[i])...)
+		events = append(events, milestoneEvents(&memberProjects[i])...)
+	}
+
+	return helper.BuildICS("Synergazing", events), nil
+}
+
+// GetProjectCalendar renders the registration deadline, dates and milestones of one project.
+// Drafts and archived projects are only available to the people who can see them.
+func (s *CalendarService) GetProjectCalendar(projectID, userID uint) ([]byte, *model.Project, error) {
+	var project model.Project
+	if err := s.DB.Preload("Milestones", orderMilestones).First(&project, projectID).Error; err != nil {
+		return nil, nil, errors.New("project not found")
+	}
+	if project.Status == model.ProjectStatusDraft || project.Status == model.ProjectStatusArchived {
+		if userID == 0 || !CanViewProject(s.DB, &project, userID) {
+			return nil, nil, errors.New("project not found")
+		}
+	}
+
+	var events []helper.ICSEvent
+	if event, ok := registrationDeadlineEvent(&project); ok {
+		events = append(events, event)
+	}
+	events = append(events, projectDateEvents(&project)...)
+	events = append(events, milestoneEvents(&project)...)
+
+	return helper.BuildICS(project.Title, events), &project, nil
+}
+
+// projectEvent fills in what every event of a project shares. The UID stays the same and the
+// sequence grows whenever the project is edited, so calendars update the event in place.
+func projectEvent(project *model.Project, kind, summary string) helper.ICSEvent {
+	return helper.ICSEvent{
+		UID:          fmt.Sprintf("project-%d-%s@%s", project.ID, kind, calendarUIDDomain),
+		Summary:      summary,
+		Description:  project.Title,
+		Location:     project.Location,
+		AllDay:       true,
+		Cancelled:    project.Status == model.ProjectStatusCancelled,
+		Sequence:     project.UpdatedAt.Unix(),
+		LastModified: project.UpdatedAt,
+	}
+}
+
+func registrationDeadlineEvent(project *model.Project) (helper.ICSEvent, bool) {
+	if project.RegistrationDeadline.IsZero() {
+		return helper.ICSEvent{}, false
+	}
+	event := projectEvent(project, "registration", "Registration closes: "+project.Title)
+	event.Start, event.End = project.RegistrationDeadline, project.RegistrationDeadline
+	return event, true
+}
+
+func projectDateEvents(project *model.Project) []helper.ICSEvent {
+	var events []helper.ICSEvent
+	if !project.StartDate.IsZero() {
+		event := projectEvent(project, "start", "Project starts: "+project.Title)
+		event.Start, event.End = project.StartDate, project.StartDate
+		events = append(events, event)
+	}
+	if !project.EndDate.IsZero() {
+		event := projectEvent(project, "end", "Project ends: "+project.Title)
+		event.Start, event.End = project.EndDate, project.EndDate
+		events = append(events, event)
+	}
+	return events
+}
+
+// milestoneEvents spans each milestone from its start to its due date. Milestones with only
+// one of the dates are shown on that day; those with neither are left out.
+func milestoneEvents(project *model.Project) []helper.ICSEvent {
+	var events []helper.ICSEvent
+	for _, milestone := range project.Milestones {
+		if milestone.StartDate == nil && milestone.DueDate == nil {
+			continue
+		}
+		// The event shows project fields too, so it changes with whichever was edited last
+		modified := milestone.UpdatedAt
+		if project.UpdatedAt.After(modified) {
+			modified = project.UpdatedAt
+		}
+		event := helper.ICSEvent{
+			UID:          fmt.Sprintf("milestone-%d@%s", milestone.ID, calendarUIDDomain),
+			Summary:      project.Title + ": " + milestone.Name,
+			Description:  milestone.Description,
+			AllDay:       true,
+			Cancelled:    project.Status == model.ProjectStatusCancelled,
+			Sequence:     modified.Unix(),
+			LastModified: modified,
+		}
+		if milestone.StartDate != nil {
+			event.Start, event.End = *milestone.StartDate, *milestone.StartDate
+		}
+		if milestone.DueDate != nil {
+			event.End = *milestone.DueDate
+			if milestone.StartDate == nil {
+				event.Start = *milestone.DueDate
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
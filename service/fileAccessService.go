@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	// cvLinkLifetime is how long a signed CV link stays valid
+	cvLinkLifetime = 10 * time.Minute
+	// attachmentLinkLifetime is how long the attachment links of a loaded feed stay valid
+	attachmentLinkLifetime = time.Hour
+)
+
+// Where the private file classes are stored
+const (
+	cvFilePrefix         = helper.PrivateFilePrefix + "cv/"
+	attachmentFilePrefix = helper.PrivateFilePrefix + "announcements/"
+)
+
+var (
+	ErrCVNotUploaded          = errors.New("user has not uploaded a CV")
+	ErrCVAccessDenied         = errors.New("you can only view the CV of users who applied to or joined a project you review")
+	ErrAttachmentAccessDenied = errors.New("you are not a member of this project")
+	ErrPrivateFileGone        = errors.New("file is no longer available")
+)
+
+// SignedFileLink is a time-limited link to a private file
+type SignedFileLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type FileAccessService struct {
+	DB *gorm.DB
+}
+
+func NewFileAccessService(db *gorm.DB) *FileAccessService {
+	return &FileAccessService{DB: db}
+}
+
+// cvAccessProject returns the project through which viewerID may see ownerID's CV: one where
+// the viewer reviews applications and the owner applied or is a member. It returns 0 for users
+// viewing their own CV.
+func (s *FileAccessService) cvAccessProject(ownerID, viewerID uint) (uint, error) {
+	if ownerID == viewerID {
+		return 0, nil
+	}
+
+	var projectIDs []uint
+	if err := s.DB.Model(&model.ProjectApplication{}).
+		Where("user_id = ? AND status <> ?", ownerID, model.ApplicationStatusWithdrawn).
+		Distinct().Pluck("project_id", &projectIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to load applications: %v", err)
+	}
+	var memberProjectIDs []uint
+	if err := s.DB.Model(&model.ProjectMember{}).
+		Where("user_id = ?", ownerID).
+		Distinct().Pluck("project_id", &memberProjectIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to load memberships: %v", err)
+	}
+	projectIDs = append(projectIDs, memberProjectIDs...)
+	if len(projectIDs) == 0 {
+		return 0, ErrCVAccessDenied
+	}
+
+	var projects []model.Project
+	if err := s.DB.Where("id IN ?", projectIDs).Order("id").Find(&projects).Error; err != nil {
+		return 0, fmt.Errorf("failed to load projects: %v", err)
+	}
+	for i := range projects {
+		allowed, err := HasProjectPermission(s.DB, &projects[i], viewerID, model.ProjectPermissionReviewApplications)
+		if err != nil {
+			return 0, err
+		}
+		if allowed {
+			return projects[i].ID, nil
+		}
+	}
+	return 0, ErrCVAccessDenied
+}
+
+// IssueCVLink returns a signed, expiring link to ownerID's CV if viewerID may see it
+func (s *FileAccessService) IssueCVLink(ownerID, viewerID uint) (*SignedFileLink, error) {
+	var profile model.Profiles
+	if err := s.DB.Select("cv_file").Where("user_id = ?", ownerID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCVNotUploaded
+		}
+		return nil, fmt.Errorf("failed to load profile: %v", err)
+	}
+	if profile.CVFile == "" {
+		return nil, ErrCVNotUploaded
+	}
+
+	projectID, err := s.cvAccessProject(ownerID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(cvLinkLifetime).Truncate(time.Second)
+	return &SignedFileLink{
+		URL: helper.SignFileURL(helper.SignedFile{
+			Path:      profile.CVFile,
+			OwnerID:   ownerID,
+			ViewerID:  viewerID,
+			ProjectID: projectID,
+			ExpiresAt: expiresAt,
+		}),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// announcementAttachmentURL returns the link an attachment is downloaded through by viewerID.
+// Attachments are private, so the link is signed for the viewer and the announcement's project.
+func announcementAttachmentURL(announcement *model.ProjectAnnouncement, attachment *model.ProjectAnnouncementAttachment, viewerID uint) string {
+	if !helper.IsPrivateFile(attachment.FilePath) {
+		return helper.GetUrlFile(attachment.FilePath)
+	}
+	return helper.SignFileURL(helper.SignedFile{
+		Path:      attachment.FilePath,
+		OwnerID:   announcement.AuthorID,
+		ViewerID:  viewerID,
+		ProjectID: announcement.ProjectID,
+		ExpiresAt: time.Now().Add(attachmentLinkLifetime).Truncate(time.Second),
+	})
+}
+
+// checkSignedFile makes sure the file a link points to is still there and the viewer may still
+// see it, and returns its file class. Links to a CV that has since been replaced stop working,
+// and so do attachment links of users who left the project.
+func (s *FileAccessService) checkSignedFile(file *helper.SignedFile) (string, error) {
+	switch {
+	case strings.HasPrefix(file.Path, cvFilePrefix):
+		var profile model.Profiles
+		if err := s.DB.Select("cv_file").Where("user_id = ?", file.OwnerID).First(&profile).Error; err != nil || profile.CVFile != file.Path {
+			return "", ErrPrivateFileGone
+		}
+		return model.FileTypeCV, nil
+
+	case strings.HasPrefix(file.Path, attachmentFilePrefix):
+		var count int64
+		if err := s.DB.Model(&model.ProjectAnnouncementAttachment{}).
+			Joins("JOIN project_announcements ON project_announcements.id = project_announcement_attachments.announcement_id").
+			Where("project_announcement_attachments.file_path = ? AND project_announcements.project_id = ?", file.Path, file.ProjectID).
+			Count(&count).Error; err != nil || count == 0 {
+			return "", ErrPrivateFileGone
+		}
+		var project model.Project
+		if err := s.DB.First(&project, file.ProjectID).Error; err != nil {
+			return "", ErrPrivateFileGone
+		}
+		if !CanViewProject(s.DB, &project, file.ViewerID) {
+			return "", ErrAttachmentAccessDenied
+		}
+		return model.FileTypeAnnouncementAttachment, nil
+	}
+	return "", ErrPrivateFileGone
+}
+
+// OpenSignedFile reads the private file a verified link points to and logs the download
+func (s *FileAccessService) OpenSignedFile(file *helper.SignedFile, ipAddress, userAgent string) ([]byte, error) {
+	fileType, err := s.checkSignedFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := helper.ReadFile(file.Path)
+	if err != nil {
+		if errors.Is(err, helper.ErrFileNotFound) {
+			return nil, ErrPrivateFileGone
+		}
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	entry := model.FileAccessLog{
+		OwnerID:   file.OwnerID,
+		ViewerID:  file.ViewerID,
+		FileType:  fileType,
+		FilePath:  file.Path,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+	if file.ProjectID != 0 {
+		entry.ProjectID = &file.ProjectID
+	}
+	if err := s.DB.Create(&entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to log file access: %v", err)
+	}
+	return data, nil
+}
+
+// GetCVViewsQuery returns a query over who downloaded ownerID's CV, newest first, for pagination.
+// Views of the owner's own CV are left out.
+func (s *FileAccessService) GetCVViewsQuery(ownerID uint) *gorm.DB {
+	return s.DB.Model(&model.FileAccessLog{}).
+		Preload("Viewer").
+		Preload("Project").
+		Where("owner_id = ? AND viewer_id <> ? AND file_type = ?", ownerID, ownerID, model.FileTypeCV).
+		Order("created_at DESC, id DESC")
+}
+
+// DecorateFileAccessLogs fills the viewer names and project titles of loaded log entries
+func DecorateFileAccessLogs(logs []*model.FileAccessLog) {
+	for _, entry := range logs {
+		entry.ViewerName = entry.Viewer.Name
+		if entry.Project != nil {
+			entry.ProjectTitle = entry.Project.Title
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// DefaultSearchRadiusKm and MaxSearchRadiusKm bound radius searches
+const (
+	DefaultSearchRadiusKm = 25
+	MaxSearchRadiusKm     = 1000
+)
+
+// profileCoordinateDecimals keeps profile coordinates to about a kilometre, so a user's exact
+// address is never stored or shown to other users
+const profileCoordinateDecimals = 2
+
+var locationModeWords = []struct {
+	mode  string
+	words []string
+}{
+	{model.LocationModeHybrid, []string{"hybrid", "hibrid"}},
+	{model.LocationModeRemote, []string{"remote", "online", "daring", "wfh", "work from home", "anywhere"}},
+	{model.LocationModeOnsite, []string{"onsite", "on site", "on-site", "offline", "luring", "wfo", "in person", "in-person"}},
+}
+
+// GeoLocationInput carries the typed location fields sent alongside a free-text location.
+// Nil coordinates and an empty mode were not sent.
+type GeoLocationInput struct {
+	Latitude  *float64
+	Longitude *float64
+	Mode      string
+}
+
+// NormalizeLocationMode accepts the location modes in any case and with "on-site" spelled out
+func NormalizeLocationMode(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case model.LocationModeRemote, model.LocationModeHybrid, model.LocationModeOnsite:
+		return mode, nil
+	case "on-site", "on_site", "on site":
+		return model.LocationModeOnsite, nil
+	}
+	return "", fmt.Errorf("location mode must be %s, %s or %s", model.LocationModeRemote, model.LocationModeHybrid, model.LocationModeOnsite)
+}
+
+// detectLocationMode guesses the mode from a free-text location such as "Remote" or
+// "Hybrid, Jakarta". It returns "" when the text doesn't say.
+func detectLocationMode(text string) string {
+	lower := strings.ToLower(text)
+	for _, entry := range locationModeWords {
+		for _, word := range entry.words {
+			if strings.Contains(lower, word) {
+				return entry.mode
+			}
+		}
+	}
+	return ""
+}
+
+// ResolveGeoLocation builds the structured location from the free text and typed input. The
+// geocoder normalises the city and region and supplies coordinates unless they were sent.
+func ResolveGeoLocation(text string, input GeoLocationInput) (model.GeoLocation, error) {
+	var location model.GeoLocation
+
+	if input.Mode != "" {
+		mode, err := NormalizeLocationMode(input.Mode)
+		if err != nil {
+			return location, err
+		}
+		location.LocationMode = mode
+	} else {
+		location.LocationMode = detectLocationMode(text)
+	}
+
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		return location, errors.New("latitude and longitude must be sent together")
+	}
+	if input.Latitude != nil && !helper.ValidCoordinates(*input.Latitude, *input.Longitude) {
+		return location, errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+
+	if place, err := helper.GetGeocoder().Geocode(text); err == nil {
+		location.City, location.Region = place.City, place.Region
+		location.Latitude, location.Longitude = &place.Latitude, &place.Longitude
+	} else if !errors.Is(err, helper.ErrPlaceNotFound) {
+		return location, fmt.Errorf("failed to look up location: %v", err)
+	}
+	if input.Latitude != nil {
+		location.Latitude, location.Longitude = input.Latitude, input.Longitude
+	}
+	return location, nil
+}
+
+// ResolveProfileGeoLocation resolves a profile location like ResolveGeoLocation, with the
+// coordinates rounded to profileCoordinateDecimals
+func ResolveProfileGeoLocation(text string, input GeoLocationInput) (model.GeoLocation, error) {
+	location, err := ResolveGeoLocation(text, input)
+	if err != nil || !location.HasCoordinates() {
+		return location, err
+	}
+	scale := math.Pow(10, profileCoordinateDecimals)
+	latitude := math.Round(*location.Latitude*scale) / scale
+	longitude := math.Round(*location.Longitude*scale) / scale
+	location.Latitude, location.Longitude = &latitude, &longitude
+	return location, nil
+}
+
+// GeoFilter narrows listings to a radius around a point, a city or region, and location modes.
+// Empty fields match everything.
+type GeoFilter struct {
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  float64
+
+	City   string
+	Region string
+	Modes  []string
+	// ExcludeRemote leaves remote rows out of radius searches, where their location doesn't matter
+	ExcludeRemote bool
+}
+
+// HasCenter reports whether the filter is a radius search
+func (f GeoFilter) HasCenter() bool {
+	return f.Latitude != nil && f.Longitude != nil
+}
+
+// distanceSQL is the Haversine distance in kilometres from the filter centre to the row
+func (f GeoFilter) distanceSQL(table string) clause.Expr {
+	return gorm.Expr(fmt.Sprintf(
+		"2 * 6371 * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(%[1]s.latitude - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(%[1]s.latitude)) * POWER(SIN(RADIANS(%[1]s.longitude - ?) / 2), 2))))",
+		table), *f.Latitude, *f.Latitude, *f.Longitude)
+}
+
+// Apply adds the filter conditions to a query over table. Radius searches first narrow rows to a
+// bounding box, which can use the latitude index, then check the exact distance and order by it.
+func (f GeoFilter) Apply(query *gorm.DB, table string) *gorm.DB {
+	if f.City != "" {
+		query = query.Where(fmt.Sprintf("LOWER(%s.city) = LOWER(?)", table), f.City)
+	}
+	if f.Region != "" {
+		query = query.Where(fmt.Sprintf("LOWER(%s.region) = LOWER(?)", table), f.Region)
+	}
+	if len(f.Modes) > 0 {
+		query = query.Where(fmt.Sprintf("%s.location_mode IN ?", table), f.Modes)
+	}
+	if !f.HasCenter() {
+		return query
+	}
+
+	radius := f.RadiusKm
+	if radius <= 0 {
+		radius = DefaultSearchRadiusKm
+	}
+	minLat, maxLat, minLon, maxLon := helper.BoundingBox(*f.Latitude, *f.Longitude, radius)
+	query = query.Where(fmt.Sprintf("%[1]s.latitude BETWEEN ? AND ? AND %[1]s.longitude BETWEEN ? AND ?", table), minLat, maxLat, minLon, maxLon)
+	if f.ExcludeRemote {
+		query = query.Where(fmt.Sprintf("%s.location_mode <> ?", table), model.LocationModeRemote)
+	}
+
+	distance := f.distanceSQL(table)
+	return query.
+		Where(gorm.Expr("? <= ?", distance, radius)).
+		Clauses(clause.OrderBy{Expression: gorm.Expr("? ASC", distance)})
+}
+
+// DistanceKm returns the distance from the filter centre to a location, rounded to 100 metres,
+// or nil when either has no coordinates
+func (f GeoFilter) DistanceKm(location model.GeoLocation) *float64 {
+	if !f.HasCenter() || !location.HasCoordinates() {
+		return nil
+	}
+	distance := math.Round(helper.HaversineKm(*f.Latitude, *f.Longitude, *location.Latitude, *location.Longitude)*10) / 10
+	return &distance
+}
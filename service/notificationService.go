@@ -2,17 +2,29 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
 	"synergazing.com/synergazing/model"
 )
 
+// notificationGroupWindow is how long after the first event new events of the same group are folded into it
+const notificationGroupWindow = 24 * time.Hour
+
 type NotificationService struct {
 	DB *gorm.DB
 }
 
+// NotificationActor identifies a user whose action is folded into a grouped notification
+type NotificationActor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
 func NewNotificationService(db *gorm.DB) *NotificationService {
 	return &NotificationService{DB: db}
 }
@@ -29,13 +41,15 @@ func (s *NotificationService) CreateNotification(userID uint, projectID *uint, n
 	}
 
 	notification := &model.Notification{
-		UserID:    userID,
-		ProjectID: projectID,
-		Type:      notificationType,
-		Title:     title,
-		Message:   message,
-		IsRead:    false,
-		Data:      dataJSON,
+		UserID:      userID,
+		ProjectID:   projectID,
+		Type:        notificationType,
+		Title:       title,
+		Message:     message,
+		IsRead:      false,
+		Data:        dataJSON,
+		EventCount:  1,
+		LastEventAt: time.Now(),
 	}
 
 	if err := s.DB.Create(notification).Error; err != nil {
@@ -45,26 +59,175 @@ func (s *NotificationService) CreateNotification(userID uint, projectID *uint, n
 	return notification, nil
 }
 
-// GetUserNotifications retrieves notifications for a specific user
-func (s *NotificationService) GetUserNotifications(userID uint, limit, offset int) ([]model.Notification, error) {
+// CreateGroupedNotification folds an event into the user's open notification with the same type and project,
+// or starts a new one. The actor list and count are updated in place and the notification is marked unread again.
+// render builds the title and message from the number of distinct actors and the actors, most recent first.
+func (s *NotificationService) CreateGroupedNotification(userID uint, projectID *uint, notificationType string, actor NotificationActor, data map[string]interface{}, render func(count int, actors []NotificationActor) (string, string)) (*model.Notification, error) {
+	groupKey := notificationType
+	if projectID != nil {
+		groupKey = fmt.Sprintf("%s:%d", notificationType, *projectID)
+	}
+
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	tx := s.DB.Begin()
+
+	// Events are handled in parallel, so serialise on the group before looking it up; a row lock
+	// would not stop two events from both finding no group and each creating one
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", fmt.Sprintf("notification:%d:%s", userID, groupKey)).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to lock notification group: %v", err)
+	}
+
+	var notification model.Notification
+	err := tx.Where("user_id = ? AND group_key = ? AND created_at >= ?", userID, groupKey, time.Now().Add(-notificationGroupWindow)).
+		Order("created_at DESC").
+		First(&notification).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to find grouped notification: %v", err)
+	}
+
+	actors := []NotificationActor{actor}
+	if err == nil {
+		var existing struct {
+			Actors []NotificationActor `json:"actors"`
+		}
+		if notification.Data != "" {
+			if err := json.Unmarshal([]byte(notification.Data), &existing); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to read actors of notification %d: %v", notification.ID, err)
+			}
+		}
+		for _, a := range existing.Actors {
+			if a.ID != actor.ID {
+				actors = append(actors, a)
+			}
+		}
+	}
+
+	title, message := render(len(actors), actors)
+	data["actors"] = actors
+	data["actor_count"] = len(actors)
+
+	dataBytes, marshalErr := json.Marshal(data)
+	if marshalErr != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to marshal notification data: %v", marshalErr)
+	}
+
+	now := time.Now()
+	if err == gorm.ErrRecordNotFound {
+		notification = model.Notification{
+			UserID:      userID,
+			ProjectID:   projectID,
+			Type:        notificationType,
+			Title:       title,
+			Message:     message,
+			IsRead:      false,
+			Data:        string(dataBytes),
+			GroupKey:    groupKey,
+			EventCount:  len(actors),
+			LastEventAt: now,
+		}
+		if err := tx.Create(&notification).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create notification: %v", err)
+		}
+	} else {
+		if err := tx.Model(&notification).Updates(map[string]interface{}{
+			"title":         title,
+			"message":       message,
+			"data":          string(dataBytes),
+			"event_count":   len(actors),
+			"is_read":       false,
+			"last_event_at": now,
+		}).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to update grouped notification: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to save notification: %v", err)
+	}
+
+	return &notification, nil
+}
+
+// withoutDeletedProjects hides notifications about projects that are in the trash
+func withoutDeletedProjects(query *gorm.DB) *gorm.DB {
+	return query.Where("project_id IS NULL OR " + notDeletedProjectCondition)
+}
+
+// NotificationFilter narrows the notifications of a user; zero values match everything
+type NotificationFilter struct {
+	Types     []string
+	ProjectID *uint
+	IsRead    *bool
+	From      *time.Time
+	// To is exclusive
+	To *time.Time
+}
+
+// IsEmpty reports whether the filter has no criteria set
+func (f NotificationFilter) IsEmpty() bool {
+	return len(f.Types) == 0 && f.ProjectID == nil && f.IsRead == nil && f.From == nil && f.To == nil
+}
+
+func (f NotificationFilter) apply(query *gorm.DB) *gorm.DB {
+	if len(f.Types) > 0 {
+		query = query.Where("type IN ?", f.Types)
+	}
+	if f.ProjectID != nil {
+		query = query.Where("project_id = ?", *f.ProjectID)
+	}
+	if f.IsRead != nil {
+		query = query.Where("is_read = ?", *f.IsRead)
+	}
+	if f.From != nil {
+		query = query.Where("last_event_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("last_event_at < ?", *f.To)
+	}
+	return query
+}
+
+// GetUserNotifications retrieves a page of filtered notifications for a user, newest activity first.
+// Pages are keyed by cursor; the returned cursor is empty when there are no more results.
+func (s *NotificationService) GetUserNotifications(userID uint, filter NotificationFilter, cursor string, limit int) ([]model.Notification, string, error) {
 	var notifications []model.Notification
 
-	query := s.DB.Where("user_id = ?", userID).
+	query := filter.apply(s.DB.Where("user_id = ?", userID)).
+		Scopes(withoutDeletedProjects).
 		Preload("Project").
-		Order("created_at DESC")
+		Order("last_event_at DESC, id DESC")
 
-	if limit > 0 {
-		query = query.Limit(limit)
+	if cursor != "" {
+		lastEventAt, lastID, err := helper.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("(last_event_at < ?) OR (last_event_at = ? AND id < ?)", lastEventAt, lastEventAt, lastID)
 	}
-	if offset > 0 {
-		query = query.Offset(offset)
+
+	// Fetch one extra row to know whether another page exists
+	if err := query.Limit(limit + 1).Find(&notifications).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to get user notifications: %v", err)
 	}
 
-	if err := query.Find(&notifications).Error; err != nil {
-		return nil, fmt.Errorf("failed to get user notifications: %v", err)
+	nextCursor := ""
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		last := notifications[len(notifications)-1]
+		nextCursor = helper.EncodeCursor(last.LastEventAt, last.ID)
 	}
 
-	return notifications, nil
+	return notifications, nextCursor, nil
 }
 
 // GetUnreadNotifications retrieves unread notifications for a user
@@ -72,8 +235,9 @@ func (s *NotificationService) GetUnreadNotifications(userID uint) ([]model.Notif
 	var notifications []model.Notification
 
 	if err := s.DB.Where("user_id = ? AND is_read = ?", userID, false).
+		Scopes(withoutDeletedProjects).
 		Preload("Project").
-		Order("created_at DESC").
+		Order("last_event_at DESC").
 		Find(&notifications).Error; err != nil {
 		return nil, fmt.Errorf("failed to get unread notifications: %v", err)
 	}
@@ -86,20 +250,52 @@ func (s *NotificationService) GetUnreadCount(userID uint) (int64, error) {
 	var count int64
 	if err := s.DB.Model(&model.Notification{}).
 		Where("user_id = ? AND is_read = ?", userID, false).
+		Scopes(withoutDeletedProjects).
 		Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("failed to get unread count: %v", err)
 	}
 	return count, nil
 }
 
+// GetUnreadCountsByType returns the number of unread notifications per type for a user
+func (s *NotificationService) GetUnreadCountsByType(userID uint) (map[string]int64, error) {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	if err := s.DB.Model(&model.Notification{}).
+		Select("type, COUNT(*) AS count").
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Scopes(withoutDeletedProjects).
+		Group("type").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %v", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
+
 // MarkAsRead marks a notification as read
 func (s *NotificationService) MarkAsRead(notificationID, userID uint) error {
+	return s.setReadState(notificationID, userID, true)
+}
+
+// MarkAsUnread marks a notification as unread
+func (s *NotificationService) MarkAsUnread(notificationID, userID uint) error {
+	return s.setReadState(notificationID, userID, false)
+}
+
+func (s *NotificationService) setReadState(notificationID, userID uint, isRead bool) error {
 	result := s.DB.Model(&model.Notification{}).
 		Where("id = ? AND user_id = ?", notificationID, userID).
-		Update("is_read", true)
+		Update("is_read", isRead)
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to mark notification as read: %v", result.Error)
+		return fmt.Errorf("failed to update notification: %v", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("notification not found or unauthorized")
@@ -130,45 +326,51 @@ func (s *NotificationService) DeleteNotification(notificationID, userID uint) er
 	return nil
 }
 
-// Project-specific notification methods
-
-// NotifyDeadlineApproaching sends notifications to project members about approaching deadline
-func (s *NotificationService) NotifyDeadlineApproaching(projectID uint) error {
-	var project model.Project
-	if err := s.DB.Preload("Members.User").First(&project, projectID).Error; err != nil {
-		return fmt.Errorf("failed to find project: %v", err)
+// bulkQuery scopes a bulk action to the user's notifications matching ids, or filter when no ids are given
+func (s *NotificationService) bulkQuery(userID uint, ids []uint, filter NotificationFilter) (*gorm.DB, error) {
+	if len(ids) == 0 && filter.IsEmpty() {
+		return nil, errors.New("provide notification ids or at least one filter")
 	}
 
-	daysUntilDeadline := int(time.Until(project.RegistrationDeadline).Hours() / 24)
-
-	title := "Project Deadline Approaching"
-	message := fmt.Sprintf("The registration deadline for project '%s' is in %d days", project.Title, daysUntilDeadline)
+	query := s.DB.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if len(ids) > 0 {
+		return query.Where("id IN ?", ids), nil
+	}
+	return filter.apply(query), nil
+}
 
-	data := map[string]interface{}{
-		"project_id":            project.ID,
-		"project_title":         project.Title,
-		"registration_deadline": project.RegistrationDeadline,
-		"days_until_deadline":   daysUntilDeadline,
+// BulkSetReadState marks the selected notifications as read or unread and returns how many changed
+func (s *NotificationService) BulkSetReadState(userID uint, ids []uint, filter NotificationFilter, isRead bool) (int64, error) {
+	query, err := s.bulkQuery(userID, ids, filter)
+	if err != nil {
+		return 0, err
 	}
 
-	// Notify project creator
-	if _, err := s.CreateNotification(project.CreatorID, &projectID, model.NotificationTypeDeadlineApproaching, title, message, data); err != nil {
-		return fmt.Errorf("failed to notify project creator: %v", err)
+	result := query.Where("is_read = ?", !isRead).Update("is_read", isRead)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to update notifications: %v", result.Error)
 	}
+	return result.RowsAffected, nil
+}
 
-	// Notify all project members
-	for _, member := range project.Members {
-		if member.UserID != project.CreatorID { // Don't double-notify creator
-			if _, err := s.CreateNotification(member.UserID, &projectID, model.NotificationTypeDeadlineApproaching, title, message, data); err != nil {
-				return fmt.Errorf("failed to notify project member: %v", err)
-			}
-		}
+// BulkDelete deletes the selected notifications and returns how many were removed
+func (s *NotificationService) BulkDelete(userID uint, ids []uint, filter NotificationFilter) (int64, error) {
+	query, err := s.bulkQuery(userID, ids, filter)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	result := query.Delete(&model.Notification{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %v", result.Error)
+	}
+	return result.RowsAffected, nil
 }
 
-// NotifyUserRegistered notifies project creator when someone registers for their project
+// Project-specific notification methods
+
+// NotifyUserRegistered notifies project creator when someone registers for their project.
+// Applications to the same project are grouped into a single notification.
 func (s *NotificationService) NotifyUserRegistered(projectID, applicantUserID uint) error {
 	var project model.Project
 	var applicant model.Users
@@ -181,9 +383,6 @@ func (s *NotificationService) NotifyUserRegistered(projectID, applicantUserID ui
 		return fmt.Errorf("failed to find applicant: %v", err)
 	}
 
-	title := "New Project Application"
-	message := fmt.Sprintf("%s has applied to join your project '%s'", applicant.Name, project.Title)
-
 	data := map[string]interface{}{
 		"project_id":      project.ID,
 		"project_title":   project.Title,
@@ -192,8 +391,27 @@ func (s *NotificationService) NotifyUserRegistered(projectID, applicantUserID ui
 		"applicant_email": applicant.Email,
 	}
 
-	_, err := s.CreateNotification(project.CreatorID, &projectID, model.NotificationTypeUserRegistered, title, message, data)
-	return err
+	// Everyone who can review applications is told about new ones
+	reviewers, err := projectUsersWithPermission(s.DB, &project, model.ProjectPermissionReviewApplications)
+	if err != nil {
+		return err
+	}
+
+	actor := NotificationActor{ID: applicant.ID, Name: applicant.Name}
+	for _, reviewerID := range reviewers {
+		if _, err := s.CreateGroupedNotification(reviewerID, &projectID, model.NotificationTypeUserRegistered, actor, data,
+			func(count int, actors []NotificationActor) (string, string) {
+				if count == 1 {
+					return "New Project Application",
+						fmt.Sprintf("%s has applied to join your project '%s'", actors[0].Name, project.Title)
+				}
+				return fmt.Sprintf("%d new applicants to %s", count, project.Title),
+					fmt.Sprintf("%s and %d others have applied to join your project '%s'", actors[0].Name, count-1, project.Title)
+			}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NotifyUserAccepted notifies user when they're accepted into a project
@@ -261,26 +479,60 @@ func (s *NotificationService) NotifyRoleAssigned(projectID, userID uint, roleTit
 	return err
 }
 
-// NotifyProjectStatusChange notifies relevant users about project status changes
-func (s *NotificationService) NotifyProjectStatusChange(projectID uint, newStatus string) error {
+// NotifyProjectStatusChange notifies the accepted members of a project about a lifecycle status change
+func (s *NotificationService) NotifyProjectStatusChange(projectID uint, previousStatus, newStatus string) error {
 	var project model.Project
-	if err := s.DB.Preload("Members.User").First(&project, projectID).Error; err != nil {
+	if err := s.DB.Preload("Members", "status = ?", "accepted").First(&project, projectID).Error; err != nil {
 		return fmt.Errorf("failed to find project: %v", err)
 	}
 
+	notificationType := model.NotificationTypeProjectStatusChange
 	title := "Project Status Update"
 	message := fmt.Sprintf("Project '%s' status has been updated to: %s", project.Title, newStatus)
+	if newStatus == model.ProjectStatusCompleted {
+		notificationType = model.NotificationTypeProjectCompleted
+		title = "Project Completed"
+		message = fmt.Sprintf("Project '%s' has been completed. Thank you for your contribution!", project.Title)
+	}
 
 	data := map[string]interface{}{
 		"project_id":    project.ID,
 		"project_title": project.Title,
 		"new_status":    newStatus,
-		"old_status":    project.Status,
+		"old_status":    previousStatus,
 	}
 
-	// Notify all project members
+	// Notify all accepted members
 	for _, member := range project.Members {
-		if _, err := s.CreateNotification(member.UserID, &projectID, model.NotificationTypeProjectStatusChange, title, message, data); err != nil {
+		if _, err := s.CreateNotification(member.UserID, &projectID, notificationType, title, message, data); err != nil {
+			return fmt.Errorf("failed to notify project member: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// NotifyProjectUpdated notifies members that material fields of a project were edited
+func (s *NotificationService) NotifyProjectUpdated(projectID, editorID uint, fields []string, recipients []uint) error {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return fmt.Errorf("failed to find project: %v", err)
+	}
+
+	title := "Project Updated"
+	message := fmt.Sprintf("The %s of project '%s' changed. Check the project for details.", describeChangedFields(fields), project.Title)
+
+	data := map[string]interface{}{
+		"project_id":    project.ID,
+		"project_title": project.Title,
+		"fields":        fields,
+	}
+
+	for _, userID := range recipients {
+		if userID == editorID {
+			continue
+		}
+		if _, err := s.CreateNotification(userID, &projectID, model.NotificationTypeProjectUpdated, title, message, data); err != nil {
 			return fmt.Errorf("failed to notify project member: %v", err)
 		}
 	}
@@ -288,6 +540,225 @@ func (s *NotificationService) NotifyProjectStatusChange(projectID uint, newStatu
 	return nil
 }
 
+// loadTaskForNotification loads a task with its project; a task deleted since the event yields nil
+func (s *NotificationService) loadTaskForNotification(taskID uint) (*model.ProjectTask, error) {
+	var task model.ProjectTask
+	err := s.DB.Preload("Project").First(&task, taskID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %v", err)
+	}
+	return &task, nil
+}
+
+// notifyTaskRecipients sends a task notification to every recipient except the actor
+func (s *NotificationService) notifyTaskRecipients(task *model.ProjectTask, actorID uint, recipients []uint, notificationType, title, message string, data map[string]interface{}) error {
+	data["project_id"] = task.ProjectID
+	data["project_title"] = task.Project.Title
+	data["task_id"] = task.ID
+	data["task_title"] = task.Title
+
+	for _, userID := range recipients {
+		if userID == actorID {
+			continue
+		}
+		if _, err := s.CreateNotification(userID, &task.ProjectID, notificationType, title, message, data); err != nil {
+			return fmt.Errorf("failed to notify task recipient: %v", err)
+		}
+	}
+	return nil
+}
+
+// NotifyTaskAssigned notifies a user that a task was assigned to them
+func (s *NotificationService) NotifyTaskAssigned(taskID, actorID uint, recipients []uint) error {
+	task, err := s.loadTaskForNotification(taskID)
+	if err != nil || task == nil {
+		return err
+	}
+
+	return s.notifyTaskRecipients(task, actorID, recipients, model.NotificationTypeTaskAssigned,
+		"Task Assigned",
+		fmt.Sprintf("You have been assigned the task '%s' in project '%s'", task.Title, task.Project.Title),
+		map[string]interface{}{})
+}
+
+// NotifyTaskUpdated notifies the assignee and creator of a task that its status changed
+func (s *NotificationService) NotifyTaskUpdated(taskID, actorID uint, previousStatus, newStatus string, recipients []uint) error {
+	task, err := s.loadTaskForNotification(taskID)
+	if err != nil || task == nil {
+		return err
+	}
+
+	return s.notifyTaskRecipients(task, actorID, recipients, model.NotificationTypeTaskUpdated,
+		"Task Updated",
+		fmt.Sprintf("Task '%s' in project '%s' moved from %s to %s", task.Title, task.Project.Title,
+			strings.ReplaceAll(previousStatus, "_", " "), strings.ReplaceAll(newStatus, "_", " ")),
+		map[string]interface{}{
+			"previous_status": previousStatus,
+			"status":          newStatus,
+		})
+}
+
+// NotifyTaskCommented notifies the people following a task about a new comment
+func (s *NotificationService) NotifyTaskCommented(taskID, actorID uint, recipients []uint) error {
+	task, err := s.loadTaskForNotification(taskID)
+	if err != nil || task == nil {
+		return err
+	}
+
+	var actor model.Users
+	if err := s.DB.First(&actor, actorID).Error; err != nil {
+		return fmt.Errorf("failed to find commenter: %v", err)
+	}
+
+	return s.notifyTaskRecipients(task, actorID, recipients, model.NotificationTypeTaskCommented,
+		"New Task Comment",
+		fmt.Sprintf("%s commented on task '%s' in project '%s'", actor.Name, task.Title, task.Project.Title),
+		map[string]interface{}{
+			"commenter_id":   actor.ID,
+			"commenter_name": actor.Name,
+		})
+}
+
+// loadAnnouncementForNotification loads an announcement with its project; one deleted since the event yields nil
+func (s *NotificationService) loadAnnouncementForNotification(announcementID uint) (*model.ProjectAnnouncement, *model.Project, error) {
+	var announcement model.ProjectAnnouncement
+	err := s.DB.Preload("Author").First(&announcement, announcementID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find announcement: %v", err)
+	}
+
+	var project model.Project
+	if err := s.DB.First(&project, announcement.ProjectID).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to find project: %v", err)
+	}
+	return &announcement, &project, nil
+}
+
+// notifyAnnouncementRecipients sends an announcement notification to every recipient except the actor
+func (s *NotificationService) notifyAnnouncementRecipients(announcement *model.ProjectAnnouncement, project *model.Project, actorID uint, recipients []uint, notificationType, title, message string, data map[string]interface{}) error {
+	data["project_id"] = project.ID
+	data["project_title"] = project.Title
+	data["announcement_id"] = announcement.ID
+	data["announcement_title"] = announcement.Title
+
+	for _, userID := range recipients {
+		if userID == actorID {
+			continue
+		}
+		if _, err := s.CreateNotification(userID, &project.ID, notificationType, title, message, data); err != nil {
+			return fmt.Errorf("failed to notify announcement recipient: %v", err)
+		}
+	}
+	return nil
+}
+
+// NotifyAnnouncementPosted notifies everyone on a project about a new announcement
+func (s *NotificationService) NotifyAnnouncementPosted(announcementID, authorID uint, recipients []uint) error {
+	announcement, project, err := s.loadAnnouncementForNotification(announcementID)
+	if err != nil || announcement == nil {
+		return err
+	}
+
+	return s.notifyAnnouncementRecipients(announcement, project, authorID, recipients, model.NotificationTypeAnnouncement,
+		"New Announcement",
+		fmt.Sprintf("%s posted '%s' in project '%s'", announcement.Author.Name, announcement.Title, project.Title),
+		map[string]interface{}{
+			"author_id":   announcement.AuthorID,
+			"author_name": announcement.Author.Name,
+		})
+}
+
+// NotifyAnnouncementCommented notifies the author and earlier commenters of an announcement about a new comment
+func (s *NotificationService) NotifyAnnouncementCommented(announcementID, actorID uint, recipients []uint) error {
+	announcement, project, err := s.loadAnnouncementForNotification(announcementID)
+	if err != nil || announcement == nil {
+		return err
+	}
+
+	var actor model.Users
+	if err := s.DB.First(&actor, actorID).Error; err != nil {
+		return fmt.Errorf("failed to find commenter: %v", err)
+	}
+
+	return s.notifyAnnouncementRecipients(announcement, project, actorID, recipients, model.NotificationTypeAnnouncementComment,
+		"New Announcement Comment",
+		fmt.Sprintf("%s commented on '%s' in project '%s'", actor.Name, announcement.Title, project.Title),
+		map[string]interface{}{
+			"commenter_id":   actor.ID,
+			"commenter_name": actor.Name,
+		})
+}
+
+// loadQuestionForNotification loads a question with its asker and project; one deleted since the event yields nil
+func (s *NotificationService) loadQuestionForNotification(questionID uint) (*model.ProjectQuestion, *model.Project, error) {
+	var question model.ProjectQuestion
+	err := s.DB.Preload("Asker").First(&question, questionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find question: %v", err)
+	}
+
+	var project model.Project
+	if err := s.DB.First(&project, question.ProjectID).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to find project: %v", err)
+	}
+	return &question, &project, nil
+}
+
+// NotifyQuestionAsked notifies the people who can answer that a question was asked on their project
+func (s *NotificationService) NotifyQuestionAsked(questionID uint, recipients []uint) error {
+	question, project, err := s.loadQuestionForNotification(questionID)
+	if err != nil || question == nil {
+		return err
+	}
+
+	title := "New Question"
+	message := fmt.Sprintf("%s asked a question about project '%s'", question.Asker.Name, project.Title)
+	data := map[string]interface{}{
+		"project_id":    project.ID,
+		"project_title": project.Title,
+		"question_id":   question.ID,
+		"asker_id":      question.AskerID,
+	}
+
+	for _, userID := range recipients {
+		if userID == question.AskerID {
+			continue
+		}
+		if _, err := s.CreateNotification(userID, &project.ID, model.NotificationTypeQuestionAsked, title, message, data); err != nil {
+			return fmt.Errorf("failed to notify project owner: %v", err)
+		}
+	}
+	return nil
+}
+
+// NotifyQuestionAnswered notifies the asker that their question was answered
+func (s *NotificationService) NotifyQuestionAnswered(questionID uint) error {
+	question, project, err := s.loadQuestionForNotification(questionID)
+	if err != nil || question == nil {
+		return err
+	}
+
+	title := "Question Answered"
+	message := fmt.Sprintf("Your question about project '%s' has been answered", project.Title)
+	data := map[string]interface{}{
+		"project_id":    project.ID,
+		"project_title": project.Title,
+		"question_id":   question.ID,
+	}
+
+	_, err = s.CreateNotification(question.AskerID, &project.ID, model.NotificationTypeQuestionAnswered, title, message, data)
+	return err
+}
+
 // NotifyInvitationReceived notifies user when they receive a project invitation
 func (s *NotificationService) NotifyInvitationReceived(projectID, userID uint, roleTitle string) error {
 	var project model.Project
@@ -312,37 +783,3 @@ func (s *NotificationService) NotifyInvitationReceived(projectID, userID uint, r
 	_, err := s.CreateNotification(userID, &projectID, model.NotificationTypeInvitationReceived, title, message, data)
 	return err
 }
-
-// CheckAndNotifyApproachingDeadlines checks for projects with approaching deadlines
-func (s *NotificationService) CheckAndNotifyApproachingDeadlines() error {
-	// Check for deadlines in 1, 3, and 7 days
-	deadlineDays := []int{1, 3, 7}
-
-	for _, days := range deadlineDays {
-		targetDate := time.Now().AddDate(0, 0, days)
-		startOfDay := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, targetDate.Location())
-		endOfDay := startOfDay.Add(24 * time.Hour)
-
-		var projects []model.Project
-		if err := s.DB.Where("registration_deadline >= ? AND registration_deadline < ? AND status != ?",
-			startOfDay, endOfDay, "completed").Find(&projects).Error; err != nil {
-			return fmt.Errorf("failed to find projects with approaching deadlines: %v", err)
-		}
-
-		for _, project := range projects {
-			// Check if we already sent a notification for this deadline
-			var existingNotification model.Notification
-			err := s.DB.Where("project_id = ? AND type = ? AND created_at >= ?",
-				project.ID, model.NotificationTypeDeadlineApproaching, startOfDay).First(&existingNotification).Error
-
-			if err == gorm.ErrRecordNotFound {
-				// No notification sent yet, send one
-				if err := s.NotifyDeadlineApproaching(project.ID); err != nil {
-					return fmt.Errorf("failed to notify approaching deadline for project %d: %v", project.ID, err)
-				}
-			}
-		}
-	}
-
-	return nil
-}
@@ -15,10 +15,14 @@ import (
 	"synergazing.com/synergazing/model"
 )
 
-type OTPService struct{}
+type OTPService struct {
+	OutboxService *OutboxService
+}
 
 func NewOTPService() *OTPService {
-	return &OTPService{}
+	return &OTPService{
+		OutboxService: NewOutboxService(config.GetDB()),
+	}
 }
 
 func (s *OTPService) GenerateOTP() (string, error) {
@@ -41,7 +45,9 @@ func (s *OTPService) SendOTP(email, purpose string) error {
 		return errors.New("failed to generate OTP")
 	}
 
-	db.Where("email = ? AND purpose = ? AND is_used = ?", email, purpose, false).Delete(&model.OTP{})
+	tx := db.Begin()
+
+	tx.Where("email = ? AND purpose = ? AND is_used = ?", email, purpose, false).Delete(&model.OTP{})
 
 	otp := model.OTP{
 		Email:     email,
@@ -51,12 +57,26 @@ func (s *OTPService) SendOTP(email, purpose string) error {
 		IsUsed:    false,
 	}
 
-	if err := db.Create(&otp).Error; err != nil {
+	if err := tx.Create(&otp).Error; err != nil {
+		tx.Rollback()
 		log.Printf("Database error creating OTP: %v", err)
 		return errors.New("failed to create OTP")
 	}
 
-	go s.sendOTPEmail(email, code, purpose)
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicOTPEmail, OTPEmailPayload{
+		Email:   email,
+		Code:    code,
+		Purpose: purpose,
+	}); err != nil {
+		tx.Rollback()
+		log.Printf("Database error queueing OTP email: %v", err)
+		return errors.New("failed to send OTP")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Printf("Database error committing OTP: %v", err)
+		return errors.New("failed to create OTP")
+	}
 
 	return nil
 }
@@ -83,18 +103,20 @@ func (s *OTPService) VerifyOTP(email, code, purpose string) error {
 	return nil
 }
 
-func (s *OTPService) CleanupExpiredOTPs() {
+func (s *OTPService) CleanupExpiredOTPs() error {
 	db := config.GetDB()
 
 	result := db.Where("expires_at < ?", time.Now()).Delete(&model.OTP{})
 	if result.Error != nil {
-		log.Printf("Error cleaning up expired OTPs: %v", result.Error)
-	} else if result.RowsAffected > 0 {
+		return fmt.Errorf("failed to clean up expired OTPs: %v", result.Error)
+	}
+	if result.RowsAffected > 0 {
 		log.Printf("Cleaned up %d expired OTP records", result.RowsAffected)
 	}
+	return nil
 }
 
-func (s *OTPService) sendOTPEmail(email, code, purpose string) {
+func sendOTPEmail(email, code, purpose string) error {
 	emailHost := os.Getenv("EMAIL_HOST")
 	emailPortStr := os.Getenv("EMAIL_PORT")
 	emailUser := os.Getenv("EMAIL_USERNAME")
@@ -102,8 +124,7 @@ func (s *OTPService) sendOTPEmail(email, code, purpose string) {
 
 	emailPort, err := strconv.Atoi(emailPortStr)
 	if err != nil {
-		log.Printf("Error: Could not parse EMAIL_PORT from .env file: %v", err)
-		return
+		return fmt.Errorf("could not parse EMAIL_PORT: %v", err)
 	}
 
 	m := gomail.NewMessage()
@@ -198,8 +219,9 @@ func (s *OTPService) sendOTPEmail(email, code, purpose string) {
 	d := gomail.NewDialer(emailHost, emailPort, emailUser, emailPass)
 
 	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Could not send OTP email to %s: %v", email, err)
-	} else {
-		log.Printf("OTP email sent successfully to %s for purpose: %s", email, purpose)
+		return fmt.Errorf("could not send OTP email to %s: %w", email, err)
 	}
+
+	log.Printf("OTP email sent successfully to %s for purpose: %s", email, purpose)
+	return nil
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/textproto"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	outboxBaseBackoff   = 30 * time.Second
+	outboxMaxBackoff    = 1 * time.Hour
+	outboxStaleClaimAge = 5 * time.Minute
+)
+
+// OutboxHandler delivers a single outbox payload
+type OutboxHandler func(payload []byte) error
+
+type OutboxService struct {
+	DB       *gorm.DB
+	handlers map[string]OutboxHandler
+}
+
+func NewOutboxService(db *gorm.DB) *OutboxService {
+	s := &OutboxService{
+		DB:       db,
+		handlers: make(map[string]OutboxHandler),
+	}
+	s.registerDefaultHandlers()
+	return s
+}
+
+// OTPEmailPayload is the outbox payload for OTP emails
+type OTPEmailPayload struct {
+	Email   string `json:"email"`
+	Code    string `json:"code"`
+	Purpose string `json:"purpose"`
+}
+
+// PasswordResetEmailPayload is the outbox payload for password reset emails
+type PasswordResetEmailPayload struct {
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+// NotificationPayload is the outbox payload for project notifications
+type NotificationPayload struct {
+	ProjectID      uint     `json:"project_id"`
+	UserID         uint     `json:"user_id"`
+	RoleTitle      string   `json:"role_title,omitempty"`
+	Status         string   `json:"status,omitempty"`
+	PreviousStatus string   `json:"previous_status,omitempty"`
+	Fields         []string `json:"fields,omitempty"`
+	Recipients     []uint   `json:"recipients,omitempty"`
+	TaskID         uint     `json:"task_id,omitempty"`
+	AnnouncementID uint     `json:"announcement_id,omitempty"`
+	QuestionID     uint     `json:"question_id,omitempty"`
+}
+
+// permanentError marks a delivery failure that retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// PermanentError wraps err so the outbox dead-letters the message instead of retrying it
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// RegisterHandler registers the delivery handler for a topic
+func (s *OutboxService) RegisterHandler(topic string, handler OutboxHandler) {
+	s.handlers[topic] = handler
+}
+
+func (s *OutboxService) registerDefaultHandlers() {
+	s.RegisterHandler(model.OutboxTopicOTPEmail, func(payload []byte) error {
+		var p OTPEmailPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return PermanentError(err)
+		}
+		return classifySMTPError(sendOTPEmail(p.Email, p.Code, p.Purpose))
+	})
+
+	s.RegisterHandler(model.OutboxTopicPasswordResetEmail, func(payload []byte) error {
+		var p PasswordResetEmailPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return PermanentError(err)
+		}
+		return classifySMTPError(helper.SendPasswordResetEmail(p.Email, p.Token))
+	})
+
+	s.RegisterHandler(model.OutboxTopicWebhookDelivery, func(payload []byte) error {
+		var p WebhookDeliveryPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return PermanentError(err)
+		}
+		return NewWebhookService(s.DB, s).Deliver(p.DeliveryID)
+	})
+
+	notificationHandlers := map[string]func(ns *NotificationService, p NotificationPayload) error{
+		model.OutboxTopicNotifyUserRegistered: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyUserRegistered(p.ProjectID, p.UserID)
+		},
+		model.OutboxTopicNotifyUserAccepted: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyUserAccepted(p.ProjectID, p.UserID, p.RoleTitle)
+		},
+		model.OutboxTopicNotifyUserRejected: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyUserRejected(p.ProjectID, p.UserID)
+		},
+		model.OutboxTopicNotifyRoleAssigned: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyRoleAssigned(p.ProjectID, p.UserID, p.RoleTitle)
+		},
+		model.OutboxTopicNotifyInvitation: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyInvitationReceived(p.ProjectID, p.UserID, p.RoleTitle)
+		},
+		model.OutboxTopicNotifyStatusChange: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyProjectStatusChange(p.ProjectID, p.PreviousStatus, p.Status)
+		},
+		model.OutboxTopicNotifyProjectUpdated: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyProjectUpdated(p.ProjectID, p.UserID, p.Fields, p.Recipients)
+		},
+		model.OutboxTopicNotifyTaskAssigned: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyTaskAssigned(p.TaskID, p.UserID, p.Recipients)
+		},
+		model.OutboxTopicNotifyTaskUpdated: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyTaskUpdated(p.TaskID, p.UserID, p.PreviousStatus, p.Status, p.Recipients)
+		},
+		model.OutboxTopicNotifyTaskCommented: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyTaskCommented(p.TaskID, p.UserID, p.Recipients)
+		},
+		model.OutboxTopicNotifyAnnouncement: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyAnnouncementPosted(p.AnnouncementID, p.UserID, p.Recipients)
+		},
+		model.OutboxTopicNotifyAnnouncementComment: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyAnnouncementCommented(p.AnnouncementID, p.UserID, p.Recipients)
+		},
+		model.OutboxTopicNotifyQuestionAsked: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyQuestionAsked(p.QuestionID, p.Recipients)
+		},
+		model.OutboxTopicNotifyQuestionAnswered: func(ns *NotificationService, p NotificationPayload) error {
+			return ns.NotifyQuestionAnswered(p.QuestionID)
+		},
+	}
+
+	for topic, notify := range notificationHandlers {
+		notify := notify
+		s.RegisterHandler(topic, func(payload []byte) error {
+			var p NotificationPayload
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return PermanentError(err)
+			}
+			return notify(NewNotificationService(s.DB), p)
+		})
+	}
+}
+
+// classifySMTPError treats 5xx SMTP replies as permanent failures
+func classifySMTPError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return PermanentError(err)
+	}
+	return err
+}
+
+// Enqueue writes a message to the outbox using tx, so it commits or rolls back with the caller's changes
+func (s *OutboxService) Enqueue(tx *gorm.DB, topic string, payload interface{}) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %v", err)
+	}
+
+	message := &model.OutboxMessage{
+		Topic:         topic,
+		Payload:       string(payloadBytes),
+		Status:        model.OutboxStatusPending,
+		MaxAttempts:   8,
+		NextAttemptAt: time.Now(),
+	}
+
+	if err := tx.Create(message).Error; err != nil {
+		return fmt.Errorf("failed to enqueue outbox message: %v", err)
+	}
+
+	return nil
+}
+
+// ProcessPending claims up to limit due messages and delivers them, returning how many were claimed
+func (s *OutboxService) ProcessPending(limit int) (int, error) {
+	var messages []model.OutboxMessage
+	now := time.Now()
+
+	tx := s.DB.Begin()
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			model.OutboxStatusPending, now, model.OutboxStatusProcessing, now.Add(-outboxStaleClaimAge)).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to claim outbox messages: %v", err)
+	}
+
+	if len(messages) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	ids := make([]uint, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	if err := tx.Model(&model.OutboxMessage{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":     model.OutboxStatusProcessing,
+			"updated_at": now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("failed to claim outbox messages: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %v", err)
+	}
+
+	for i := range messages {
+		s.deliver(&messages[i])
+	}
+
+	return len(messages), nil
+}
+
+func (s *OutboxService) deliver(message *model.OutboxMessage) {
+	handler, ok := s.handlers[message.Topic]
+	var err error
+	if !ok {
+		err = PermanentError(fmt.Errorf("no handler registered for topic %s", message.Topic))
+	} else {
+		err = handler([]byte(message.Payload))
+	}
+
+	attempts := message.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts": attempts,
+	}
+
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		now := time.Now()
+		updates["status"] = model.OutboxStatusDelivered
+		updates["delivered_at"] = &now
+		updates["last_error"] = ""
+	case errors.As(err, &permanent) || attempts >= message.MaxAttempts:
+		updates["status"] = model.OutboxStatusDead
+		updates["last_error"] = err.Error()
+		log.Printf("Outbox message %d (%s) dead-lettered after %d attempts: %v", message.ID, message.Topic, attempts, err)
+	default:
+		updates["status"] = model.OutboxStatusPending
+		updates["last_error"] = err.Error()
+		updates["next_attempt_at"] = time.Now().Add(outboxBackoff(attempts))
+		log.Printf("Outbox message %d (%s) failed attempt %d: %v", message.ID, message.Topic, attempts, err)
+	}
+
+	if err := s.DB.Model(message).Updates(updates).Error; err != nil {
+		log.Printf("Failed to update outbox message %d: %v", message.ID, err)
+	}
+}
+
+// outboxBackoff doubles the retry delay with every attempt, capped at outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
+
+// GetMessagesQuery returns a query over outbox messages filtered by status and topic, for pagination
+func (s *OutboxService) GetMessagesQuery(status, topic string) *gorm.DB {
+	query := s.DB.Model(&model.OutboxMessage{}).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if topic != "" {
+		query = query.Where("topic = ?", topic)
+	}
+	return query
+}
+
+// GetMessage retrieves a single outbox message
+func (s *OutboxService) GetMessage(messageID uint) (*model.OutboxMessage, error) {
+	var message model.OutboxMessage
+	if err := s.DB.First(&message, messageID).Error; err != nil {
+		return nil, errors.New("outbox message not found")
+	}
+	return &message, nil
+}
+
+// RetryMessage puts a dead or pending message back in the queue for immediate delivery
+func (s *OutboxService) RetryMessage(messageID uint) (*model.OutboxMessage, error) {
+	message, err := s.GetMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.Status != model.OutboxStatusDead && message.Status != model.OutboxStatusPending {
+		return nil, fmt.Errorf("cannot retry a message with status %s", message.Status)
+	}
+
+	if err := s.DB.Model(message).Updates(map[string]interface{}{
+		"status":          model.OutboxStatusPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"last_error":      "",
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to retry outbox message: %v", err)
+	}
+
+	return message, nil
+}
+
+// GetStatusCounts returns the number of outbox messages per status
+func (s *OutboxService) GetStatusCounts() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.DB.Model(&model.OutboxMessage{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count outbox messages: %v", err)
+	}
+
+	counts := map[string]int64{
+		model.OutboxStatusPending:    0,
+		model.OutboxStatusProcessing: 0,
+		model.OutboxStatusDelivered:  0,
+		model.OutboxStatusDead:       0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -18,6 +18,7 @@ type UpdateProfileDTO struct {
 	Password       *string
 	AboutMe        *string
 	Location       *string
+	GeoLocation    *GeoLocationInput
 	Interests      *string
 	Academic       *string
 	WebsiteURL     *string
@@ -125,6 +126,18 @@ func (s *ProfileService) UpdateUserProfile(userId uint, data *UpdateProfileDTO)
 	if data.Location != nil {
 		profile.Location = *data.Location
 	}
+	if data.Location != nil || data.GeoLocation != nil {
+		var input GeoLocationInput
+		if data.GeoLocation != nil {
+			input = *data.GeoLocation
+		}
+		location, err := ResolveProfileGeoLocation(profile.Location, input)
+		if err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		profile.GeoLocation = location
+	}
 	if data.Interests != nil {
 		profile.Interests = *data.Interests
 	}
@@ -237,14 +250,6 @@ func (s *ProfileService) GetPublicUserProfile(userId uint) (*model.Users, *model
 	return &user, &profile, nil
 }
 
-func (s *ProfileService) GetCVFilePath(userId uint) (string, error) {
-	var profile model.Profiles
-	if err := s.DB.Select("cv_file").Where("user_id = ?", userId).First(&profile).Error; err != nil {
-		return "", err
-	}
-	return profile.CVFile, nil
-}
-
 func (s *ProfileService) DeleteProfilePicture(userId uint) error {
 	tx := s.DB.Begin()
 	if tx.Error != nil {
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/model"
+)
+
+// projectAccessPermissions lists the permissions granted by each access level.
+// The primary owner (the project creator) holds every permission.
+var projectAccessPermissions = map[string][]string{
+	model.ProjectAccessOwner: {
+		model.ProjectPermissionEdit,
+		model.ProjectPermissionDelete,
+		model.ProjectPermissionManageMembers,
+		model.ProjectPermissionReviewApplications,
+		model.ProjectPermissionManageAccess,
+		model.ProjectPermissionManageWebhooks,
+		model.ProjectPermissionViewAnalytics,
+	},
+	model.ProjectAccessManager: {
+		model.ProjectPermissionEdit,
+		model.ProjectPermissionManageMembers,
+		model.ProjectPermissionReviewApplications,
+	},
+	model.ProjectAccessReviewer: {
+		model.ProjectPermissionReviewApplications,
+	},
+}
+
+// ProjectAccessLevels lists every access level, most privileged first
+var ProjectAccessLevels = []string{
+	model.ProjectAccessOwner,
+	model.ProjectAccessManager,
+	model.ProjectAccessReviewer,
+}
+
+// ErrProjectPermissionDenied is returned when a user lacks the permission an action needs
+var ErrProjectPermissionDenied = errors.New("you do not have permission to perform this action on this project")
+
+// projectAccessCondition matches projects on which a user holds an access level
+const projectAccessCondition = "id IN (SELECT project_id FROM project_accesses WHERE user_id = ?)"
+
+// ProjectPermissions returns the permissions userID holds on project
+func ProjectPermissions(db *gorm.DB, project *model.Project, userID uint) ([]string, error) {
+	if project.CreatorID == userID {
+		return append(projectAccessPermissions[model.ProjectAccessOwner], model.ProjectPermissionTransferOwnership), nil
+	}
+
+	var access model.ProjectAccess
+	err := db.Where("project_id = ? AND user_id = ?", project.ID, userID).First(&access).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project access: %v", err)
+	}
+	return projectAccessPermissions[access.Level], nil
+}
+
+// HasProjectPermission reports whether userID holds permission on project
+func HasProjectPermission(db *gorm.DB, project *model.Project, userID uint, permission string) (bool, error) {
+	permissions, err := ProjectPermissions(db, project, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, granted := range permissions {
+		if granted == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AuthorizeProject loads a project and checks that userID holds permission on it
+func AuthorizeProject(db *gorm.DB, projectID, userID uint, permission string) (*model.Project, error) {
+	var project model.Project
+	if err := db.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+
+	allowed, err := HasProjectPermission(db, &project, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrProjectPermissionDenied
+	}
+	return &project, nil
+}
+
+// CanViewProject reports whether userID is the creator, holds an access level or is an accepted member of project
+func CanViewProject(db *gorm.DB, project *model.Project, userID uint) bool {
+	if project.CreatorID == userID {
+		return true
+	}
+
+	var count int64
+	db.Model(&model.ProjectAccess{}).
+		Where("project_id = ? AND user_id = ?", project.ID, userID).
+		Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	db.Model(&model.ProjectMember{}).
+		Where("project_id = ? AND user_id = ? AND status = ?", project.ID, userID, "accepted").
+		Count(&count)
+	return count > 0
+}
+
+// projectUsersWithPermission returns the creator and every user whose access level grants permission
+func projectUsersWithPermission(db *gorm.DB, project *model.Project, permission string) ([]uint, error) {
+	var levels []string
+	for level, permissions := range projectAccessPermissions {
+		for _, granted := range permissions {
+			if granted == permission {
+				levels = append(levels, level)
+			}
+		}
+	}
+
+	var userIDs []uint
+	if len(levels) > 0 {
+		if err := db.Model(&model.ProjectAccess{}).
+			Where("project_id = ? AND level IN ?", project.ID, levels).
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to load project access: %v", err)
+		}
+	}
+	return append([]uint{project.CreatorID}, userIDs...), nil
+}
+
+type ProjectAccessService struct {
+	DB *gorm.DB
+}
+
+func NewProjectAccessService(db *gorm.DB) *ProjectAccessService {
+	return &ProjectAccessService{DB: db}
+}
+
+// ProjectAccessOverview lists who can manage a project
+type ProjectAccessOverview struct {
+	Owner  model.Users           `json:"owner"`
+	Grants []model.ProjectAccess `json:"grants"`
+}
+
+// GetAccess lists the owner and access grants of a project; visible to anyone who can view the project
+func (s *ProjectAccessService) GetAccess(projectID, userID uint) (*ProjectAccessOverview, error) {
+	var project model.Project
+	if err := s.DB.Preload("Creator").First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	if !CanViewProject(s.DB, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+
+	var grants []model.ProjectAccess
+	if err := s.DB.Preload("User").
+		Where("project_id = ?", projectID).
+		Order("created_at ASC").
+		Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("failed to get project access: %v", err)
+	}
+
+	return &ProjectAccessOverview{Owner: project.Creator, Grants: grants}, nil
+}
+
+// GetMyPermissions returns the permissions the user holds on a project
+func (s *ProjectAccessService) GetMyPermissions(projectID, userID uint) ([]string, error) {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+
+	permissions, err := ProjectPermissions(s.DB, &project, userID)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return permissions, nil
+}
+
+// GrantAccess gives a user an access level on a project, replacing any level they already hold
+func (s *ProjectAccessService) GrantAccess(projectID, granterID, targetUserID uint, level string) (*model.ProjectAccess, error) {
+	if _, known := projectAccessPermissions[level]; !known {
+		return nil, fmt.Errorf("invalid access level: %s. Must be one of: %s", level, strings.Join(ProjectAccessLevels, ", "))
+	}
+
+	project, err := AuthorizeProject(s.DB, projectID, granterID, model.ProjectPermissionManageAccess)
+	if err != nil {
+		return nil, err
+	}
+	if project.Status == model.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+
+	if targetUserID == project.CreatorID {
+		return nil, errors.New("the project owner already has full access")
+	}
+
+	var user model.Users
+	if err := s.DB.First(&user, targetUserID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	access := &model.ProjectAccess{
+		ProjectID: projectID,
+		UserID:    targetUserID,
+		Level:     level,
+		GrantedBy: granterID,
+	}
+
+	tx := s.DB.Begin()
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"level", "granted_by", "updated_at"}),
+	}).Create(access).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to grant access: %v", err)
+	}
+
+	if _, err := NewNotificationService(tx).CreateNotification(targetUserID, &projectID, model.NotificationTypeAccessGranted,
+		"Project Access Granted",
+		fmt.Sprintf("You are now a %s of project '%s'", level, project.Title),
+		map[string]interface{}{
+			"project_id":    projectID,
+			"project_title": project.Title,
+			"level":         level,
+		}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to grant access: %v", err)
+	}
+
+	if err := s.DB.Preload("User").Where("project_id = ? AND user_id = ?", projectID, targetUserID).First(access).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project access: %v", err)
+	}
+	return access, nil
+}
+
+// RevokeAccess removes a user's access level; users can always give up their own access
+func (s *ProjectAccessService) RevokeAccess(projectID, userID, targetUserID uint) error {
+	if userID != targetUserID {
+		if _, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionManageAccess); err != nil {
+			return err
+		}
+	}
+
+	result := s.DB.Where("project_id = ? AND user_id = ?", projectID, targetUserID).Delete(&model.ProjectAccess{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke access: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("project access not found")
+	}
+	return nil
+}
+
+// TransferOwnership makes another user the primary owner of a project.
+// The previous owner stays on as a manager.
+func (s *ProjectAccessService) TransferOwnership(projectID, ownerID, newOwnerID uint) (*model.Project, error) {
+	project, err := AuthorizeProject(s.DB, projectID, ownerID, model.ProjectPermissionTransferOwnership)
+	if err != nil {
+		return nil, err
+	}
+	if newOwnerID == ownerID {
+		return nil, errors.New("you already own this project")
+	}
+
+	var newOwner model.Users
+	if err := s.DB.First(&newOwner, newOwnerID).Error; err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	tx := s.DB.Begin()
+
+	if err := tx.Model(project).Update("creator_id", newOwnerID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to transfer ownership: %v", err)
+	}
+
+	if err := tx.Where("project_id = ? AND user_id = ?", projectID, newOwnerID).Delete(&model.ProjectAccess{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to transfer ownership: %v", err)
+	}
+
+	previousOwner := &model.ProjectAccess{
+		ProjectID: projectID,
+		UserID:    ownerID,
+		Level:     model.ProjectAccessManager,
+		GrantedBy: ownerID,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"level", "granted_by", "updated_at"}),
+	}).Create(previousOwner).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to transfer ownership: %v", err)
+	}
+
+	if _, err := NewNotificationService(tx).CreateNotification(newOwnerID, &projectID, model.NotificationTypeOwnershipTransfer,
+		"Project Ownership Transferred",
+		fmt.Sprintf("You are now the owner of project '%s'", project.Title),
+		map[string]interface{}{
+			"project_id":     projectID,
+			"project_title":  project.Title,
+			"previous_owner": ownerID,
+		}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to transfer ownership: %v", err)
+	}
+
+	return project, nil
+}
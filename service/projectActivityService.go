@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+type ProjectActivityService struct {
+	DB *gorm.DB
+}
+
+func NewProjectActivityService(db *gorm.DB) *ProjectActivityService {
+	return &ProjectActivityService{DB: db}
+}
+
+// recordActivity adds an entry to the activity feed of a project inside tx
+func recordActivity(tx *gorm.DB, projectID, actorID uint, activityType, message string, data map[string]interface{}) error {
+	var dataJSON string
+	if data != nil {
+		dataBytes, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal activity data: %v", err)
+		}
+		dataJSON = string(dataBytes)
+	}
+
+	activity := &model.ProjectActivity{
+		ProjectID: projectID,
+		ActorID:   actorID,
+		Type:      activityType,
+		Message:   message,
+		Data:      dataJSON,
+	}
+	if err := tx.Create(activity).Error; err != nil {
+		return fmt.Errorf("failed to record activity: %v", err)
+	}
+	return nil
+}
+
+// GetActivityQuery returns a query over the activity feed of a project, newest first, for pagination.
+// The feed is visible to anyone on the project.
+func (s *ProjectActivityService) GetActivityQuery(projectID, userID uint, types []string) (*gorm.DB, error) {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+
+	if !CanViewProject(s.DB, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+
+	query := s.DB.Model(&model.ProjectActivity{}).
+		Preload("Actor").
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC")
+	if len(types) > 0 {
+		query = query.Where("type IN ?", types)
+	}
+	return query, nil
+}
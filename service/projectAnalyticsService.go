@@ -0,0 +1,315 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	analyticsDateLayout = "2006-01-02"
+	// defaultAnalyticsDays is the range returned when none is asked for
+	defaultAnalyticsDays = 30
+	// maxAnalyticsDays caps the range of a single analytics request
+	maxAnalyticsDays = 366
+)
+
+// ProjectViewer identifies who opened a public project page
+type ProjectViewer struct {
+	UserID    uint
+	IPAddress string
+	UserAgent string
+}
+
+// key identifies the viewer for daily deduplication. Anonymous viewers are keyed by a keyed hash,
+// so their IP address is never stored.
+func (v ProjectViewer) key() string {
+	if v.UserID != 0 {
+		return "user:" + strconv.FormatUint(uint64(v.UserID), 10)
+	}
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	fmt.Fprintf(mac, "%s\n%s", v.IPAddress, v.UserAgent)
+	return "anon:" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// ProjectFunnel counts how far viewers got, from viewing the project to a decision on their
+// application. Views are counted once per viewer per day and viewers once over the whole range.
+// Rates are percentages; review times are in hours and nil when nothing was reviewed.
+type ProjectFunnel struct {
+	Views               int64    `json:"views"`
+	Viewers             int64    `json:"viewers"`
+	Applied             int      `json:"applied"`
+	Pending             int      `json:"pending"`
+	Accepted            int      `json:"accepted"`
+	Rejected            int      `json:"rejected"`
+	Withdrawn           int      `json:"withdrawn"`
+	ViewToApplyRate     float64  `json:"view_to_apply_rate"`
+	AcceptanceRate      float64  `json:"acceptance_rate"`
+	AvgHoursToReview    *float64 `json:"avg_hours_to_review"`
+	MedianHoursToReview *float64 `json:"median_hours_to_review"`
+
+	reviewHours []float64
+}
+
+// RoleFunnel is the funnel of one role. Views are counted per project, so every role shares them.
+type RoleFunnel struct {
+	RoleID   uint   `json:"role_id"`
+	RoleName string `json:"role_name"`
+	ProjectFunnel
+}
+
+// AnalyticsPoint is one day of a project's analytics. Applications are dated by when they were
+// sent, decisions by when they were reviewed and withdrawals by when they happened.
+type AnalyticsPoint struct {
+	Date         string `json:"date"`
+	Views        int64  `json:"views"`
+	Applications int    `json:"applications"`
+	Accepted     int    `json:"accepted"`
+	Rejected     int    `json:"rejected"`
+	Withdrawn    int    `json:"withdrawn"`
+}
+
+// ProjectAnalytics covers the applications sent and views made between From and To, inclusive
+type ProjectAnalytics struct {
+	ProjectID uint             `json:"project_id"`
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Funnel    ProjectFunnel    `json:"funnel"`
+	Roles     []RoleFunnel     `json:"roles"`
+	Series    []AnalyticsPoint `json:"series"`
+}
+
+type ProjectAnalyticsService struct {
+	DB *gorm.DB
+}
+
+func NewProjectAnalyticsService(db *gorm.DB) *ProjectAnalyticsService {
+	return &ProjectAnalyticsService{DB: db}
+}
+
+// RecordView counts a view of a public project page, once per viewer per day. Views by people
+// who manage the project are not counted.
+func (s *ProjectAnalyticsService) RecordView(project *model.Project, viewer ProjectViewer) error {
+	var viewerID *uint
+	if viewer.UserID != 0 {
+		permissions, err := ProjectPermissions(s.DB, project, viewer.UserID)
+		if err != nil {
+			return err
+		}
+		if len(permissions) > 0 {
+			return nil
+		}
+		viewerID = &viewer.UserID
+	}
+
+	// The day is passed as text so it is the app's local date whatever the database time zone
+	err := s.DB.Exec(`INSERT INTO project_views (project_id, view_date, viewer_key, viewer_id, created_at)
+		VALUES (?, ?, ?, ?, NOW()) ON CONFLICT DO NOTHING`,
+		project.ID, time.Now().Format(analyticsDateLayout), viewer.key(), viewerID).Error
+	if err != nil {
+		return fmt.Errorf("failed to record project view: %v", err)
+	}
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// ParseAnalyticsRange reads the from and to dates (YYYY-MM-DD) of an analytics request. Both
+// are optional: the range defaults to the last 30 days, ending today.
+func ParseAnalyticsRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	to := startOfDay(time.Now())
+	if toStr != "" {
+		parsed, err := time.ParseInLocation(analyticsDateLayout, toStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date such as 2025-01-31")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if fromStr != "" {
+		parsed, err := time.ParseInLocation(analyticsDateLayout, fromStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date such as 2025-01-01")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from cannot be after to")
+	}
+	if days := int(math.Round(to.Sub(from).Hours()/24)) + 1; days > maxAnalyticsDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range can be at most %d days", maxAnalyticsDays)
+	}
+	return from, to, nil
+}
+
+// GetAnalytics returns the views, application funnel per role and daily time series of a
+// project between from and to, inclusive. Only the project's owners can see them.
+func (s *ProjectAnalyticsService) GetAnalytics(projectID, userID uint, from, to time.Time) (*ProjectAnalytics, error) {
+	project, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionViewAnalytics)
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+
+	analytics := &ProjectAnalytics{
+		ProjectID: project.ID,
+		From:      from.Format(analyticsDateLayout),
+		To:        to.Format(analyticsDateLayout),
+		Roles:     []RoleFunnel{},
+	}
+
+	series := make(map[string]*AnalyticsPoint)
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(analyticsDateLayout)
+		analytics.Series = append(analytics.Series, AnalyticsPoint{Date: date})
+	}
+	for i := range analytics.Series {
+		series[analytics.Series[i].Date] = &analytics.Series[i]
+	}
+	addToSeries := func(t time.Time, add func(point *AnalyticsPoint)) {
+		if point, ok := series[t.In(time.Local).Format(analyticsDateLayout)]; ok {
+			add(point)
+		}
+	}
+
+	// Views are stored one row per viewer per day
+	var dailyViews []struct {
+		ViewDate time.Time
+		Views    int64
+	}
+	if err := s.DB.Model(&model.ProjectView{}).
+		Select("view_date, COUNT(*) AS views").
+		Where("project_id = ? AND view_date BETWEEN ? AND ?", project.ID, analytics.From, analytics.To).
+		Group("view_date").
+		Scan(&dailyViews).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project views: %v", err)
+	}
+	for _, day := range dailyViews {
+		analytics.Funnel.Views += day.Views
+		if point, ok := series[day.ViewDate.Format(analyticsDateLayout)]; ok {
+			point.Views = day.Views
+		}
+	}
+	if err := s.DB.Model(&model.ProjectView{}).
+		Where("project_id = ? AND view_date BETWEEN ? AND ?", project.ID, analytics.From, analytics.To).
+		Distinct("viewer_key").
+		Count(&analytics.Funnel.Viewers).Error; err != nil {
+		return nil, fmt.Errorf("failed to count project viewers: %v", err)
+	}
+
+	var roles []model.ProjectRole
+	if err := s.DB.Where("project_id = ?", project.ID).Order("id").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project roles: %v", err)
+	}
+	roleFunnels := make(map[uint]*RoleFunnel, len(roles))
+	for _, role := range roles {
+		analytics.Roles = append(analytics.Roles, RoleFunnel{RoleID: role.ID, RoleName: role.Name})
+	}
+	for i := range analytics.Roles {
+		analytics.Roles[i].Views = analytics.Funnel.Views
+		analytics.Roles[i].Viewers = analytics.Funnel.Viewers
+		roleFunnels[analytics.Roles[i].RoleID] = &analytics.Roles[i]
+	}
+
+	var applications []model.ProjectApplication
+	if err := s.DB.Select("id", "project_role_id", "status", "applied_at", "reviewed_at", "updated_at").
+		Where("project_id = ? AND applied_at >= ? AND applied_at < ?", project.ID, from, end).
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applications: %v", err)
+	}
+	for _, application := range applications {
+		funnels := []*ProjectFunnel{&analytics.Funnel}
+		if role, ok := roleFunnels[application.ProjectRoleID]; ok {
+			funnels = append(funnels, &role.ProjectFunnel)
+		}
+		for _, funnel := range funnels {
+			funnel.countApplication(application)
+		}
+
+		addToSeries(application.AppliedAt, func(point *AnalyticsPoint) { point.Applications++ })
+		switch application.Status {
+		case model.ApplicationStatusAccepted:
+			if application.ReviewedAt != nil {
+				addToSeries(*application.ReviewedAt, func(point *AnalyticsPoint) { point.Accepted++ })
+			}
+		case model.ApplicationStatusRejected:
+			if application.ReviewedAt != nil {
+				addToSeries(*application.ReviewedAt, func(point *AnalyticsPoint) { point.Rejected++ })
+			}
+		case model.ApplicationStatusWithdrawn:
+			addToSeries(application.UpdatedAt, func(point *AnalyticsPoint) { point.Withdrawn++ })
+		}
+	}
+
+	analytics.Funnel.finish()
+	for i := range analytics.Roles {
+		analytics.Roles[i].finish()
+	}
+	return analytics, nil
+}
+
+func (f *ProjectFunnel) countApplication(application model.ProjectApplication) {
+	f.Applied++
+	switch application.Status {
+	case model.ApplicationStatusPending:
+		f.Pending++
+	case model.ApplicationStatusAccepted:
+		f.Accepted++
+	case model.ApplicationStatusRejected:
+		f.Rejected++
+	case model.ApplicationStatusWithdrawn:
+		f.Withdrawn++
+	}
+	if application.ReviewedAt != nil && !application.AppliedAt.IsZero() {
+		f.reviewHours = append(f.reviewHours, application.ReviewedAt.Sub(application.AppliedAt).Hours())
+	}
+}
+
+// finish works out the rates and review times once every application is counted
+func (f *ProjectFunnel) finish() {
+	f.ViewToApplyRate = percentage(int64(f.Applied), f.Viewers)
+	f.AcceptanceRate = percentage(int64(f.Accepted), int64(f.Accepted+f.Rejected))
+
+	if len(f.reviewHours) == 0 {
+		return
+	}
+	sort.Float64s(f.reviewHours)
+	total := 0.0
+	for _, hours := range f.reviewHours {
+		total += hours
+	}
+	avg := roundTenth(total / float64(len(f.reviewHours)))
+
+	middle := len(f.reviewHours) / 2
+	median := f.reviewHours[middle]
+	if len(f.reviewHours)%2 == 0 {
+		median = (f.reviewHours[middle-1] + f.reviewHours[middle]) / 2
+	}
+	median = roundTenth(median)
+	f.AvgHoursToReview, f.MedianHoursToReview = &avg, &median
+}
+
+func percentage(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return roundTenth(float64(part) * 100 / float64(whole))
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
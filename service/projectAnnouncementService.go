@@ -0,0 +1,567 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// maxAnnouncementAttachments limits how many files can be uploaded with one announcement
+const maxAnnouncementAttachments = 5
+
+type ProjectAnnouncementService struct {
+	DB            *gorm.DB
+	OutboxService *OutboxService
+}
+
+func NewProjectAnnouncementService(db *gorm.DB, outboxService *OutboxService) *ProjectAnnouncementService {
+	return &ProjectAnnouncementService{
+		DB:            db,
+		OutboxService: outboxService,
+	}
+}
+
+func isValidAnnouncementReaction(reaction string) bool {
+	for _, valid := range model.AnnouncementReactions {
+		if valid == reaction {
+			return true
+		}
+	}
+	return false
+}
+
+// projectAudience returns everyone on a project: the creator, users with an access level and accepted members
+func projectAudience(tx *gorm.DB, project *model.Project) ([]uint, error) {
+	var userIDs []uint
+	if err := tx.Raw(`SELECT user_id FROM project_accesses WHERE project_id = ?
+		UNION SELECT user_id FROM project_members WHERE project_id = ? AND status = ?`,
+		project.ID, project.ID, "accepted").
+		Scan(&userIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project members: %v", err)
+	}
+
+	audience := []uint{project.CreatorID}
+	for _, userID := range userIDs {
+		if userID != project.CreatorID {
+			audience = append(audience, userID)
+		}
+	}
+	return audience, nil
+}
+
+// loadAnnouncementProject loads a project the user takes part in; write access also requires it not to be archived
+func (s *ProjectAnnouncementService) loadAnnouncementProject(tx *gorm.DB, projectID, userID uint, write bool) (*model.Project, error) {
+	var project model.Project
+	if err := tx.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	if !CanViewProject(tx, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+	if write && project.Status == model.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+	return &project, nil
+}
+
+func (s *ProjectAnnouncementService) getAnnouncement(tx *gorm.DB, projectID, announcementID uint) (*model.ProjectAnnouncement, error) {
+	var announcement model.ProjectAnnouncement
+	if err := tx.Preload("Attachments").
+		Where("id = ? AND project_id = ?", announcementID, projectID).
+		First(&announcement).Error; err != nil {
+		return nil, errors.New("announcement not found")
+	}
+	return &announcement, nil
+}
+
+// canModerate reports whether the user may pin, edit or delete any announcement of the project
+func (s *ProjectAnnouncementService) canModerate(tx *gorm.DB, project *model.Project, userID uint) (bool, error) {
+	return HasProjectPermission(tx, project, userID, model.ProjectPermissionEdit)
+}
+
+// CreateAnnouncement posts an announcement with optional attachments and notifies everyone on the project.
+// Only owners and managers can post.
+func (s *ProjectAnnouncementService) CreateAnnouncement(projectID, userID uint, title, body string, pinned bool, files []*multipart.FileHeader) (*model.ProjectAnnouncement, error) {
+	title = strings.TrimSpace(title)
+	body = strings.TrimSpace(body)
+	if title == "" || body == "" {
+		return nil, errors.New("title and body are required")
+	}
+	if len(files) > maxAnnouncementAttachments {
+		return nil, fmt.Errorf("an announcement can have at most %d attachments", maxAnnouncementAttachments)
+	}
+
+	tx := s.DB.Begin()
+	project, err := s.loadAnnouncementProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	allowed, err := s.canModerate(tx, project, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !allowed {
+		tx.Rollback()
+		return nil, errors.New("only owners and managers can post announcements")
+	}
+
+	announcement := &model.ProjectAnnouncement{
+		ProjectID: projectID,
+		AuthorID:  userID,
+		Title:     title,
+		Body:      body,
+		Pinned:    pinned,
+	}
+	if pinned {
+		now := time.Now()
+		announcement.PinnedAt = &now
+	}
+	if err := tx.Create(announcement).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create announcement: %v", err)
+	}
+
+	var uploaded []string
+	for _, file := range files {
+		filePath, err := helper.UploadFile(file, "announcement")
+		if err != nil {
+			tx.Rollback()
+			for _, path := range uploaded {
+				helper.DeleteFile(path)
+			}
+			return nil, err
+		}
+		uploaded = append(uploaded, filePath)
+
+		attachment := &model.ProjectAnnouncementAttachment{
+			AnnouncementID: announcement.ID,
+			FileName:       file.Filename,
+			FilePath:       filePath,
+			Size:           file.Size,
+		}
+		if err := tx.Create(attachment).Error; err != nil {
+			tx.Rollback()
+			for _, path := range uploaded {
+				helper.DeleteFile(path)
+			}
+			return nil, fmt.Errorf("failed to save attachment: %v", err)
+		}
+	}
+
+	if err := recordActivity(tx, projectID, userID, model.ActivityTypeAnnouncement,
+		fmt.Sprintf("Posted announcement '%s'", announcement.Title),
+		map[string]interface{}{"announcement_id": announcement.ID}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	recipients, err := projectAudience(tx, project)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyAnnouncement, NotificationPayload{
+		ProjectID:      projectID,
+		UserID:         userID,
+		AnnouncementID: announcement.ID,
+		Recipients:     recipients,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		for _, path := range uploaded {
+			helper.DeleteFile(path)
+		}
+		return nil, fmt.Errorf("failed to create announcement: %v", err)
+	}
+
+	return s.GetAnnouncement(projectID, announcement.ID, userID)
+}
+
+// GetAnnouncementsQuery returns a query over the announcements of a project for pagination,
+// pinned posts first and the rest newest first
+func (s *ProjectAnnouncementService) GetAnnouncementsQuery(projectID, userID uint) (*gorm.DB, error) {
+	if _, err := s.loadAnnouncementProject(s.DB, projectID, userID, false); err != nil {
+		return nil, err
+	}
+
+	return s.DB.Model(&model.ProjectAnnouncement{}).
+		Preload("Author").
+		Preload("Attachments").
+		Where("project_id = ?", projectID).
+		Order("pinned DESC, pinned_at DESC, created_at DESC, id DESC"), nil
+}
+
+// DecorateAnnouncements fills in signed attachment links, comment counts and reactions as seen by userID
+func (s *ProjectAnnouncementService) DecorateAnnouncements(announcements []*model.ProjectAnnouncement, userID uint) error {
+	if len(announcements) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(announcements))
+	byID := make(map[uint]*model.ProjectAnnouncement, len(announcements))
+	for _, announcement := range announcements {
+		ids = append(ids, announcement.ID)
+		byID[announcement.ID] = announcement
+		announcement.Reactions = make(map[string]int64)
+		announcement.MyReactions = []string{}
+		for _, attachment := range announcement.Attachments {
+			attachment.URL = announcementAttachmentURL(announcement, attachment, userID)
+		}
+	}
+
+	var commentCounts []struct {
+		AnnouncementID uint
+		Count          int64
+	}
+	if err := s.DB.Model(&model.ProjectAnnouncementComment{}).
+		Select("announcement_id, COUNT(*) AS count").
+		Where("announcement_id IN ?", ids).
+		Group("announcement_id").
+		Scan(&commentCounts).Error; err != nil {
+		return fmt.Errorf("failed to count comments: %v", err)
+	}
+	for _, row := range commentCounts {
+		byID[row.AnnouncementID].CommentCount = row.Count
+	}
+
+	var reactionCounts []struct {
+		AnnouncementID uint
+		Reaction       string
+		Count          int64
+	}
+	if err := s.DB.Model(&model.ProjectAnnouncementReaction{}).
+		Select("announcement_id, reaction, COUNT(*) AS count").
+		Where("announcement_id IN ?", ids).
+		Group("announcement_id, reaction").
+		Scan(&reactionCounts).Error; err != nil {
+		return fmt.Errorf("failed to count reactions: %v", err)
+	}
+	for _, row := range reactionCounts {
+		byID[row.AnnouncementID].Reactions[row.Reaction] = row.Count
+	}
+
+	var mine []model.ProjectAnnouncementReaction
+	if err := s.DB.Where("announcement_id IN ? AND user_id = ?", ids, userID).Find(&mine).Error; err != nil {
+		return fmt.Errorf("failed to load reactions: %v", err)
+	}
+	for _, reaction := range mine {
+		byID[reaction.AnnouncementID].MyReactions = append(byID[reaction.AnnouncementID].MyReactions, reaction.Reaction)
+	}
+	return nil
+}
+
+// GetAnnouncement returns an announcement with its comments, oldest first
+func (s *ProjectAnnouncementService) GetAnnouncement(projectID, announcementID, userID uint) (*model.ProjectAnnouncement, error) {
+	if _, err := s.loadAnnouncementProject(s.DB, projectID, userID, false); err != nil {
+		return nil, err
+	}
+
+	var announcement model.ProjectAnnouncement
+	if err := s.DB.Preload("Author").
+		Preload("Attachments").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Preload("Comments.User").
+		Where("id = ? AND project_id = ?", announcementID, projectID).
+		First(&announcement).Error; err != nil {
+		return nil, errors.New("announcement not found")
+	}
+
+	if err := s.DecorateAnnouncements([]*model.ProjectAnnouncement{&announcement}, userID); err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// UpdateAnnouncement edits the title or body of an announcement; empty values are left unchanged.
+// The author, owners and managers can edit.
+func (s *ProjectAnnouncementService) UpdateAnnouncement(projectID, announcementID, userID uint, title, body string) (*model.ProjectAnnouncement, error) {
+	tx := s.DB.Begin()
+	project, err := s.loadAnnouncementProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	announcement, err := s.getAnnouncement(tx, projectID, announcementID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if announcement.AuthorID != userID {
+		allowed, err := s.canModerate(tx, project, userID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if !allowed {
+			tx.Rollback()
+			return nil, errors.New("only the author, owners and managers can edit this announcement")
+		}
+	}
+
+	updates := map[string]interface{}{}
+	if title = strings.TrimSpace(title); title != "" {
+		updates["title"] = title
+	}
+	if body = strings.TrimSpace(body); body != "" {
+		updates["body"] = body
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(announcement).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to update announcement: %v", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to update announcement: %v", err)
+	}
+	return s.GetAnnouncement(projectID, announcementID, userID)
+}
+
+// PinAnnouncement pins or unpins an announcement; only owners and managers can pin
+func (s *ProjectAnnouncementService) PinAnnouncement(projectID, announcementID, userID uint, pinned bool) (*model.ProjectAnnouncement, error) {
+	tx := s.DB.Begin()
+	project, err := s.loadAnnouncementProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	allowed, err := s.canModerate(tx, project, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !allowed {
+		tx.Rollback()
+		return nil, errors.New("only owners and managers can pin announcements")
+	}
+
+	announcement, err := s.getAnnouncement(tx, projectID, announcementID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"pinned": pinned, "pinned_at": nil}
+	if pinned {
+		updates["pinned_at"] = time.Now()
+	}
+	if err := tx.Model(announcement).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to pin announcement: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to pin announcement: %v", err)
+	}
+	return s.GetAnnouncement(projectID, announcementID, userID)
+}
+
+// DeleteAnnouncement removes an announcement with its comments, reactions and attachments.
+// The author, owners and managers can delete.
+func (s *ProjectAnnouncementService) DeleteAnnouncement(projectID, announcementID, userID uint) error {
+	tx := s.DB.Begin()
+	project, err := s.loadAnnouncementProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	announcement, err := s.getAnnouncement(tx, projectID, announcementID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if announcement.AuthorID != userID {
+		allowed, err := s.canModerate(tx, project, userID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if !allowed {
+			tx.Rollback()
+			return errors.New("only the author, owners and managers can delete this announcement")
+		}
+	}
+
+	if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&model.ProjectAnnouncementComment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement comments: %v", err)
+	}
+	if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&model.ProjectAnnouncementReaction{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement reactions: %v", err)
+	}
+	if err := tx.Where("announcement_id = ?", announcement.ID).Delete(&model.ProjectAnnouncementAttachment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement attachments: %v", err)
+	}
+	if err := tx.Delete(announcement).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement: %v", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to delete announcement: %v", err)
+	}
+
+	for _, attachment := range announcement.Attachments {
+		helper.DeleteFile(attachment.FilePath)
+	}
+	return nil
+}
+
+// AddComment comments on an announcement and notifies its author and earlier commenters
+func (s *ProjectAnnouncementService) AddComment(projectID, announcementID, userID uint, body string) (*model.ProjectAnnouncementComment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+
+	tx := s.DB.Begin()
+	if _, err := s.loadAnnouncementProject(tx, projectID, userID, true); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	announcement, err := s.getAnnouncement(tx, projectID, announcementID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var commenterIDs []uint
+	if err := tx.Model(&model.ProjectAnnouncementComment{}).
+		Where("announcement_id = ?", announcement.ID).
+		Distinct().
+		Pluck("user_id", &commenterIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to load announcement commenters: %v", err)
+	}
+
+	comment := &model.ProjectAnnouncementComment{
+		AnnouncementID: announcement.ID,
+		UserID:         userID,
+		Body:           body,
+	}
+	if err := tx.Create(comment).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+
+	recipients := []uint{announcement.AuthorID}
+	for _, id := range commenterIDs {
+		if id != announcement.AuthorID {
+			recipients = append(recipients, id)
+		}
+	}
+
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyAnnouncementComment, NotificationPayload{
+		ProjectID:      projectID,
+		UserID:         userID,
+		AnnouncementID: announcement.ID,
+		Recipients:     recipients,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+
+	if err := s.DB.Preload("User").First(comment, comment.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load comment: %v", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment; commenters can delete their own, owners and managers any
+func (s *ProjectAnnouncementService) DeleteComment(projectID, announcementID, commentID, userID uint) error {
+	project, err := s.loadAnnouncementProject(s.DB, projectID, userID, true)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.getAnnouncement(s.DB, projectID, announcementID); err != nil {
+		return err
+	}
+
+	var comment model.ProjectAnnouncementComment
+	if err := s.DB.Where("id = ? AND announcement_id = ?", commentID, announcementID).First(&comment).Error; err != nil {
+		return errors.New("comment not found")
+	}
+
+	if comment.UserID != userID {
+		allowed, err := s.canModerate(s.DB, project, userID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("you can only delete your own comments")
+		}
+	}
+
+	if err := s.DB.Delete(&comment).Error; err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+	return nil
+}
+
+// React adds the user's reaction to an announcement; reacting twice with the same reaction has no effect
+func (s *ProjectAnnouncementService) React(projectID, announcementID, userID uint, reaction string) (*model.ProjectAnnouncement, error) {
+	if !isValidAnnouncementReaction(reaction) {
+		return nil, fmt.Errorf("invalid reaction: %s. Must be one of: %s", reaction, strings.Join(model.AnnouncementReactions, ", "))
+	}
+
+	if _, err := s.loadAnnouncementProject(s.DB, projectID, userID, true); err != nil {
+		return nil, err
+	}
+	if _, err := s.getAnnouncement(s.DB, projectID, announcementID); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ProjectAnnouncementReaction{
+		AnnouncementID: announcementID,
+		UserID:         userID,
+		Reaction:       reaction,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %v", err)
+	}
+
+	return s.GetAnnouncement(projectID, announcementID, userID)
+}
+
+// RemoveReaction takes back the user's reaction to an announcement
+func (s *ProjectAnnouncementService) RemoveReaction(projectID, announcementID, userID uint, reaction string) (*model.ProjectAnnouncement, error) {
+	if _, err := s.loadAnnouncementProject(s.DB, projectID, userID, true); err != nil {
+		return nil, err
+	}
+	if _, err := s.getAnnouncement(s.DB, projectID, announcementID); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Where("announcement_id = ? AND user_id = ? AND reaction = ?", announcementID, userID, reaction).
+		Delete(&model.ProjectAnnouncementReaction{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %v", err)
+	}
+
+	return s.GetAnnouncement(projectID, announcementID, userID)
+}
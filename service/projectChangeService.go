@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// projectMaterialFields are the fields whose changes are announced to accepted members
+var projectMaterialFields = map[string]bool{
+	"duration":              true,
+	"start_date":            true,
+	"end_date":              true,
+	"registration_deadline": true,
+	"location":              true,
+	"time_commitment":       true,
+	"roles":                 true,
+}
+
+// projectFieldLabels are the human readable names used in change notifications
+var projectFieldLabels = map[string]string{
+	"duration":              "duration",
+	"total_team":            "team size",
+	"start_date":            "start date",
+	"end_date":              "end date",
+	"location":              "location",
+	"budget":                "budget",
+	"registration_deadline": "registration deadline",
+	"time_commitment":       "time commitment",
+	"required_skills":       "required skills",
+	"conditions":            "conditions",
+	"roles":                 "roles",
+	"benefits":              "benefits",
+	"timeline":              "timeline",
+	"tags":                  "tags",
+}
+
+type ProjectChangeService struct {
+	DB            *gorm.DB
+	OutboxService *OutboxService
+}
+
+func NewProjectChangeService(db *gorm.DB, outboxService *OutboxService) *ProjectChangeService {
+	return &ProjectChangeService{
+		DB:            db,
+		OutboxService: outboxService,
+	}
+}
+
+func formatChangeDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func sortedJoin(values []string) string {
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// ProjectSnapshot holds the tracked fields of a project and its accepted members before an edit
+type ProjectSnapshot struct {
+	fields    map[string]string
+	memberIDs []uint
+}
+
+// Snapshot captures the project inside tx before a stage is saved. Members are captured too,
+// since stage 4 rebuilds the member list before the changes are recorded.
+// It returns nil when the stage is being filled in for the first time, which is not an edit.
+func (s *ProjectChangeService) Snapshot(tx *gorm.DB, project *model.Project, stage int) (*ProjectSnapshot, error) {
+	if project.CompletionStage < stage {
+		return nil, nil
+	}
+
+	fields, err := s.snapshot(tx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberIDs []uint
+	if err := tx.Model(&model.ProjectMember{}).
+		Where("project_id = ? AND status = ?", project.ID, "accepted").
+		Pluck("user_id", &memberIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project members: %v", err)
+	}
+
+	return &ProjectSnapshot{fields: fields, memberIDs: memberIDs}, nil
+}
+
+func (s *ProjectChangeService) snapshot(tx *gorm.DB, projectID uint) (map[string]string, error) {
+	var project model.Project
+	if err := tx.Preload("RequiredSkills.Skill").
+		Preload("Conditions").
+		Preload("Roles.RequiredSkills.Skill").
+		Preload("Benefits.Benefit").
+		Preload("Milestones", orderMilestones).
+		Preload("Tags.Tag").
+		First(&project, projectID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project snapshot: %v", err)
+	}
+
+	var skills, conditions, roles, benefits, timeline, tags []string
+	for _, skill := range project.RequiredSkills {
+		skills = append(skills, skill.Skill.Name)
+	}
+	for _, condition := range project.Conditions {
+		conditions = append(conditions, condition.Description)
+	}
+	for _, role := range project.Roles {
+		var roleSkills []string
+		for _, skill := range role.RequiredSkills {
+			roleSkills = append(roleSkills, skill.Skill.Name)
+		}
+		roles = append(roles, fmt.Sprintf("%s (%d slots; %s)", role.Name, role.SlotsAvailable, sortedJoin(roleSkills)))
+	}
+	for _, benefit := range project.Benefits {
+		benefits = append(benefits, benefit.Benefit.Name)
+	}
+	for _, item := range project.Milestones {
+		entry := fmt.Sprintf("%s [%s]", item.Name, item.Status)
+		if item.StartDate != nil {
+			entry += " from " + formatChangeDate(*item.StartDate)
+		}
+		if item.DueDate != nil {
+			entry += " due " + formatChangeDate(*item.DueDate)
+		}
+		timeline = append(timeline, entry)
+	}
+	for _, tag := range project.Tags {
+		tags = append(tags, tag.Tag.Name)
+	}
+
+	return map[string]string{
+		"duration":              project.Duration,
+		"total_team":            strconv.Itoa(project.TotalTeam),
+		"start_date":            formatChangeDate(project.StartDate),
+		"end_date":              formatChangeDate(project.EndDate),
+		"location":              withTypedValue(project.Location, project.LocationMode),
+		"budget":                withTypedValue(project.Budget, helper.FormatBudget(project.BudgetMin, project.BudgetMax, project.BudgetCurrency, project.IsPaid)),
+		"registration_deadline": formatChangeDate(project.RegistrationDeadline),
+		"time_commitment":       withTypedValue(project.TimeCommitment, formatHoursPerWeek(project.HoursPerWeek)),
+		"required_skills":       sortedJoin(skills),
+		"conditions":            sortedJoin(conditions),
+		"roles":                 sortedJoin(roles),
+		"benefits":              sortedJoin(benefits),
+		"timeline":              sortedJoin(timeline),
+		"tags":                  sortedJoin(tags),
+	}, nil
+}
+
+// withTypedValue appends the typed value to a free-text description, so changing only the typed
+// budget, hours or location mode still counts as a change
+func withTypedValue(text, typed string) string {
+	if typed == "" || typed == text {
+		return text
+	}
+	if text == "" {
+		return typed
+	}
+	return fmt.Sprintf("%s (%s)", text, typed)
+}
+
+func formatHoursPerWeek(hours *int) string {
+	if hours == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d hours/week", *hours)
+}
+
+// RecordChanges compares the project against the before snapshot, logs each changed field and,
+// when a material field changed, queues a notification to the members captured in the snapshot.
+// A nil snapshot is a no-op.
+func (s *ProjectChangeService) RecordChanges(tx *gorm.DB, projectID, userID uint, stage int, snapshot *ProjectSnapshot) error {
+	if snapshot == nil {
+		return nil
+	}
+	before := snapshot.fields
+
+	after, err := s.snapshot(tx, projectID)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var materialFields []string
+	for _, field := range fields {
+		if before[field] == after[field] {
+			continue
+		}
+
+		change := &model.ProjectChange{
+			ProjectID: projectID,
+			ChangedBy: userID,
+			Stage:     stage,
+			Field:     field,
+			OldValue:  before[field],
+			NewValue:  after[field],
+			Material:  projectMaterialFields[field],
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return fmt.Errorf("failed to record project change: %v", err)
+		}
+
+		if change.Material {
+			materialFields = append(materialFields, field)
+		}
+	}
+
+	if len(materialFields) == 0 || len(snapshot.memberIDs) == 0 {
+		return nil
+	}
+
+	return s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyProjectUpdated, NotificationPayload{
+		ProjectID:  projectID,
+		UserID:     userID,
+		Fields:     materialFields,
+		Recipients: snapshot.memberIDs,
+	})
+}
+
+// GetChangesQuery returns a query over the change log of a project, for pagination.
+// The log is visible to the owner, users with project access and accepted members.
+func (s *ProjectChangeService) GetChangesQuery(projectID, userID uint, field string) (*gorm.DB, error) {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+
+	if !CanViewProject(s.DB, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+
+	query := s.DB.Model(&model.ProjectChange{}).
+		Preload("ChangedByUser").
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC")
+	if field != "" {
+		query = query.Where("field = ?", field)
+	}
+	return query, nil
+}
+
+// describeChangedFields turns field keys into a readable list such as "start date and roles"
+func describeChangedFields(fields []string) string {
+	labels := make([]string, len(fields))
+	for i, field := range fields {
+		label, ok := projectFieldLabels[field]
+		if !ok {
+			label = field
+		}
+		labels[i] = label
+	}
+
+	if len(labels) == 1 {
+		return labels[0]
+	}
+	return strings.Join(labels[:len(labels)-1], ", ") + " and " + labels[len(labels)-1]
+}
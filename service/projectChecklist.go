@@ -0,0 +1,200 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+// projectStageNames are the titles of the wizard stages, as shown in the checklist
+var projectStageNames = map[int]string{
+	1: "Basics",
+	2: "Details",
+	3: "Requirements",
+	4: "Team",
+	5: "Benefits & timeline",
+}
+
+// ChecklistItem is one check of a project. Blocking items must pass before the project can be
+// published; the others are suggestions. Message says what to do when the check fails.
+type ChecklistItem struct {
+	Code     string `json:"code"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking"`
+	Passed   bool   `json:"passed"`
+}
+
+// ChecklistStage groups the checks of one wizard stage. It is complete when every blocking
+// check passes.
+type ChecklistStage struct {
+	Stage    int             `json:"stage"`
+	Name     string          `json:"name"`
+	Complete bool            `json:"complete"`
+	Items    []ChecklistItem `json:"items"`
+}
+
+// ProjectChecklist reports what a project is missing, stage by stage
+type ProjectChecklist struct {
+	ProjectID       uint             `json:"project_id"`
+	Status          string           `json:"status"`
+	CompletionStage int              `json:"completion_stage"`
+	Publishable     bool             `json:"publishable"`
+	BlockingCount   int              `json:"blocking_count"`
+	WarningCount    int              `json:"warning_count"`
+	Stages          []ChecklistStage `json:"stages"`
+}
+
+// BlockingItems returns the failed checks that keep the project from being published
+func (c *ProjectChecklist) BlockingItems() []ChecklistItem {
+	var items []ChecklistItem
+	for _, stage := range c.Stages {
+		for _, item := range stage.Items {
+			if item.Blocking && !item.Passed {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// ProjectNotPublishableError is returned when publishing a project with failed blocking checks
+type ProjectNotPublishableError struct {
+	Items []ChecklistItem
+}
+
+func (e *ProjectNotPublishableError) Error() string {
+	messages := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		messages = append(messages, item.Message)
+	}
+	return "project is not ready to publish: " + strings.Join(messages, "; ")
+}
+
+// Fields maps the field of each blocking item to what to do about it
+func (e *ProjectNotPublishableError) Fields() map[string]string {
+	fields := make(map[string]string, len(e.Items))
+	for _, item := range e.Items {
+		if _, found := fields[item.Field]; !found {
+			fields[item.Field] = item.Message
+		}
+	}
+	return fields
+}
+
+type checklistBuilder struct {
+	checklist *ProjectChecklist
+	stage     *ChecklistStage
+}
+
+func (b *checklistBuilder) startStage(stage int) {
+	b.checklist.Stages = append(b.checklist.Stages, ChecklistStage{Stage: stage, Name: projectStageNames[stage], Complete: true, Items: []ChecklistItem{}})
+	b.stage = &b.checklist.Stages[len(b.checklist.Stages)-1]
+}
+
+func (b *checklistBuilder) check(passed bool, blocking bool, code, field, message string) {
+	b.stage.Items = append(b.stage.Items, ChecklistItem{Code: code, Field: field, Message: message, Blocking: blocking, Passed: passed})
+	if passed {
+		return
+	}
+	if blocking {
+		b.stage.Complete = false
+		b.checklist.BlockingCount++
+	} else {
+		b.checklist.WarningCount++
+	}
+}
+
+// BuildProjectChecklist checks a project inside tx against what a publishable project needs
+func BuildProjectChecklist(tx *gorm.DB, projectID uint) (*ProjectChecklist, error) {
+	var project model.Project
+	if err := tx.Preload("RequiredSkills").
+		Preload("Roles", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Roles.RequiredSkills").
+		Preload("Members").
+		Preload("Benefits").
+		Preload("Milestones", orderMilestones).
+		First(&project, projectID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project: %v", err)
+	}
+
+	checklist := &ProjectChecklist{
+		ProjectID:       project.ID,
+		Status:          project.Status,
+		CompletionStage: project.CompletionStage,
+	}
+	b := &checklistBuilder{checklist: checklist}
+
+	b.startStage(1)
+	b.check(strings.TrimSpace(project.Title) != "", true, "title", "title", "Add a title")
+	b.check(strings.TrimSpace(project.ProjectType) != "", true, "project_type", "project_type", "Choose a project type")
+	b.check(strings.TrimSpace(project.Description) != "", true, "description", "description", "Add a description")
+	b.check(project.PictureURL != "", false, "cover_image", "picture", "Add a cover image so the project stands out in listings")
+
+	b.startStage(2)
+	b.check(project.TotalTeam > 0, true, "total_team", "total_team", "Set the total team size")
+	b.check(!project.StartDate.IsZero(), false, "start_date", "start_date", "Set a start date")
+	b.check(!project.EndDate.IsZero(), false, "end_date", "end_date", "Set an end date")
+	if !project.StartDate.IsZero() && !project.EndDate.IsZero() {
+		b.check(!project.EndDate.Before(project.StartDate), true, "end_before_start", "end_date", "The end date must not be before the start date")
+	}
+	b.check(!project.RegistrationDeadline.IsZero(), false, "registration_deadline", "registration_deadline", "Set a registration deadline")
+	if !project.RegistrationDeadline.IsZero() && !project.StartDate.IsZero() {
+		b.check(!project.RegistrationDeadline.After(project.StartDate), true, "deadline_after_start", "registration_deadline",
+			"The registration deadline must not be after the start date")
+	}
+	if !project.RegistrationDeadline.IsZero() {
+		b.check(project.RegistrationDeadline.After(time.Now()), false, "deadline_passed", "registration_deadline",
+			"The registration deadline has passed, so nobody can apply")
+	}
+	b.check(strings.TrimSpace(project.Location) != "" || project.LocationMode != "", false, "location", "location", "Say where the project takes place, or that it is remote")
+	b.check(strings.TrimSpace(project.Budget) != "" || project.IsPaid != nil, false, "budget", "budget", "Describe the budget, or mark the project as unpaid")
+
+	b.startStage(3)
+	b.check(strings.TrimSpace(project.TimeCommitment) != "" || project.HoursPerWeek != nil, true, "time_commitment", "time_commitment", "Set the time commitment")
+	b.check(len(project.RequiredSkills) > 0, true, "required_skills", "required_skills", "Add at least one required skill")
+
+	b.startStage(4)
+	roleSlots := 0
+	for _, role := range project.Roles {
+		roleSlots += role.SlotsAvailable
+	}
+	positions := len(project.Members) + roleSlots
+	b.check(positions > 0, true, "team", "roles", "Add at least one member or an open role")
+	b.check(positions <= project.TotalTeam, true, "team_over_capacity", "total_team",
+		fmt.Sprintf("Members and role slots take %d positions, more than the total team of %d", positions, project.TotalTeam))
+	if positions <= project.TotalTeam && positions > 0 {
+		b.check(positions == project.TotalTeam, false, "team_size_mismatch", "total_team",
+			fmt.Sprintf("Members and role slots fill %d of the %d positions; add roles or lower the total team", positions, project.TotalTeam))
+	}
+	for i, role := range project.Roles {
+		b.check(len(role.RequiredSkills) > 0, false, "role_skills", fmt.Sprintf("roles[%d].skill_names", i),
+			fmt.Sprintf("Add the skills role %q needs", role.Name))
+	}
+
+	b.startStage(5)
+	b.check(len(project.Benefits) > 0, true, "benefits", "benefits", "Add at least one benefit")
+	b.check(len(project.Milestones) > 0, false, "milestones", "milestones", "Add milestones so applicants can see the plan")
+	for i, milestone := range project.Milestones {
+		if milestone.DueDate == nil || project.EndDate.IsZero() {
+			continue
+		}
+		b.check(!milestone.DueDate.After(project.EndDate), false, "milestone_after_end", fmt.Sprintf("milestones[%d].due_date", i),
+			fmt.Sprintf("Milestone %q is due after the project ends", milestone.Name))
+	}
+
+	checklist.Publishable = checklist.BlockingCount == 0
+	return checklist, nil
+}
+
+// GetChecklist returns what a project is missing before it can be published (editors only)
+func (s *ProjectService) GetChecklist(projectID, userID uint) (*ProjectChecklist, error) {
+	project, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	return BuildProjectChecklist(s.DB, project.ID)
+}
@@ -0,0 +1,695 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// projectDocumentStage is the stage recorded for edits made through the document API, which
+// can change every stage at once
+const projectDocumentStage = 0
+
+// ValidationErrors maps each invalid field of a request to what is wrong with it. Fields of list
+// items are named by position, such as roles[0].name.
+type ValidationErrors map[string]string
+
+func (e ValidationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+": "+e[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records the first problem found with a field
+func (e ValidationErrors) add(field, message string) {
+	if _, found := e[field]; !found {
+		e[field] = message
+	}
+}
+
+// ProjectDocument is a whole project in one request, covering the five stages of the wizard.
+// Nil fields were not sent and are left unchanged by updates; an empty list clears it.
+type ProjectDocument struct {
+	Title       *string `json:"title"`
+	ProjectType *string `json:"project_type"`
+	Description *string `json:"description"`
+
+	TotalTeam            *int       `json:"total_team"`
+	StartDate            *time.Time `json:"start_date"`
+	EndDate              *time.Time `json:"end_date"`
+	RegistrationDeadline *time.Time `json:"registration_deadline"`
+	Duration             *string    `json:"duration"`
+	DurationDays         *int       `json:"duration_days"`
+
+	Location     *string  `json:"location"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	LocationMode *string  `json:"location_mode"`
+
+	Budget         *string `json:"budget"`
+	BudgetMin      *int64  `json:"budget_min"`
+	BudgetMax      *int64  `json:"budget_max"`
+	BudgetCurrency *string `json:"budget_currency"`
+	IsPaid         *bool   `json:"is_paid"`
+
+	TimeCommitment *string   `json:"time_commitment"`
+	HoursPerWeek   *int      `json:"hours_per_week"`
+	RequiredSkills *[]string `json:"required_skills"`
+	Conditions     *[]string `json:"conditions"`
+
+	Roles   *[]RoleDTO   `json:"roles"`
+	Members *[]MemberDTO `json:"members"`
+
+	Benefits   *[]string       `json:"benefits"`
+	Milestones *[]MilestoneDTO `json:"milestones"`
+	Tags       *[]string       `json:"tags"`
+
+	// Publish publishes a draft once it is saved
+	Publish bool `json:"publish"`
+}
+
+// projectTeam is the team asked for by a document, checked against the project's current team
+type projectTeam struct {
+	// roleIDs maps the lowercase name of each current role to its ID
+	roleIDs map[string]uint
+	// userIDs holds the user of each member of the document, in order
+	userIDs []uint
+}
+
+// cleanNames trims names and drops empty and repeated ones, ignoring case
+func cleanNames(names []string) []string {
+	cleaned := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, name)
+	}
+	return cleaned
+}
+
+// requireProjectDocument checks that a new project has everything the wizard asks for
+func requireProjectDocument(doc *ProjectDocument, errs ValidationErrors) {
+	if doc.Title == nil {
+		errs.add("title", "title is required")
+	}
+	if doc.ProjectType == nil {
+		errs.add("project_type", "project type is required")
+	}
+	if doc.Description == nil {
+		errs.add("description", "description is required")
+	}
+	if doc.TotalTeam == nil {
+		errs.add("total_team", "total team is required")
+	}
+	if doc.TimeCommitment == nil && doc.HoursPerWeek == nil {
+		errs.add("time_commitment", "time_commitment or hours_per_week is required")
+	}
+	if doc.RequiredSkills == nil {
+		errs.add("required_skills", "at least one required skill is needed")
+	}
+	if doc.Roles == nil && doc.Members == nil {
+		errs.add("roles", "add at least one member or role for team recruitment")
+	}
+	if doc.Benefits == nil {
+		errs.add("benefits", "at least one benefit is required")
+	}
+}
+
+func requireText(value *string, field, message string, errs ValidationErrors) string {
+	text := strings.TrimSpace(*value)
+	if text == "" {
+		errs.add(field, message)
+	}
+	return text
+}
+
+// applyProjectDocument sets the project fields doc carries and cleans up its lists, collecting
+// what is wrong in errs. Nothing is written to the database.
+func applyProjectDocument(project *model.Project, doc *ProjectDocument, errs ValidationErrors) {
+	if doc.Title != nil {
+		project.Title = requireText(doc.Title, "title", "title is required", errs)
+	}
+	if doc.ProjectType != nil {
+		project.ProjectType = requireText(doc.ProjectType, "project_type", "project type is required", errs)
+	}
+	if doc.Description != nil {
+		project.Description = requireText(doc.Description, "description", "description is required", errs)
+	}
+	if doc.TotalTeam != nil {
+		if *doc.TotalTeam < 1 {
+			errs.add("total_team", "total team must be at least 1")
+		}
+		project.TotalTeam = *doc.TotalTeam
+	}
+	if doc.RegistrationDeadline != nil {
+		project.RegistrationDeadline = *doc.RegistrationDeadline
+	}
+
+	if doc.Duration != nil || doc.DurationDays != nil || doc.StartDate != nil || doc.EndDate != nil {
+		// A description generated from the old dates is generated again
+		text := project.Duration
+		if doc.Duration != nil {
+			text = *doc.Duration
+		} else if doc.DurationDays != nil || text == helper.FormatDurationDays(project.DurationDays) {
+			text = ""
+		}
+		if doc.StartDate != nil {
+			project.StartDate = *doc.StartDate
+		}
+		if doc.EndDate != nil {
+			project.EndDate = *doc.EndDate
+		}
+		if err := applyProjectDuration(project, text, doc.DurationDays); err != nil {
+			errs.add("duration", err.Error())
+		}
+	}
+
+	if doc.Budget != nil || doc.BudgetMin != nil || doc.BudgetMax != nil || doc.BudgetCurrency != nil || doc.IsPaid != nil {
+		// Typed fields win over the current description; typed fields left out keep their values
+		text := ""
+		if doc.Budget != nil {
+			text = *doc.Budget
+		}
+		var terms ProjectTermsInput
+		if doc.BudgetMin != nil || doc.BudgetMax != nil || doc.BudgetCurrency != nil || doc.IsPaid != nil {
+			terms = ProjectTermsInput{
+				BudgetMin:      project.BudgetMin,
+				BudgetMax:      project.BudgetMax,
+				BudgetCurrency: project.BudgetCurrency,
+				IsPaid:         project.IsPaid,
+			}
+			if doc.BudgetMin != nil {
+				terms.BudgetMin = doc.BudgetMin
+			}
+			if doc.BudgetMax != nil {
+				terms.BudgetMax = doc.BudgetMax
+			}
+			if doc.BudgetCurrency != nil {
+				terms.BudgetCurrency = *doc.BudgetCurrency
+			}
+			if doc.IsPaid != nil {
+				terms.IsPaid = doc.IsPaid
+				// Turning pay off drops the amounts that weren't sent
+				if !*doc.IsPaid {
+					if doc.BudgetMin == nil {
+						terms.BudgetMin = nil
+					}
+					if doc.BudgetMax == nil {
+						terms.BudgetMax = nil
+					}
+				}
+			} else if doc.BudgetMin != nil || doc.BudgetMax != nil {
+				// Sending an amount makes the project paid
+				terms.IsPaid = nil
+			}
+		}
+		if err := applyProjectBudget(project, text, terms); err != nil {
+			errs.add("budget", err.Error())
+		}
+	}
+
+	if doc.TimeCommitment != nil || doc.HoursPerWeek != nil {
+		text := ""
+		if doc.TimeCommitment != nil {
+			text = *doc.TimeCommitment
+		}
+		if err := applyProjectHours(project, text, doc.HoursPerWeek); err != nil {
+			errs.add("hours_per_week", err.Error())
+		} else if project.TimeCommitment == "" {
+			errs.add("time_commitment", "time_commitment or hours_per_week is required")
+		}
+	}
+
+	if doc.Location != nil || doc.Latitude != nil || doc.Longitude != nil || doc.LocationMode != nil {
+		input := GeoLocationInput{Latitude: doc.Latitude, Longitude: doc.Longitude}
+		if doc.LocationMode != nil {
+			input.Mode = *doc.LocationMode
+		}
+		if doc.Location != nil {
+			project.Location = strings.TrimSpace(*doc.Location)
+		} else {
+			// The text is unchanged, so it keeps its coordinates and mode unless new ones were sent
+			if input.Latitude == nil && input.Longitude == nil {
+				input.Latitude, input.Longitude = project.Latitude, project.Longitude
+			}
+			if input.Mode == "" {
+				input.Mode = project.LocationMode
+			}
+		}
+		location, err := ResolveGeoLocation(project.Location, input)
+		if err != nil {
+			errs.add("location", err.Error())
+		} else {
+			project.GeoLocation = location
+		}
+	}
+
+	if doc.RequiredSkills != nil {
+		*doc.RequiredSkills = cleanNames(*doc.RequiredSkills)
+		if len(*doc.RequiredSkills) == 0 {
+			errs.add("required_skills", "at least one required skill is needed")
+		}
+	}
+	if doc.Conditions != nil {
+		*doc.Conditions = cleanNames(*doc.Conditions)
+	}
+	if doc.Benefits != nil {
+		*doc.Benefits = cleanNames(*doc.Benefits)
+		if len(*doc.Benefits) == 0 {
+			errs.add("benefits", "at least one benefit is required")
+		}
+	}
+	if doc.Tags != nil {
+		*doc.Tags = cleanNames(*doc.Tags)
+	}
+}
+
+// planProjectTeam checks the roles, members and team size of a document against the project's
+// current team. Roles are matched by name and members by user, so a role listed again keeps its
+// applications and a member listed again keeps their status. It returns nil when the document
+// doesn't touch the team.
+func (s *ProjectService) planProjectTeam(tx *gorm.DB, project *model.Project, doc *ProjectDocument, errs ValidationErrors) (*projectTeam, error) {
+	if doc.Roles == nil && doc.Members == nil && doc.TotalTeam == nil {
+		return nil, nil
+	}
+
+	var existingRoles []model.ProjectRole
+	var existingMembers []model.ProjectMember
+	if project.ID != 0 {
+		if err := tx.Where("project_id = ?", project.ID).Find(&existingRoles).Error; err != nil {
+			return nil, fmt.Errorf("failed to load project roles: %v", err)
+		}
+		if err := tx.Preload("User").Preload("ProjectRole").Where("project_id = ?", project.ID).Find(&existingMembers).Error; err != nil {
+			return nil, fmt.Errorf("failed to load project members: %v", err)
+		}
+	}
+
+	team := &projectTeam{roleIDs: make(map[string]uint, len(existingRoles))}
+	for _, role := range existingRoles {
+		team.roleIDs[strings.ToLower(role.Name)] = role.ID
+	}
+
+	roleNames := make(map[string]bool)
+	roleSlots := 0
+	if doc.Roles != nil {
+		for i := range *doc.Roles {
+			role := &(*doc.Roles)[i]
+			field := fmt.Sprintf("roles[%d]", i)
+			role.Name = strings.TrimSpace(role.Name)
+			role.SkillNames = cleanNames(role.SkillNames)
+			key := strings.ToLower(role.Name)
+			switch {
+			case role.Name == "":
+				errs.add(field+".name", "role name is required")
+			case roleNames[key]:
+				errs.add(field+".name", "role is listed twice: "+role.Name)
+			}
+			roleNames[key] = true
+			if role.SlotsAvailable < 0 {
+				errs.add(field+".slots_available", "slots available cannot be negative")
+			}
+			roleSlots += role.SlotsAvailable
+		}
+
+		for _, role := range existingRoles {
+			if roleNames[strings.ToLower(role.Name)] {
+				continue
+			}
+			var applications int64
+			if err := tx.Model(&model.ProjectApplication{}).Where("project_role_id = ?", role.ID).Count(&applications).Error; err != nil {
+				return nil, fmt.Errorf("failed to count applications: %v", err)
+			}
+			if applications > 0 {
+				errs.add("roles", fmt.Sprintf("role %q has applications and cannot be removed", role.Name))
+			}
+		}
+	} else {
+		for _, role := range existingRoles {
+			roleNames[strings.ToLower(role.Name)] = true
+			roleSlots += role.SlotsAvailable
+		}
+	}
+
+	memberCount := len(existingMembers)
+	if doc.Members != nil {
+		memberCount = len(*doc.Members)
+		listed := make(map[uint]bool, memberCount)
+		for i := range *doc.Members {
+			member := &(*doc.Members)[i]
+			field := fmt.Sprintf("members[%d]", i)
+			member.Name = strings.TrimSpace(member.Name)
+			member.SkillNames = cleanNames(member.SkillNames)
+
+			var user model.Users
+			switch {
+			case member.Name == "":
+				errs.add(field+".name", "member name is required")
+			case tx.Where("name = ?", member.Name).First(&user).Error != nil:
+				errs.add(field+".name", "user to invite not found: "+member.Name)
+			case listed[user.ID]:
+				errs.add(field+".name", "member is listed twice: "+member.Name)
+			}
+			listed[user.ID] = true
+			team.userIDs = append(team.userIDs, user.ID)
+
+			if !roleNames[strings.ToLower(strings.TrimSpace(member.RoleName))] {
+				errs.add(field+".role_name", "role specified for member does not exist: "+member.RoleName)
+			}
+		}
+
+		// People who joined leave through the members API, which tells them and the webhooks
+		for _, existing := range existingMembers {
+			if existing.Status == "accepted" && !listed[existing.UserID] {
+				errs.add("members", fmt.Sprintf("%s has joined the project; remove them with DELETE /api/projects/%d/members/%d",
+					existing.User.Name, project.ID, existing.UserID))
+			}
+		}
+	} else if doc.Roles != nil {
+		for _, existing := range existingMembers {
+			if !roleNames[strings.ToLower(existing.ProjectRole.Name)] {
+				errs.add("roles", fmt.Sprintf("role %q still has members; send members too to move them to another role", existing.ProjectRole.Name))
+			}
+		}
+	}
+
+	if (doc.Roles != nil || doc.Members != nil) && memberCount+roleSlots == 0 {
+		errs.add("roles", "add at least one member or role for team recruitment")
+	}
+	if memberCount+roleSlots > project.TotalTeam {
+		errs.add("total_team", fmt.Sprintf("the team needs %d positions (%d members and %d role slots) but total team is %d",
+			memberCount+roleSlots, memberCount, roleSlots, project.TotalTeam))
+	}
+	return team, nil
+}
+
+// setRoleSkills replaces the required skills of a role
+func (s *ProjectService) setRoleSkills(tx *gorm.DB, roleID uint, skillNames []string) error {
+	if err := tx.Where("project_role_id = ?", roleID).Delete(&model.ProjectRoleSkill{}).Error; err != nil {
+		return err
+	}
+	for _, skillName := range skillNames {
+		skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&model.ProjectRoleSkill{ProjectRoleID: roleID, SkillID: skill.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMemberSkills replaces the skills of a member
+func (s *ProjectService) setMemberSkills(tx *gorm.DB, memberID uint, skillNames []string) error {
+	if err := tx.Where("project_member_id = ?", memberID).Delete(&model.ProjectMemberSkill{}).Error; err != nil {
+		return err
+	}
+	for _, skillName := range skillNames {
+		skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&model.ProjectMemberSkill{ProjectMemberID: memberID, SkillID: skill.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveProjectTeam writes a planned team. Roles are updated in place or created, members moved to
+// their new roles or invited, and only then are unlisted members and roles removed.
+func (s *ProjectService) saveProjectTeam(tx *gorm.DB, projectID uint, doc *ProjectDocument, team *projectTeam) error {
+	var removedRoleIDs []uint
+	if doc.Roles != nil {
+		var existingRoles []*model.ProjectRole
+		if err := tx.Where("project_id = ?", projectID).Find(&existingRoles).Error; err != nil {
+			return fmt.Errorf("failed to load project roles: %v", err)
+		}
+		remaining := make(map[string]*model.ProjectRole, len(existingRoles))
+		for _, role := range existingRoles {
+			remaining[strings.ToLower(role.Name)] = role
+		}
+
+		team.roleIDs = make(map[string]uint, len(*doc.Roles))
+		for _, roleData := range *doc.Roles {
+			key := strings.ToLower(roleData.Name)
+			role, found := remaining[key]
+			if !found {
+				created, err := s.createRole(tx, projectID, roleData)
+				if err != nil {
+					return err
+				}
+				team.roleIDs[key] = created.ID
+				continue
+			}
+
+			role.Name = roleData.Name
+			role.SlotsAvailable = roleData.SlotsAvailable
+			role.Description = roleData.Description
+			if err := tx.Omit("RequiredSkills").Save(role).Error; err != nil {
+				return fmt.Errorf("failed to save role: %v", err)
+			}
+			if err := s.setRoleSkills(tx, role.ID, roleData.SkillNames); err != nil {
+				return err
+			}
+			team.roleIDs[key] = role.ID
+			delete(remaining, key)
+		}
+		for _, role := range remaining {
+			removedRoleIDs = append(removedRoleIDs, role.ID)
+		}
+	}
+
+	if doc.Members != nil {
+		var existingMembers []*model.ProjectMember
+		if err := tx.Where("project_id = ?", projectID).Find(&existingMembers).Error; err != nil {
+			return fmt.Errorf("failed to load project members: %v", err)
+		}
+		remaining := make(map[uint]*model.ProjectMember, len(existingMembers))
+		for _, member := range existingMembers {
+			remaining[member.UserID] = member
+		}
+
+		for i, memberData := range *doc.Members {
+			roleID := team.roleIDs[strings.ToLower(strings.TrimSpace(memberData.RoleName))]
+			member, found := remaining[team.userIDs[i]]
+			if !found {
+				member = &model.ProjectMember{ProjectID: projectID, UserID: team.userIDs[i], Status: "invited"}
+			}
+			member.ProjectRoleID = roleID
+			member.RoleDescription = memberData.RoleDescription
+			if err := tx.Omit("Project", "User", "ProjectRole", "MemberSkills").Save(member).Error; err != nil {
+				return fmt.Errorf("failed to save member: %v", err)
+			}
+			if err := s.setMemberSkills(tx, member.ID, memberData.SkillNames); err != nil {
+				return err
+			}
+			delete(remaining, team.userIDs[i])
+		}
+
+		for _, member := range remaining {
+			if err := tx.Where("project_member_id = ?", member.ID).Delete(&model.ProjectMemberSkill{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(member).Error; err != nil {
+				return fmt.Errorf("failed to remove member: %v", err)
+			}
+		}
+	}
+
+	if len(removedRoleIDs) > 0 {
+		if err := tx.Where("project_role_id IN ?", removedRoleIDs).Delete(&model.ProjectRoleSkill{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", removedRoleIDs).Delete(&model.ProjectRole{}).Error; err != nil {
+			return fmt.Errorf("failed to remove roles: %v", err)
+		}
+	}
+	return nil
+}
+
+// saveProjectDocumentLists writes the lists a document carries. Problems only found while saving,
+// such as milestones depending on each other in a loop, come back as ValidationErrors.
+func (s *ProjectService) saveProjectDocumentLists(tx *gorm.DB, projectID uint, doc *ProjectDocument, team *projectTeam) error {
+	if doc.RequiredSkills != nil {
+		if err := s.setRequiredSkills(tx, projectID, *doc.RequiredSkills); err != nil {
+			return err
+		}
+	}
+	if doc.Conditions != nil {
+		if err := s.setConditions(tx, projectID, *doc.Conditions); err != nil {
+			return err
+		}
+	}
+	if team != nil {
+		if err := s.saveProjectTeam(tx, projectID, doc, team); err != nil {
+			return err
+		}
+	}
+	if doc.Benefits != nil {
+		if err := s.setProjectBenefits(tx, projectID, *doc.Benefits); err != nil {
+			return err
+		}
+	}
+	if doc.Tags != nil {
+		if err := s.setProjectTags(tx, projectID, *doc.Tags); err != nil {
+			return err
+		}
+	}
+	if doc.Milestones != nil {
+		if err := replaceProjectMilestones(tx, projectID, *doc.Milestones); err != nil {
+			return ValidationErrors{"milestones": err.Error()}
+		}
+	}
+	return nil
+}
+
+// publishProjectDocument publishes a draft when the document asks for it
+func (s *ProjectService) publishProjectDocument(tx *gorm.DB, project *model.Project, doc *ProjectDocument, userID uint) error {
+	if !doc.Publish || project.Status != model.ProjectStatusDraft {
+		return nil
+	}
+	if err := s.lifecycleService.applyTransition(tx, project, model.ProjectStatusPublished, userID, ""); err != nil {
+		var notPublishable *ProjectNotPublishableError
+		if errors.As(err, &notPublishable) {
+			return ValidationErrors(notPublishable.Fields())
+		}
+		return ValidationErrors{"publish": err.Error()}
+	}
+	return nil
+}
+
+// CreateProjectDocument creates a complete project in one transaction, as if every stage of the
+// wizard had been filled in. The project stays a draft unless the document asks to publish it.
+func (s *ProjectService) CreateProjectDocument(userID uint, doc ProjectDocument) (interface{}, error) {
+	errs := ValidationErrors{}
+	requireProjectDocument(&doc, errs)
+
+	project := model.Project{
+		CreatorID:       userID,
+		Status:          model.ProjectStatusDraft,
+		CompletionStage: 5,
+		BudgetCurrency:  helper.DefaultCurrency,
+	}
+	applyProjectDocument(&project, &doc, errs)
+
+	tx := s.DB.Begin()
+	team, err := s.planProjectTeam(tx, &project, &doc, errs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(errs) > 0 {
+		tx.Rollback()
+		return nil, errs
+	}
+
+	if err := tx.Create(&project).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create project: %v", err)
+	}
+	if err := s.saveProjectDocumentLists(tx, project.ID, &doc, team); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.publishProjectDocument(tx, &project, &doc, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	projectResult, err := s.loadProjectWithRelationships(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
+}
+
+// UpdateProjectDocument changes the fields and lists a document carries in one transaction,
+// at any stage of the wizard. Edits are logged like stage edits, under stage 0.
+func (s *ProjectService) UpdateProjectDocument(projectID, userID uint, doc ProjectDocument) (interface{}, error) {
+	tx := s.DB.Begin()
+	project, err := s.getProjectForUpdate(tx, projectID, userID, 1)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	before, err := s.changeService.Snapshot(tx, &project, 2)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	errs := ValidationErrors{}
+	applyProjectDocument(&project, &doc, errs)
+	team, err := s.planProjectTeam(tx, &project, &doc, errs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(errs) > 0 {
+		tx.Rollback()
+		return nil, errs
+	}
+
+	if err := tx.Save(&project).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.saveProjectDocumentLists(tx, project.ID, &doc, team); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.changeService.RecordChanges(tx, project.ID, userID, projectDocumentStage, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.publishProjectDocument(tx, &project, &doc, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if doc.Milestones != nil {
+		if err := s.webhookService.Dispatch(tx, project.ID, model.WebhookEventTimelineUpdated, map[string]interface{}{
+			"action":   "replaced",
+			"timeline": *doc.Milestones,
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	projectResult, err := s.loadProjectWithRelationships(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
+}
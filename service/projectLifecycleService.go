@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+// projectStatusTransitions lists the statuses each lifecycle status may move to
+var projectStatusTransitions = map[string][]string{
+	model.ProjectStatusDraft:      {model.ProjectStatusPublished, model.ProjectStatusCancelled},
+	model.ProjectStatusPublished:  {model.ProjectStatusInProgress, model.ProjectStatusCancelled},
+	model.ProjectStatusInProgress: {model.ProjectStatusCompleted, model.ProjectStatusCancelled},
+	model.ProjectStatusCompleted:  {model.ProjectStatusArchived},
+	model.ProjectStatusCancelled:  {model.ProjectStatusArchived},
+	model.ProjectStatusArchived:   {},
+}
+
+// ProjectStatuses lists every lifecycle status in order
+var ProjectStatuses = []string{
+	model.ProjectStatusDraft,
+	model.ProjectStatusPublished,
+	model.ProjectStatusInProgress,
+	model.ProjectStatusCompleted,
+	model.ProjectStatusArchived,
+	model.ProjectStatusCancelled,
+}
+
+// ProjectPublicStatuses are the statuses under which a project is listed publicly
+var ProjectPublicStatuses = []string{
+	model.ProjectStatusPublished,
+	model.ProjectStatusInProgress,
+	model.ProjectStatusCompleted,
+}
+
+// ProjectActiveStatuses are the statuses under which a project's team is still working
+var ProjectActiveStatuses = []string{
+	model.ProjectStatusPublished,
+	model.ProjectStatusInProgress,
+}
+
+// ErrProjectArchived is returned when changing an archived project; members keep read-only access to it
+var ErrProjectArchived = errors.New("project is archived and read-only")
+
+type ProjectLifecycleService struct {
+	DB             *gorm.DB
+	OutboxService  *OutboxService
+	WebhookService *WebhookService
+}
+
+func NewProjectLifecycleService(db *gorm.DB, outboxService *OutboxService, webhookService *WebhookService) *ProjectLifecycleService {
+	return &ProjectLifecycleService{
+		DB:             db,
+		OutboxService:  outboxService,
+		WebhookService: webhookService,
+	}
+}
+
+// AllowedTransitions returns the statuses a project in status can move to
+func AllowedTransitions(status string) []string {
+	return projectStatusTransitions[status]
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range projectStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransitionGuards enforces the preconditions of entering a status
+func (s *ProjectLifecycleService) checkTransitionGuards(tx *gorm.DB, project *model.Project, to string) error {
+	switch to {
+	case model.ProjectStatusPublished:
+		checklist, err := BuildProjectChecklist(tx, project.ID)
+		if err != nil {
+			return err
+		}
+		if !checklist.Publishable {
+			return &ProjectNotPublishableError{Items: checklist.BlockingItems()}
+		}
+	case model.ProjectStatusInProgress:
+		var members int64
+		if err := tx.Model(&model.ProjectMember{}).
+			Where("project_id = ? AND status = ?", project.ID, "accepted").
+			Count(&members).Error; err != nil {
+			return fmt.Errorf("failed to count project members: %v", err)
+		}
+		if members == 0 {
+			return errors.New("a project needs at least one accepted member before it can start")
+		}
+	case model.ProjectStatusCompleted:
+		var pending int64
+		if err := tx.Model(&model.ProjectApplication{}).
+			Where("project_id = ? AND status = ?", project.ID, model.ApplicationStatusPending).
+			Count(&pending).Error; err != nil {
+			return fmt.Errorf("failed to count pending applications: %v", err)
+		}
+		if pending > 0 {
+			return fmt.Errorf("review the %d pending applications before completing the project", pending)
+		}
+	}
+	return nil
+}
+
+// applyTransition moves project to status to inside tx: it checks the transition and its guards,
+// records the history entry and queues the member notifications and webhooks.
+func (s *ProjectLifecycleService) applyTransition(tx *gorm.DB, project *model.Project, to string, userID uint, reason string) error {
+	from := project.Status
+	if from == to {
+		return fmt.Errorf("project is already %s", to)
+	}
+
+	if _, known := projectStatusTransitions[to]; !known {
+		return fmt.Errorf("invalid project status: %s. Must be one of: %s", to, strings.Join(ProjectStatuses, ", "))
+	}
+
+	if !canTransition(from, to) {
+		return fmt.Errorf("cannot change project status from %s to %s", from, to)
+	}
+
+	if err := s.checkTransitionGuards(tx, project, to); err != nil {
+		return err
+	}
+
+	if err := tx.Model(project).Update("status", to).Error; err != nil {
+		return fmt.Errorf("failed to update project status: %v", err)
+	}
+	project.Status = to
+
+	history := &model.ProjectStatusHistory{
+		ProjectID:  project.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  userID,
+		Reason:     reason,
+	}
+	if err := tx.Create(history).Error; err != nil {
+		return fmt.Errorf("failed to record status history: %v", err)
+	}
+
+	if err := recordActivity(tx, project.ID, userID, model.ActivityTypeStatusChanged,
+		fmt.Sprintf("Project status changed from %s to %s", from, to),
+		map[string]interface{}{
+			"previous_status": from,
+			"status":          to,
+			"reason":          reason,
+		}); err != nil {
+		return err
+	}
+
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyStatusChange, NotificationPayload{
+		ProjectID:      project.ID,
+		Status:         to,
+		PreviousStatus: from,
+	}); err != nil {
+		return err
+	}
+
+	return s.WebhookService.Dispatch(tx, project.ID, model.WebhookEventProjectStatusChanged, map[string]interface{}{
+		"previous_status": from,
+		"status":          to,
+		"reason":          reason,
+	})
+}
+
+// ChangeStatus moves a project to a new lifecycle status
+func (s *ProjectLifecycleService) ChangeStatus(projectID, userID uint, to, reason string) (*model.Project, error) {
+	tx := s.DB.Begin()
+
+	var project model.Project
+	if err := tx.First(&project, projectID).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.New("project not found")
+	}
+	allowed, err := HasProjectPermission(tx, &project, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !allowed {
+		tx.Rollback()
+		return nil, errors.New("you are not authorized to change the status of this project")
+	}
+
+	if err := s.applyTransition(tx, &project, to, userID, reason); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to update project status: %v", err)
+	}
+
+	return &project, nil
+}
+
+// GetStatusHistory returns the lifecycle transitions of a project, newest first.
+// It is visible to the owner, users with project access and accepted members.
+func (s *ProjectLifecycleService) GetStatusHistory(projectID, userID uint) ([]model.ProjectStatusHistory, error) {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+
+	if !CanViewProject(s.DB, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+
+	var history []model.ProjectStatusHistory
+	if err := s.DB.Preload("ChangedByUser").
+		Where("project_id = ?", projectID).
+		Order("created_at DESC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get status history: %v", err)
+	}
+
+	return history, nil
+}
@@ -12,12 +12,16 @@ import (
 type ProjectMemberService struct {
 	DB                  *gorm.DB
 	NotificationService *NotificationService
+	OutboxService       *OutboxService
+	WebhookService      *WebhookService
 }
 
-func NewProjectMemberService(db *gorm.DB, notificationService *NotificationService) *ProjectMemberService {
+func NewProjectMemberService(db *gorm.DB, notificationService *NotificationService, outboxService *OutboxService, webhookService *WebhookService) *ProjectMemberService {
 	return &ProjectMemberService{
 		DB:                  db,
 		NotificationService: notificationService,
+		OutboxService:       outboxService,
+		WebhookService:      webhookService,
 	}
 }
 
@@ -37,7 +41,7 @@ func (s *ProjectMemberService) ApplyToProject(userID, projectID uint, applicatio
 		return nil, errors.New("project not found")
 	}
 
-	if project.Status != "published" {
+	if project.Status != model.ProjectStatusPublished {
 		return nil, errors.New("project is not accepting applications")
 	}
 
@@ -91,7 +95,32 @@ func (s *ProjectMemberService) ApplyToProject(userID, projectID uint, applicatio
 		AppliedAt:        time.Now(),
 	}
 
-	if err := s.DB.Create(application).Error; err != nil {
+	tx := s.DB.Begin()
+	if err := tx.Create(application).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create application: %v", err)
+	}
+
+	// Queue notification to project creator
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyUserRegistered, NotificationPayload{
+		ProjectID: projectID,
+		UserID:    userID,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.WebhookService.Dispatch(tx, projectID, model.WebhookEventApplicationSubmitted, map[string]interface{}{
+		"application_id":  application.ID,
+		"user_id":         userID,
+		"project_role_id": role.ID,
+		"role_name":       role.Name,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to create application: %v", err)
 	}
 
@@ -100,20 +129,12 @@ func (s *ProjectMemberService) ApplyToProject(userID, projectID uint, applicatio
 		return nil, fmt.Errorf("failed to load application details: %v", err)
 	}
 
-	// Send notification to project creator
-	if err := s.NotificationService.NotifyUserRegistered(projectID, userID); err != nil {
-		// Log error but don't fail the application
-		fmt.Printf("Failed to send notification: %v\n", err)
-	}
-
 	return application, nil
 }
 
-// GetProjectApplications retrieves applications for a project (for project owners)
-func (s *ProjectMemberService) GetProjectApplications(projectID, creatorID uint) ([]model.ProjectApplication, error) {
-	// Verify the user is the project creator
-	var project model.Project
-	if err := s.DB.Where("id = ? AND creator_id = ?", projectID, creatorID).First(&project).Error; err != nil {
+// GetProjectApplications retrieves applications for a project (for users who can review them)
+func (s *ProjectMemberService) GetProjectApplications(projectID, userID uint) ([]model.ProjectApplication, error) {
+	if _, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionReviewApplications); err != nil {
 		return nil, errors.New("project not found or unauthorized")
 	}
 
@@ -134,6 +155,7 @@ func (s *ProjectMemberService) GetProjectApplications(projectID, creatorID uint)
 func (s *ProjectMemberService) GetUserApplications(userID uint) ([]model.ProjectApplication, error) {
 	var applications []model.ProjectApplication
 	if err := s.DB.Where("user_id = ?", userID).
+		Where(notDeletedProjectCondition).
 		Preload("Project").
 		Preload("ProjectRole").
 		Preload("Reviewer").
@@ -149,6 +171,7 @@ func (s *ProjectMemberService) GetUserApplications(userID uint) ([]model.Project
 func (s *ProjectMemberService) GetUserInvitations(userID uint) ([]model.ProjectMember, error) {
 	var invitations []model.ProjectMember
 	if err := s.DB.Where("user_id = ? AND status = ?", userID, "invited").
+		Where(notDeletedProjectCondition).
 		Preload("Project").
 		Preload("ProjectRole").
 		Order("created_at DESC").
@@ -165,7 +188,7 @@ type ReviewApplicationData struct {
 	ReviewNotes string `json:"review_notes"`
 }
 
-// ReviewApplication allows project creator to accept or reject an application
+// ReviewApplication allows a user who can review applications to accept or reject one
 func (s *ProjectMemberService) ReviewApplication(applicationID, reviewerID uint, reviewData ReviewApplicationData) error {
 	if reviewData.Action != "accept" && reviewData.Action != "reject" {
 		return errors.New("invalid action. Must be 'accept' or 'reject'")
@@ -176,11 +199,19 @@ func (s *ProjectMemberService) ReviewApplication(applicationID, reviewerID uint,
 		return errors.New("application not found")
 	}
 
-	// Verify the reviewer is the project creator
-	if application.Project.CreatorID != reviewerID {
+	// Verify the reviewer may review applications of the project
+	allowed, err := HasProjectPermission(s.DB, &application.Project, reviewerID, model.ProjectPermissionReviewApplications)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		return errors.New("unauthorized to review this application")
 	}
 
+	if application.Project.Status == model.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+
 	if application.Status != model.ApplicationStatusPending {
 		return errors.New("application has already been reviewed")
 	}
@@ -237,14 +268,33 @@ func (s *ProjectMemberService) ReviewApplication(applicationID, reviewerID uint,
 			return fmt.Errorf("failed to create project member: %v", err)
 		}
 
-		// Send acceptance notification
-		if err := s.NotificationService.NotifyUserAccepted(application.ProjectID, application.UserID, application.ProjectRole.Name); err != nil {
-			fmt.Printf("Failed to send acceptance notification: %v\n", err)
+		// Queue acceptance notification
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyUserAccepted, NotificationPayload{
+			ProjectID: application.ProjectID,
+			UserID:    application.UserID,
+			RoleTitle: application.ProjectRole.Name,
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := s.WebhookService.Dispatch(tx, application.ProjectID, model.WebhookEventMemberAccepted, map[string]interface{}{
+			"user_id":         application.UserID,
+			"project_role_id": application.ProjectRoleID,
+			"role_name":       application.ProjectRole.Name,
+			"via":             "application",
+		}); err != nil {
+			tx.Rollback()
+			return err
 		}
 	} else {
-		// Send rejection notification
-		if err := s.NotificationService.NotifyUserRejected(application.ProjectID, application.UserID); err != nil {
-			fmt.Printf("Failed to send rejection notification: %v\n", err)
+		// Queue rejection notification
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyUserRejected, NotificationPayload{
+			ProjectID: application.ProjectID,
+			UserID:    application.UserID,
+		}); err != nil {
+			tx.Rollback()
+			return err
 		}
 	}
 
@@ -269,16 +319,19 @@ func (s *ProjectMemberService) WithdrawApplication(applicationID, userID uint) e
 	return nil
 }
 
-// RemoveMember allows project creator to remove a member from the project
-func (s *ProjectMemberService) RemoveMember(projectID, memberUserID, creatorID uint) error {
-	// Verify the user is the project creator
-	var project model.Project
-	if err := s.DB.Where("id = ? AND creator_id = ?", projectID, creatorID).First(&project).Error; err != nil {
+// RemoveMember allows a user who manages members to remove a member from the project
+func (s *ProjectMemberService) RemoveMember(projectID, memberUserID, userID uint) error {
+	project, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionManageMembers)
+	if err != nil {
 		return errors.New("project not found or unauthorized")
 	}
 
+	if project.Status == model.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+
 	// Cannot remove the creator
-	if memberUserID == creatorID {
+	if memberUserID == project.CreatorID {
 		return errors.New("cannot remove project creator")
 	}
 
@@ -288,21 +341,42 @@ func (s *ProjectMemberService) RemoveMember(projectID, memberUserID, creatorID u
 		return errors.New("member not found")
 	}
 
-	if err := s.DB.Delete(&member).Error; err != nil {
+	tx := s.DB.Begin()
+	if err := tx.Delete(&member).Error; err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to remove member: %v", err)
 	}
 
-	return nil
+	// Tasks of a removed member go back to the board unassigned
+	if err := tx.Model(&model.ProjectTask{}).
+		Where("project_id = ? AND assignee_id = ?", projectID, memberUserID).
+		Update("assignee_id", nil).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to unassign member tasks: %v", err)
+	}
+
+	if err := s.WebhookService.Dispatch(tx, projectID, model.WebhookEventMemberRemoved, map[string]interface{}{
+		"user_id":         member.UserID,
+		"project_role_id": member.ProjectRoleID,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
-// InviteMember allows project creator to invite a user to join the project
-func (s *ProjectMemberService) InviteMember(projectID, userID, roleID, creatorID uint) error {
-	// Verify the user is the project creator
-	var project model.Project
-	if err := s.DB.Where("id = ? AND creator_id = ?", projectID, creatorID).First(&project).Error; err != nil {
+// InviteMember allows a user who manages members to invite a user to join the project
+func (s *ProjectMemberService) InviteMember(projectID, userID, roleID, inviterID uint) error {
+	project, err := AuthorizeProject(s.DB, projectID, inviterID, model.ProjectPermissionManageMembers)
+	if err != nil {
 		return errors.New("project not found or unauthorized")
 	}
 
+	if project.Status == model.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+
 	// Check if role exists
 	var role model.ProjectRole
 	if err := s.DB.Where("id = ? AND project_id = ?", roleID, projectID).First(&role).Error; err != nil {
@@ -347,16 +421,23 @@ func (s *ProjectMemberService) InviteMember(projectID, userID, roleID, creatorID
 		Status:        "invited",
 	}
 
-	if err := s.DB.Create(member).Error; err != nil {
+	tx := s.DB.Begin()
+	if err := tx.Create(member).Error; err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to create invitation: %v", err)
 	}
 
-	// Send invitation notification
-	if err := s.NotificationService.NotifyInvitationReceived(projectID, userID, role.Name); err != nil {
-		fmt.Printf("Failed to send invitation notification: %v", err)
+	// Queue invitation notification
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyInvitation, NotificationPayload{
+		ProjectID: projectID,
+		UserID:    userID,
+		RoleTitle: role.Name,
+	}); err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	return nil
+	return tx.Commit().Error
 }
 
 // RespondToInvitation allows a user to accept or decline an invitation
@@ -365,6 +446,15 @@ func (s *ProjectMemberService) RespondToInvitation(projectID, userID uint, respo
 		return errors.New("invalid response. Must be 'accept' or 'decline'")
 	}
 
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return errors.New("invitation not found")
+	}
+
+	if project.Status == model.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+
 	var member model.ProjectMember
 	if err := s.DB.Preload("ProjectRole").Where("project_id = ? AND user_id = ? AND status = ?", projectID, userID, "invited").First(&member).Error; err != nil {
 		return errors.New("invitation not found")
@@ -373,18 +463,37 @@ func (s *ProjectMemberService) RespondToInvitation(projectID, userID uint, respo
 	newStatus := "declined"
 	if response == "accept" {
 		newStatus = "accepted"
-
-		// Send role assignment notification
-		if err := s.NotificationService.NotifyRoleAssigned(projectID, userID, member.ProjectRole.Name); err != nil {
-			fmt.Printf("Failed to send role assignment notification: %v", err)
-		}
 	}
 
-	if err := s.DB.Model(&member).Update("status", newStatus).Error; err != nil {
+	tx := s.DB.Begin()
+	if err := tx.Model(&member).Update("status", newStatus).Error; err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to update invitation status: %v", err)
 	}
 
-	return nil
+	if newStatus == "accepted" {
+		// Queue role assignment notification
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyRoleAssigned, NotificationPayload{
+			ProjectID: projectID,
+			UserID:    userID,
+			RoleTitle: member.ProjectRole.Name,
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := s.WebhookService.Dispatch(tx, projectID, model.WebhookEventMemberAccepted, map[string]interface{}{
+			"user_id":         userID,
+			"project_role_id": member.ProjectRoleID,
+			"role_name":       member.ProjectRole.Name,
+			"via":             "invitation",
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
 }
 
 // GetProjectMembers retrieves all members of a project
@@ -428,9 +537,15 @@ func (s *ProjectMemberService) GetApplicationDetails(applicationID, requesterID
 		return nil, fmt.Errorf("application not found: %v", err)
 	}
 
-	// Check if requester has permission to view (project owner or applicant)
-	if application.Project.CreatorID != requesterID && application.UserID != requesterID {
-		return nil, errors.New("unauthorized to view this application")
+	// Check if requester has permission to view (applicant or a user who can review applications)
+	if application.UserID != requesterID {
+		allowed, err := HasProjectPermission(s.DB, &application.Project, requesterID, model.ProjectPermissionReviewApplications)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errors.New("unauthorized to view this application")
+		}
 	}
 
 	return &application, nil
@@ -0,0 +1,759 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// MilestoneDTO describes one milestone when the timeline of a project is replaced as a whole.
+// DependsOn refers to other milestones of the same list by name.
+type MilestoneDTO struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Status      string     `json:"status"`
+	StartDate   *time.Time `json:"start_date,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	DependsOn   []string   `json:"depends_on,omitempty"`
+}
+
+// MilestoneInput contains the editable fields of a milestone; nil fields are left unchanged.
+// A nil DependsOn keeps the dependencies, an empty one clears them.
+type MilestoneInput struct {
+	Name            *string
+	Description     *string
+	Status          *string
+	PercentComplete *int
+	Position        *int
+	StartDate       *time.Time
+	DueDate         *time.Time
+	ClearStartDate  bool
+	ClearDueDate    bool
+	DependsOn       []uint
+}
+
+// ProjectProgress summarises how far a project is through its milestones
+type ProjectProgress struct {
+	PercentComplete   int `json:"percent_complete"`
+	MilestonesTotal   int `json:"milestones_total"`
+	MilestonesDone    int `json:"milestones_done"`
+	MilestonesOverdue int `json:"milestones_overdue"`
+}
+
+// ProjectMilestones is the ordered milestone list of a project together with its overall progress
+type ProjectMilestones struct {
+	Milestones []*model.ProjectMilestone `json:"milestones"`
+	Progress   ProjectProgress           `json:"progress"`
+}
+
+type ProjectMilestoneService struct {
+	DB             *gorm.DB
+	webhookService *WebhookService
+}
+
+func NewProjectMilestoneService(db *gorm.DB, webhookService *WebhookService) *ProjectMilestoneService {
+	return &ProjectMilestoneService{
+		DB:             db,
+		webhookService: webhookService,
+	}
+}
+
+// orderMilestones is used when preloading milestones so they come back in timeline order
+func orderMilestones(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// calculateProjectProgress averages the completion of every milestone
+func calculateProjectProgress(milestones []*model.ProjectMilestone) ProjectProgress {
+	progress := ProjectProgress{MilestonesTotal: len(milestones)}
+	if len(milestones) == 0 {
+		return progress
+	}
+
+	now := time.Now()
+	total := 0
+	for _, milestone := range milestones {
+		total += milestone.PercentComplete
+		if milestone.Status == helper.TimelineStatusDone {
+			progress.MilestonesDone++
+		} else if milestone.DueDate != nil && milestone.DueDate.Before(now) {
+			progress.MilestonesOverdue++
+		}
+	}
+	progress.PercentComplete = total / len(milestones)
+	return progress
+}
+
+func validateMilestoneStatus(status string) error {
+	if !helper.IsValidTimelineStatus(status) {
+		return errors.New("invalid milestone status: " + status + ". Must be one of: " + strings.Join(helper.GetValidTimelineStatuses(), ", "))
+	}
+	return nil
+}
+
+func validateMilestoneDates(milestone *model.ProjectMilestone) error {
+	if milestone.StartDate != nil && milestone.DueDate != nil && milestone.DueDate.Before(*milestone.StartDate) {
+		return fmt.Errorf("milestone %q is due before it starts", milestone.Name)
+	}
+	return nil
+}
+
+// applyMilestoneStatus sets the status of a milestone and keeps its percentage and completion time in line with it
+func applyMilestoneStatus(milestone *model.ProjectMilestone, status string) {
+	milestone.Status = status
+	switch status {
+	case helper.TimelineStatusDone:
+		milestone.PercentComplete = 100
+		if milestone.CompletedAt == nil {
+			now := time.Now()
+			milestone.CompletedAt = &now
+		}
+	case helper.TimelineStatusNotStarted:
+		milestone.PercentComplete = 0
+		milestone.CompletedAt = nil
+	default:
+		milestone.CompletedAt = nil
+		if milestone.PercentComplete == 100 {
+			milestone.PercentComplete = 99
+		}
+	}
+}
+
+// applyMilestonePercent sets the completion of a milestone and moves its status along with it
+func applyMilestonePercent(milestone *model.ProjectMilestone, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("percent complete must be between 0 and 100")
+	}
+
+	switch {
+	case percent == 100:
+		applyMilestoneStatus(milestone, helper.TimelineStatusDone)
+	case milestone.Status == helper.TimelineStatusDone || (percent > 0 && milestone.Status == helper.TimelineStatusNotStarted):
+		applyMilestoneStatus(milestone, helper.TimelineStatusInProgress)
+	}
+	milestone.PercentComplete = percent
+	return nil
+}
+
+func milestoneHasTasks(tx *gorm.DB, milestoneID uint) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.ProjectTask{}).Where("milestone_id = ?", milestoneID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to count milestone tasks: %v", err)
+	}
+	return count > 0, nil
+}
+
+// rollUpMilestone derives the status and completion of a milestone from its tasks:
+// done when every task is done, not started when none has been picked up, in progress otherwise.
+// Milestones without tasks keep the status and completion set on them.
+func rollUpMilestone(tx *gorm.DB, milestoneID *uint) error {
+	if milestoneID == nil {
+		return nil
+	}
+
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := tx.Model(&model.ProjectTask{}).
+		Select("status, COUNT(*) AS count").
+		Where("milestone_id = ?", *milestoneID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to count milestone tasks: %v", err)
+	}
+
+	var total, done, todo int64
+	for _, row := range rows {
+		total += row.Count
+		switch row.Status {
+		case model.TaskStatusDone:
+			done += row.Count
+		case model.TaskStatusTodo:
+			todo += row.Count
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var milestone model.ProjectMilestone
+	if err := tx.First(&milestone, *milestoneID).Error; err != nil {
+		return fmt.Errorf("failed to load milestone: %v", err)
+	}
+
+	status := helper.TimelineStatusInProgress
+	if done == total {
+		status = helper.TimelineStatusDone
+	} else if todo == total {
+		status = helper.TimelineStatusNotStarted
+	}
+	applyMilestoneStatus(&milestone, status)
+	milestone.PercentComplete = int(done * 100 / total)
+
+	if err := tx.Model(&milestone).Updates(map[string]interface{}{
+		"status":           milestone.Status,
+		"percent_complete": milestone.PercentComplete,
+		"completed_at":     milestone.CompletedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update milestone progress: %v", err)
+	}
+	return nil
+}
+
+func validateTaskMilestone(tx *gorm.DB, projectID, milestoneID uint) error {
+	var count int64
+	if err := tx.Model(&model.ProjectMilestone{}).
+		Where("id = ? AND project_id = ?", milestoneID, projectID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check milestone: %v", err)
+	}
+	if count == 0 {
+		return errors.New("milestone not found in this project")
+	}
+	return nil
+}
+
+// dependencyReaches reports whether from depends on target, directly or through other milestones
+func dependencyReaches(graph map[uint][]uint, from, target uint, visited map[uint]bool) bool {
+	if from == target {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+	for _, next := range graph[from] {
+		if dependencyReaches(graph, next, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// setMilestoneDependencies replaces the dependencies of a milestone. Dependencies must be
+// other milestones of the same project and may not form a cycle.
+func setMilestoneDependencies(tx *gorm.DB, projectID, milestoneID uint, dependsOn []uint) error {
+	var projectMilestoneIDs []uint
+	if err := tx.Model(&model.ProjectMilestone{}).Where("project_id = ?", projectID).Pluck("id", &projectMilestoneIDs).Error; err != nil {
+		return fmt.Errorf("failed to load milestones: %v", err)
+	}
+	inProject := make(map[uint]bool, len(projectMilestoneIDs))
+	for _, id := range projectMilestoneIDs {
+		inProject[id] = true
+	}
+
+	var dependencies []model.ProjectMilestoneDependency
+	if err := tx.Where("milestone_id IN ?", projectMilestoneIDs).Find(&dependencies).Error; err != nil {
+		return fmt.Errorf("failed to load milestone dependencies: %v", err)
+	}
+	graph := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		if dependency.MilestoneID != milestoneID {
+			graph[dependency.MilestoneID] = append(graph[dependency.MilestoneID], dependency.DependsOnID)
+		}
+	}
+
+	seen := make(map[uint]bool)
+	var unique []uint
+	for _, dependsOnID := range dependsOn {
+		if seen[dependsOnID] {
+			continue
+		}
+		if dependsOnID == milestoneID {
+			return errors.New("a milestone cannot depend on itself")
+		}
+		if !inProject[dependsOnID] {
+			return fmt.Errorf("milestone %d not found in this project", dependsOnID)
+		}
+		if dependencyReaches(graph, dependsOnID, milestoneID, make(map[uint]bool)) {
+			return errors.New("milestone dependencies cannot form a cycle")
+		}
+		seen[dependsOnID] = true
+		unique = append(unique, dependsOnID)
+	}
+
+	if err := tx.Where("milestone_id = ?", milestoneID).Delete(&model.ProjectMilestoneDependency{}).Error; err != nil {
+		return fmt.Errorf("failed to clear milestone dependencies: %v", err)
+	}
+	for _, dependsOnID := range unique {
+		if err := tx.Create(&model.ProjectMilestoneDependency{MilestoneID: milestoneID, DependsOnID: dependsOnID}).Error; err != nil {
+			return fmt.Errorf("failed to add milestone dependency: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkDependenciesDone makes sure a milestone only starts once everything it depends on is done
+func checkDependenciesDone(tx *gorm.DB, milestone *model.ProjectMilestone) error {
+	if milestone.Status == helper.TimelineStatusNotStarted {
+		return nil
+	}
+
+	var pending []string
+	if err := tx.Model(&model.ProjectMilestone{}).
+		Where("id IN (SELECT depends_on_id FROM project_milestone_dependencies WHERE milestone_id = ?)", milestone.ID).
+		Where("status <> ?", helper.TimelineStatusDone).
+		Order("position ASC").
+		Pluck("name", &pending).Error; err != nil {
+		return fmt.Errorf("failed to check milestone dependencies: %v", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("milestone %q cannot start before %s is done", milestone.Name, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// deleteMilestone removes a milestone and its dependencies; its tasks stay on the board without a milestone
+func deleteMilestone(tx *gorm.DB, milestoneID uint) error {
+	if err := tx.Model(&model.ProjectTask{}).Where("milestone_id = ?", milestoneID).Update("milestone_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to detach milestone tasks: %v", err)
+	}
+	if err := tx.Where("milestone_id = ? OR depends_on_id = ?", milestoneID, milestoneID).Delete(&model.ProjectMilestoneDependency{}).Error; err != nil {
+		return fmt.Errorf("failed to delete milestone dependencies: %v", err)
+	}
+	if err := tx.Delete(&model.ProjectMilestone{}, milestoneID).Error; err != nil {
+		return fmt.Errorf("failed to delete milestone: %v", err)
+	}
+	return nil
+}
+
+// replaceProjectMilestones makes the milestones of a project match data, in that order.
+// Existing milestones are matched by name so their tasks and history survive; dates, description
+// and status are only changed when given. Milestones missing from data are deleted.
+func replaceProjectMilestones(tx *gorm.DB, projectID uint, data []MilestoneDTO) error {
+	seenNames := make(map[string]bool)
+	for i := range data {
+		data[i].Name = strings.TrimSpace(data[i].Name)
+		key := strings.ToLower(data[i].Name)
+		if key == "" {
+			return errors.New("milestone name is required")
+		}
+		if seenNames[key] {
+			return errors.New("duplicate milestone: " + data[i].Name)
+		}
+		seenNames[key] = true
+		if data[i].Status != "" {
+			if err := validateMilestoneStatus(data[i].Status); err != nil {
+				return err
+			}
+		}
+	}
+
+	var existing []*model.ProjectMilestone
+	if err := tx.Where("project_id = ?", projectID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load milestones: %v", err)
+	}
+	remaining := make(map[string]*model.ProjectMilestone, len(existing))
+	for _, milestone := range existing {
+		remaining[strings.ToLower(milestone.Name)] = milestone
+	}
+
+	saved := make(map[string]*model.ProjectMilestone, len(data))
+	for position, item := range data {
+		key := strings.ToLower(item.Name)
+		milestone, found := remaining[key]
+		if !found {
+			milestone = &model.ProjectMilestone{ProjectID: projectID}
+			applyMilestoneStatus(milestone, helper.GetDefaultTimelineStatus())
+		}
+
+		milestone.Name = item.Name
+		milestone.Position = position
+		if item.Description != "" {
+			milestone.Description = item.Description
+		}
+		if item.StartDate != nil {
+			milestone.StartDate = item.StartDate
+		}
+		if item.DueDate != nil {
+			milestone.DueDate = item.DueDate
+		}
+		if item.Status != "" {
+			applyMilestoneStatus(milestone, item.Status)
+		}
+		if err := validateMilestoneDates(milestone); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Dependencies").Save(milestone).Error; err != nil {
+			return fmt.Errorf("failed to save milestone: %v", err)
+		}
+		saved[key] = milestone
+		delete(remaining, key)
+	}
+
+	for _, milestone := range remaining {
+		if err := deleteMilestone(tx, milestone.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range data {
+		if item.DependsOn == nil {
+			continue
+		}
+		milestone := saved[strings.ToLower(item.Name)]
+		dependsOn := make([]uint, 0, len(item.DependsOn))
+		for _, name := range item.DependsOn {
+			dependency, ok := saved[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return fmt.Errorf("milestone %q depends on unknown milestone %q", item.Name, name)
+			}
+			dependsOn = append(dependsOn, dependency.ID)
+		}
+		if err := setMilestoneDependencies(tx, projectID, milestone.ID, dependsOn); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range data {
+		milestone := saved[strings.ToLower(item.Name)]
+		if item.Status != "" {
+			if err := checkDependenciesDone(tx, milestone); err != nil {
+				return err
+			}
+		}
+		// Milestones with tasks keep the progress rolled up from them
+		if err := rollUpMilestone(tx, &milestone.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProjectMilestoneService) loadMilestones(tx *gorm.DB, projectID uint) ([]*model.ProjectMilestone, error) {
+	var milestones []*model.ProjectMilestone
+	if err := orderMilestones(tx.Preload("Dependencies")).
+		Where("project_id = ?", projectID).
+		Find(&milestones).Error; err != nil {
+		return nil, fmt.Errorf("failed to get milestones: %v", err)
+	}
+	return milestones, nil
+}
+
+func (s *ProjectMilestoneService) getMilestone(tx *gorm.DB, projectID, milestoneID uint) (*model.ProjectMilestone, error) {
+	var milestone model.ProjectMilestone
+	if err := tx.Preload("Dependencies").
+		Where("id = ? AND project_id = ?", milestoneID, projectID).
+		First(&milestone).Error; err != nil {
+		return nil, errors.New("milestone not found")
+	}
+	return &milestone, nil
+}
+
+// authorizeMilestoneEdit checks that the user may edit the project and that it is not archived
+func (s *ProjectMilestoneService) authorizeMilestoneEdit(tx *gorm.DB, projectID, userID uint) (*model.Project, error) {
+	project, err := AuthorizeProject(tx, projectID, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if project.Status == model.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+	return project, nil
+}
+
+// GetMilestones lists the milestones of a project with its overall progress.
+// Milestones of published projects are public, like the rest of the project page.
+func (s *ProjectMilestoneService) GetMilestones(projectID, userID uint) (*ProjectMilestones, error) {
+	var project model.Project
+	if err := s.DB.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	if (project.Status == model.ProjectStatusDraft || project.Status == model.ProjectStatusArchived) && !CanViewProject(s.DB, &project, userID) {
+		return nil, errors.New("project not found")
+	}
+
+	milestones, err := s.loadMilestones(s.DB, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectMilestones{
+		Milestones: milestones,
+		Progress:   calculateProjectProgress(milestones),
+	}, nil
+}
+
+// CreateMilestone adds a milestone, at the end of the timeline unless a position is given
+func (s *ProjectMilestoneService) CreateMilestone(projectID, userID uint, data MilestoneInput) (*model.ProjectMilestone, error) {
+	if data.Name == nil || strings.TrimSpace(*data.Name) == "" {
+		return nil, errors.New("milestone name is required")
+	}
+
+	tx := s.DB.Begin()
+	if _, err := s.authorizeMilestoneEdit(tx, projectID, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	milestones, err := s.loadMilestones(tx, projectID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	milestone := &model.ProjectMilestone{
+		ProjectID: projectID,
+		Position:  len(milestones),
+	}
+	applyMilestoneStatus(milestone, helper.GetDefaultTimelineStatus())
+	if err := s.applyMilestoneInput(tx, milestone, milestones, data); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Omit("Dependencies").Create(milestone).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create milestone: %v", err)
+	}
+
+	if err := s.finishMilestoneChange(tx, projectID, milestone, milestones, data, "created"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return s.getMilestone(s.DB, projectID, milestone.ID)
+}
+
+// UpdateMilestone changes a milestone. The status and completion of milestones with tasks roll up
+// from the tasks and cannot be set directly.
+func (s *ProjectMilestoneService) UpdateMilestone(projectID, milestoneID, userID uint, data MilestoneInput) (*model.ProjectMilestone, error) {
+	tx := s.DB.Begin()
+	if _, err := s.authorizeMilestoneEdit(tx, projectID, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	milestone, err := s.getMilestone(tx, projectID, milestoneID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if data.Status != nil || data.PercentComplete != nil {
+		hasTasks, err := milestoneHasTasks(tx, milestone.ID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if hasTasks {
+			tx.Rollback()
+			return nil, errors.New("the progress of this milestone is tracked by its tasks")
+		}
+	}
+
+	milestones, err := s.loadMilestones(tx, projectID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.applyMilestoneInput(tx, milestone, milestones, data); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Omit("Dependencies").Save(milestone).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update milestone: %v", err)
+	}
+
+	if err := s.finishMilestoneChange(tx, projectID, milestone, milestones, data, "updated"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return s.getMilestone(s.DB, projectID, milestone.ID)
+}
+
+// applyMilestoneInput copies the given fields onto milestone
+func (s *ProjectMilestoneService) applyMilestoneInput(tx *gorm.DB, milestone *model.ProjectMilestone, milestones []*model.ProjectMilestone, data MilestoneInput) error {
+	if data.Name != nil {
+		name := strings.TrimSpace(*data.Name)
+		if name == "" {
+			return errors.New("milestone name is required")
+		}
+		for _, other := range milestones {
+			if other.ID != milestone.ID && strings.EqualFold(other.Name, name) {
+				return errors.New("duplicate milestone: " + name)
+			}
+		}
+		milestone.Name = name
+	}
+	if data.Description != nil {
+		milestone.Description = *data.Description
+	}
+	if data.ClearStartDate {
+		milestone.StartDate = nil
+	} else if data.StartDate != nil {
+		milestone.StartDate = data.StartDate
+	}
+	if data.ClearDueDate {
+		milestone.DueDate = nil
+	} else if data.DueDate != nil {
+		milestone.DueDate = data.DueDate
+	}
+	if err := validateMilestoneDates(milestone); err != nil {
+		return err
+	}
+
+	if data.Status != nil {
+		if err := validateMilestoneStatus(*data.Status); err != nil {
+			return err
+		}
+		applyMilestoneStatus(milestone, *data.Status)
+	}
+	if data.PercentComplete != nil {
+		if err := applyMilestonePercent(milestone, *data.PercentComplete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishMilestoneChange stores the dependencies and position of a created or updated milestone,
+// enforces that it does not start before its dependencies and notifies webhook subscribers
+func (s *ProjectMilestoneService) finishMilestoneChange(tx *gorm.DB, projectID uint, milestone *model.ProjectMilestone, milestones []*model.ProjectMilestone, data MilestoneInput, action string) error {
+	if data.DependsOn != nil {
+		if err := setMilestoneDependencies(tx, projectID, milestone.ID, data.DependsOn); err != nil {
+			return err
+		}
+	}
+	if data.Status != nil || data.PercentComplete != nil || data.DependsOn != nil {
+		if err := checkDependenciesDone(tx, milestone); err != nil {
+			return err
+		}
+	}
+
+	if data.Position != nil {
+		order := make([]uint, 0, len(milestones)+1)
+		for _, other := range milestones {
+			if other.ID != milestone.ID {
+				order = append(order, other.ID)
+			}
+		}
+		position := *data.Position
+		if position < 0 {
+			position = 0
+		}
+		if position > len(order) {
+			position = len(order)
+		}
+		order = append(order[:position], append([]uint{milestone.ID}, order[position:]...)...)
+		if err := saveMilestoneOrder(tx, order); err != nil {
+			return err
+		}
+	}
+
+	return s.webhookService.Dispatch(tx, projectID, model.WebhookEventTimelineUpdated, map[string]interface{}{
+		"action":    action,
+		"milestone": milestone,
+	})
+}
+
+func saveMilestoneOrder(tx *gorm.DB, order []uint) error {
+	for position, milestoneID := range order {
+		if err := tx.Model(&model.ProjectMilestone{}).Where("id = ?", milestoneID).Update("position", position).Error; err != nil {
+			return fmt.Errorf("failed to reorder milestones: %v", err)
+		}
+	}
+	return nil
+}
+
+// ReorderMilestones puts the milestones of a project in the given order; every milestone must be listed once
+func (s *ProjectMilestoneService) ReorderMilestones(projectID, userID uint, order []uint) (*ProjectMilestones, error) {
+	tx := s.DB.Begin()
+	if _, err := s.authorizeMilestoneEdit(tx, projectID, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	milestones, err := s.loadMilestones(tx, projectID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	listed := make(map[uint]bool, len(order))
+	for _, milestoneID := range order {
+		listed[milestoneID] = true
+	}
+	if len(order) != len(milestones) || len(listed) != len(milestones) {
+		tx.Rollback()
+		return nil, errors.New("the new order must list every milestone of the project exactly once")
+	}
+	for _, milestone := range milestones {
+		if !listed[milestone.ID] {
+			tx.Rollback()
+			return nil, errors.New("the new order must list every milestone of the project exactly once")
+		}
+	}
+
+	if err := saveMilestoneOrder(tx, order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.webhookService.Dispatch(tx, projectID, model.WebhookEventTimelineUpdated, map[string]interface{}{
+		"action": "reordered",
+		"order":  order,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return s.GetMilestones(projectID, userID)
+}
+
+// DeleteMilestone removes a milestone; its tasks stay on the board and milestones depending on it lose that dependency
+func (s *ProjectMilestoneService) DeleteMilestone(projectID, milestoneID, userID uint) error {
+	tx := s.DB.Begin()
+	if _, err := s.authorizeMilestoneEdit(tx, projectID, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	milestone, err := s.getMilestone(tx, projectID, milestoneID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := deleteMilestone(tx, milestone.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Close the gap left in the ordering
+	if err := tx.Model(&model.ProjectMilestone{}).
+		Where("project_id = ? AND position > ?", projectID, milestone.Position).
+		Update("position", gorm.Expr("position - 1")).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to reorder milestones: %v", err)
+	}
+
+	if err := s.webhookService.Dispatch(tx, projectID, model.WebhookEventTimelineUpdated, map[string]interface{}{
+		"action":    "deleted",
+		"milestone": milestone,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
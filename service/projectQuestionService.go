@@ -0,0 +1,288 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	maxQuestionLength = 1000
+	maxAnswerLength   = 5000
+)
+
+type ProjectQuestionService struct {
+	DB            *gorm.DB
+	OutboxService *OutboxService
+}
+
+func NewProjectQuestionService(db *gorm.DB, outboxService *OutboxService) *ProjectQuestionService {
+	return &ProjectQuestionService{
+		DB:            db,
+		OutboxService: outboxService,
+	}
+}
+
+// QuestionFilter narrows the questions of a project; zero values match everything
+type QuestionFilter struct {
+	FAQOnly        bool
+	UnansweredOnly bool
+}
+
+// orderQuestions lists FAQ entries first, then answered questions, then the rest, newest first
+func orderQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("is_faq DESC, answered_at IS NULL, created_at DESC, id DESC")
+}
+
+// fillQuestionAskers copies the name of each preloaded asker onto its question
+func fillQuestionAskers(questions []*model.ProjectQuestion) {
+	for _, question := range questions {
+		question.AskerName = question.Asker.Name
+	}
+}
+
+// loadPublicQuestions returns the questions shown on a project's public page
+func loadPublicQuestions(db *gorm.DB, projectID uint) ([]*model.ProjectQuestion, error) {
+	var questions []*model.ProjectQuestion
+	if err := orderQuestions(db.Preload("Asker")).
+		Where("project_id = ? AND hidden = ?", projectID, false).
+		Find(&questions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load questions: %v", err)
+	}
+	fillQuestionAskers(questions)
+	return questions, nil
+}
+
+// loadPublicProject loads a project that is shown publicly: not a draft and not archived
+func (s *ProjectQuestionService) loadPublicProject(tx *gorm.DB, projectID uint) (*model.Project, error) {
+	var project model.Project
+	if err := tx.Where("id = ? AND status NOT IN ?", projectID, []string{model.ProjectStatusDraft, model.ProjectStatusArchived}).
+		First(&project).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	return &project, nil
+}
+
+// loadQuestionForAnswer loads a question of a project the user can answer on
+func (s *ProjectQuestionService) loadQuestionForAnswer(tx *gorm.DB, projectID, questionID, userID uint) (*model.Project, *model.ProjectQuestion, error) {
+	project, err := s.loadPublicProject(tx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	allowed, err := HasProjectPermission(tx, project, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, errors.New("only the project owners and managers can manage questions")
+	}
+
+	var question model.ProjectQuestion
+	if err := tx.Preload("Asker").
+		Where("id = ? AND project_id = ?", questionID, projectID).
+		First(&question).Error; err != nil {
+		return nil, nil, errors.New("question not found")
+	}
+	question.AskerName = question.Asker.Name
+	return project, &question, nil
+}
+
+// AskQuestion adds a question to the public thread of a published or running project
+func (s *ProjectQuestionService) AskQuestion(projectID, userID uint, text string) (*model.ProjectQuestion, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("question is required")
+	}
+	if len(text) > maxQuestionLength {
+		return nil, fmt.Errorf("question is too long, maximum is %d characters", maxQuestionLength)
+	}
+
+	tx := s.DB.Begin()
+	project, err := s.loadPublicProject(tx, projectID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if project.Status != model.ProjectStatusPublished && project.Status != model.ProjectStatusInProgress {
+		tx.Rollback()
+		return nil, errors.New("questions can only be asked on published or running projects")
+	}
+
+	question := &model.ProjectQuestion{
+		ProjectID: projectID,
+		AskerID:   userID,
+		Question:  text,
+	}
+	if err := tx.Create(question).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to ask question: %v", err)
+	}
+
+	recipients, err := projectUsersWithPermission(tx, project, model.ProjectPermissionEdit)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyQuestionAsked, NotificationPayload{
+		ProjectID:  projectID,
+		UserID:     userID,
+		QuestionID: question.ID,
+		Recipients: recipients,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to ask question: %v", err)
+	}
+
+	if err := s.DB.Preload("Asker").First(question, question.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load question: %v", err)
+	}
+	question.AskerName = question.Asker.Name
+	return question, nil
+}
+
+// GetQuestionsQuery returns a query over the questions of a project for pagination.
+// Owners and managers also see hidden questions.
+func (s *ProjectQuestionService) GetQuestionsQuery(projectID, userID uint, filter QuestionFilter) (*gorm.DB, error) {
+	project, err := s.loadPublicProject(s.DB, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := orderQuestions(s.DB.Model(&model.ProjectQuestion{}).Preload("Asker")).
+		Where("project_id = ?", projectID)
+
+	canManage, err := HasProjectPermission(s.DB, project, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		query = query.Where("hidden = ?", false)
+	}
+
+	if filter.FAQOnly {
+		query = query.Where("is_faq = ?", true)
+	}
+	if filter.UnansweredOnly {
+		query = query.Where("answered_at IS NULL")
+	}
+	return query, nil
+}
+
+// AnswerQuestion answers a question, or changes the answer; the asker is notified the first time
+func (s *ProjectQuestionService) AnswerQuestion(projectID, questionID, userID uint, answer string) (*model.ProjectQuestion, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, errors.New("answer is required")
+	}
+	if len(answer) > maxAnswerLength {
+		return nil, fmt.Errorf("answer is too long, maximum is %d characters", maxAnswerLength)
+	}
+
+	tx := s.DB.Begin()
+	_, question, err := s.loadQuestionForAnswer(tx, projectID, questionID, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	firstAnswer := question.AnsweredAt == nil
+	now := time.Now()
+	question.Answer = answer
+	question.AnsweredBy = &userID
+	question.AnsweredAt = &now
+	if err := tx.Model(question).Updates(map[string]interface{}{
+		"answer":      question.Answer,
+		"answered_by": question.AnsweredBy,
+		"answered_at": question.AnsweredAt,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to answer question: %v", err)
+	}
+
+	if firstAnswer {
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyQuestionAnswered, NotificationPayload{
+			ProjectID:  projectID,
+			UserID:     userID,
+			QuestionID: question.ID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to answer question: %v", err)
+	}
+	return question, nil
+}
+
+// SetFAQ marks an answered question as a FAQ entry, or removes the mark
+func (s *ProjectQuestionService) SetFAQ(projectID, questionID, userID uint, isFAQ bool) (*model.ProjectQuestion, error) {
+	_, question, err := s.loadQuestionForAnswer(s.DB, projectID, questionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isFAQ && question.AnsweredAt == nil {
+		return nil, errors.New("only answered questions can be marked as FAQ")
+	}
+
+	question.IsFAQ = isFAQ
+	if err := s.DB.Model(question).Update("is_faq", isFAQ).Error; err != nil {
+		return nil, fmt.Errorf("failed to update question: %v", err)
+	}
+	return question, nil
+}
+
+// SetHidden hides a question from the public thread, or shows it again
+func (s *ProjectQuestionService) SetHidden(projectID, questionID, userID uint, hidden bool) (*model.ProjectQuestion, error) {
+	_, question, err := s.loadQuestionForAnswer(s.DB, projectID, questionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	question.Hidden = hidden
+	if err := s.DB.Model(question).Update("hidden", hidden).Error; err != nil {
+		return nil, fmt.Errorf("failed to update question: %v", err)
+	}
+	return question, nil
+}
+
+// DeleteQuestion removes a question; askers can withdraw their own unanswered questions,
+// owners and managers can delete any
+func (s *ProjectQuestionService) DeleteQuestion(projectID, questionID, userID uint) error {
+	project, err := s.loadPublicProject(s.DB, projectID)
+	if err != nil {
+		return err
+	}
+
+	var question model.ProjectQuestion
+	if err := s.DB.Where("id = ? AND project_id = ?", questionID, projectID).First(&question).Error; err != nil {
+		return errors.New("question not found")
+	}
+
+	canManage, err := HasProjectPermission(s.DB, project, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		if question.AskerID != userID {
+			return errors.New("you can only delete your own questions")
+		}
+		if question.AnsweredAt != nil {
+			return errors.New("answered questions can no longer be withdrawn")
+		}
+	}
+
+	if err := s.DB.Delete(&question).Error; err != nil {
+		return fmt.Errorf("failed to delete question: %v", err)
+	}
+	return nil
+}
@@ -3,7 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"log"
 
 	"gorm.io/gorm"
 	"synergazing.com/synergazing/helper"
@@ -11,11 +11,14 @@ import (
 )
 
 type ProjectService struct {
-	DB              *gorm.DB
-	skillService    *SkillService
-	tagService      *TagService
-	benefitService  *BenefitService
-	timelineService *TimelineService
+	DB               *gorm.DB
+	skillService     *SkillService
+	tagService       *TagService
+	benefitService   *BenefitService
+	webhookService   *WebhookService
+	lifecycleService *ProjectLifecycleService
+	changeService    *ProjectChangeService
+	analyticsService *ProjectAnalyticsService
 }
 
 type RoleDTO struct {
@@ -39,11 +42,6 @@ type MemberResponse struct {
 	SkillNames      []string `json:"skill_names"`
 }
 
-type TimelineDTO struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
-}
-
 type CreatorWithProfileResponse struct {
 	ID                  uint   `json:"id"`
 	Name                string `json:"name"`
@@ -72,34 +70,52 @@ type ProjectResponseForMarshal struct {
 	Status               string                        `json:"status"`
 	ProjectType          string                        `json:"project_type"`
 	PictureURL           string                        `json:"picture_url"`
+	PictureVariants      map[string]string             `json:"picture_variants,omitempty"`
 	Duration             string                        `json:"duration"`
+	DurationDays         int                           `json:"duration_days"`
 	TotalTeam            int                           `json:"total_team"`
 	FilledTeam           int                           `json:"filled_team"`
 	RemainingTeam        int                           `json:"remaining_team"`
 	StartDate            string                        `json:"start_date"`
 	EndDate              string                        `json:"end_date"`
 	Location             string                        `json:"location"`
+	City                 string                        `json:"city"`
+	Region               string                        `json:"region"`
+	Latitude             *float64                      `json:"latitude"`
+	Longitude            *float64                      `json:"longitude"`
+	LocationMode         string                        `json:"location_mode"`
+	DistanceKm           *float64                      `json:"distance_km,omitempty"`
 	Budget               string                        `json:"budget"`
+	BudgetMin            *int64                        `json:"budget_min"`
+	BudgetMax            *int64                        `json:"budget_max"`
+	BudgetCurrency       string                        `json:"budget_currency"`
+	IsPaid               *bool                         `json:"is_paid"`
 	RegistrationDeadline string                        `json:"registration_deadline"`
 	TimeCommitment       string                        `json:"time_commitment"`
+	HoursPerWeek         *int                          `json:"hours_per_week"`
 	Benefits             []*model.ProjectBenefit       `json:"benefits"`
-	Timeline             []*model.ProjectTimeline      `json:"timeline"`
+	Milestones           []*model.ProjectMilestone     `json:"milestones"`
+	Progress             ProjectProgress               `json:"progress"`
 	RequiredSkills       []*model.ProjectRequiredSkill `json:"required_skills"`
 	Conditions           []*model.ProjectCondition     `json:"conditions"`
 	Tags                 []*model.ProjectTag           `json:"tags"`
 	Members              []MemberResponse              `json:"members"`
 	Roles                []*model.ProjectRole          `json:"roles"`
+	Questions            []*model.ProjectQuestion      `json:"questions,omitempty"`
 	CreatedAt            string                        `json:"created_at"`
 	UpdatedAt            string                        `json:"updated_at"`
 }
 
-func NewProjectService(db *gorm.DB, skillService *SkillService, tagService *TagService, benefitService *BenefitService, timelineService *TimelineService) *ProjectService {
+func NewProjectService(db *gorm.DB, skillService *SkillService, tagService *TagService, benefitService *BenefitService, webhookService *WebhookService) *ProjectService {
 	return &ProjectService{
-		DB:              db,
-		skillService:    skillService,
-		tagService:      tagService,
-		benefitService:  benefitService,
-		timelineService: timelineService,
+		DB:               db,
+		skillService:     skillService,
+		tagService:       tagService,
+		benefitService:   benefitService,
+		webhookService:   webhookService,
+		lifecycleService: NewProjectLifecycleService(db, webhookService.OutboxService, webhookService),
+		changeService:    NewProjectChangeService(db, webhookService.OutboxService),
+		analyticsService: NewProjectAnalyticsService(db),
 	}
 }
 
@@ -108,9 +124,16 @@ func (s *ProjectService) getProjectForUpdate(tx *gorm.DB, projectID, userID uint
 	if err := tx.First(&project, projectID).Error; err != nil {
 		return project, errors.New("project not found")
 	}
-	if project.CreatorID != userID {
+	allowed, err := HasProjectPermission(tx, &project, userID, model.ProjectPermissionEdit)
+	if err != nil {
+		return project, err
+	}
+	if !allowed {
 		return project, errors.New("you are not authorized to edit this project")
 	}
+	if project.Status == model.ProjectStatusArchived {
+		return project, ErrProjectArchived
+	}
 	if project.CompletionStage < requiredStage {
 		return project, fmt.Errorf("you must complete the previous stage %d", requiredStage)
 	}
@@ -198,23 +221,37 @@ func (s *ProjectService) transformProjectToResponseWithProfile(project *model.Pr
 		Status:               project.Status,
 		ProjectType:          project.ProjectType,
 		PictureURL:           helper.GetUrlFile(project.PictureURL),
+		PictureVariants:      helper.GetImageVariantURLs(project.PictureURL),
 		Duration:             project.Duration,
+		DurationDays:         project.DurationDays,
 		TotalTeam:            project.TotalTeam,
 		FilledTeam:           filledTeam,
 		RemainingTeam:        remainingTeam,
 		StartDate:            startDateStr,
 		EndDate:              endDateStr,
 		Location:             project.Location,
+		City:                 project.City,
+		Region:               project.Region,
+		Latitude:             project.Latitude,
+		Longitude:            project.Longitude,
+		LocationMode:         project.LocationMode,
 		Budget:               project.Budget,
+		BudgetMin:            project.BudgetMin,
+		BudgetMax:            project.BudgetMax,
+		BudgetCurrency:       project.BudgetCurrency,
+		IsPaid:               project.IsPaid,
 		RegistrationDeadline: registrationDeadlineStr,
 		TimeCommitment:       project.TimeCommitment,
+		HoursPerWeek:         project.HoursPerWeek,
 		Benefits:             project.Benefits,
-		Timeline:             project.Timeline,
+		Milestones:           project.Milestones,
+		Progress:             calculateProjectProgress(project.Milestones),
 		RequiredSkills:       project.RequiredSkills,
 		Conditions:           project.Conditions,
 		Tags:                 project.Tags,
 		Members:              memberResponses,
 		Roles:                project.Roles,
+		Questions:            project.Questions,
 		CreatedAt:            createdAtStr,
 		UpdatedAt:            updatedAtStr,
 	}
@@ -233,7 +270,8 @@ func (s *ProjectService) loadProjectWithRelationships(projectID uint) (*model.Pr
 		Preload("Members.MemberSkills.Skill").
 		Preload("Tags.Tag").
 		Preload("Benefits.Benefit").
-		Preload("Timeline.Timeline").
+		Preload("Milestones", orderMilestones).
+		Preload("Milestones.Dependencies").
 		First(&project, projectID).Error; err != nil {
 		return nil, err
 	}
@@ -257,7 +295,7 @@ func (s *ProjectService) CreateProjectStage1(userID uint, title, projectType, de
 		ProjectType:     projectType,
 		Description:     description,
 		PictureURL:      pictureURL,
-		Status:          "draft",
+		Status:          model.ProjectStatusDraft,
 		CompletionStage: 1,
 	}
 	if err := s.DB.Create(&project).Error; err != nil {
@@ -266,7 +304,7 @@ func (s *ProjectService) CreateProjectStage1(userID uint, title, projectType, de
 	return &project, nil
 }
 
-func (s *ProjectService) CreateProjectStage2(ProjectID, userID uint, details model.Project) (*model.Project, error) {
+func (s *ProjectService) CreateProjectStage2(ProjectID, userID uint, details model.Project, terms ProjectTermsInput, location GeoLocationInput) (*model.Project, error) {
 	tx := s.DB.Begin()
 	project, err := s.getProjectForUpdate(tx, ProjectID, userID, 1)
 	if err != nil {
@@ -274,30 +312,59 @@ func (s *ProjectService) CreateProjectStage2(ProjectID, userID uint, details mod
 		return nil, err
 	}
 
-	project.Duration = details.Duration
+	before, err := s.changeService.Snapshot(tx, &project, 2)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	project.TotalTeam = details.TotalTeam
 	project.StartDate = details.StartDate
 	project.EndDate = details.EndDate
 	project.Location = details.Location
-	project.Budget = details.Budget
 	project.RegistrationDeadline = details.RegistrationDeadline
 	project.CompletionStage = 2
 
+	if err := applyProjectDuration(&project, details.Duration, terms.DurationDays); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := applyProjectBudget(&project, details.Budget, terms); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if project.GeoLocation, err = ResolveGeoLocation(project.Location, location); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Save(&project).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+
+	if err := s.changeService.RecordChanges(tx, project.ID, userID, 2, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	return &project, tx.Commit().Error
 }
 
-func (s *ProjectService) UpdateStage3(projectID, userID uint, timeCommitment string, skillNames []string, conditionDescriptions []string) (interface{}, error) {
+func (s *ProjectService) UpdateStage3(projectID, userID uint, timeCommitment string, hoursPerWeek *int, skillNames []string, conditionDescriptions []string) (interface{}, error) {
 	tx := s.DB.Begin()
 	project, err := s.getProjectForUpdate(tx, projectID, userID, 2)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
-	if timeCommitment == "" {
+
+	before, err := s.changeService.Snapshot(tx, &project, 3)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if timeCommitment == "" && hoursPerWeek == nil {
 		tx.Rollback()
 		return nil, errors.New("Time commitment are required for stage 3")
 	}
@@ -306,36 +373,19 @@ func (s *ProjectService) UpdateStage3(projectID, userID uint, timeCommitment str
 		return nil, errors.New("skill are required for stage 3")
 	}
 
-	project.TimeCommitment = timeCommitment
-
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRequiredSkill{}).Error; err != nil {
+	if err := applyProjectHours(&project, timeCommitment, hoursPerWeek); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	for _, skillName := range skillNames {
-		skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
-		projectSkill := model.ProjectRequiredSkill{
-			ProjectID: projectID,
-			SkillID:   skill.ID,
-		}
-		if err := tx.Create(&projectSkill).Error; err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	if err := s.setRequiredSkills(tx, projectID, skillNames); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	tx.Where("project_id = ?", projectID).Delete(&model.ProjectCondition{})
-	for _, desc := range conditionDescriptions {
-		condition := model.ProjectCondition{ProjectID: project.ID, Description: desc}
-		if err := tx.Create(&condition).Error; err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	if err := s.setConditions(tx, projectID, conditionDescriptions); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
 	project.CompletionStage = 3
@@ -344,6 +394,11 @@ func (s *ProjectService) UpdateStage3(projectID, userID uint, timeCommitment str
 		return nil, err
 	}
 
+	if err := s.changeService.RecordChanges(tx, project.ID, userID, 3, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -364,6 +419,12 @@ func (s *ProjectService) UpdateStage4(projectID, userID uint, roles []RoleDTO, m
 		return nil, err
 	}
 
+	before, err := s.changeService.Snapshot(tx, &project, 4)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Validate team capacity
 	totalMembers := len(members)
 	totalRoleSlots := 0
@@ -402,35 +463,12 @@ func (s *ProjectService) UpdateStage4(projectID, userID uint, roles []RoleDTO, m
 	roleMap := make(map[string]uint)
 
 	for _, roleData := range roles {
-		role := model.ProjectRole{
-			ProjectID:      project.ID,
-			Name:           roleData.Name,
-			SlotsAvailable: roleData.SlotsAvailable,
-			Description:    roleData.Description,
-		}
-		if err := tx.Create(&role).Error; err != nil {
+		role, err := s.createRole(tx, project.ID, roleData)
+		if err != nil {
 			tx.Rollback()
 			return nil, err
 		}
 		roleMap[role.Name] = role.ID
-
-		if len(roleData.SkillNames) > 0 {
-			for _, skillName := range roleData.SkillNames {
-				skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
-				if err != nil {
-					tx.Rollback()
-					return nil, err
-				}
-				roleSkill := model.ProjectRoleSkill{
-					ProjectRoleID: role.ID,
-					SkillID:       skill.ID,
-				}
-				if err := tx.Create(&roleSkill).Error; err != nil {
-					tx.Rollback()
-					return nil, err
-				}
-			}
-		}
 	}
 
 	for _, memberData := range members {
@@ -480,6 +518,11 @@ func (s *ProjectService) UpdateStage4(projectID, userID uint, roles []RoleDTO, m
 		tx.Rollback()
 		return nil, err
 	}
+
+	if err := s.changeService.RecordChanges(tx, project.ID, userID, 4, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -525,7 +568,7 @@ func (s *ProjectService) GetProjectTeamCapacity(projectID, userID uint) (map[str
 	}, nil
 }
 
-func (s *ProjectService) UpdateStage5(projectID, userID uint, benefitNames []string, timelineData []TimelineDTO, tagNames []string) (interface{}, error) {
+func (s *ProjectService) UpdateStage5(projectID, userID uint, benefitNames []string, milestoneData []MilestoneDTO, tagNames []string) (interface{}, error) {
 	tx := s.DB.Begin()
 	project, err := s.getProjectForUpdate(tx, projectID, userID, 4)
 	if err != nil {
@@ -533,126 +576,184 @@ func (s *ProjectService) UpdateStage5(projectID, userID uint, benefitNames []str
 		return nil, err
 	}
 
+	before, err := s.changeService.Snapshot(tx, &project, 5)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if len(benefitNames) == 0 {
 		tx.Rollback()
 		return nil, errors.New("at least one benefit is required")
 	}
 
 	project.CompletionStage = 5
-	project.Status = "published"
 
-	if len(benefitNames) > 0 {
-		benefits, err := s.benefitService.findOrCreate(tx, benefitNames)
-		if err != nil {
+	if err := s.setProjectBenefits(tx, project.ID, benefitNames); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(milestoneData) > 0 {
+		if err := replaceProjectMilestones(tx, project.ID, milestoneData); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
 
-		if err := tx.Where("project_id = ?", project.ID).Delete(&model.ProjectBenefit{}).Error; err != nil {
+	if len(tagNames) > 0 {
+		if err := s.setProjectTags(tx, project.ID, tagNames); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
 
-		for _, benefit := range benefits {
-			projectBenefit := &model.ProjectBenefit{
-				ProjectID: project.ID,
-				BenefitID: benefit.ID,
-			}
-			if err := tx.Create(projectBenefit).Error; err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
+	if err := tx.Save(&project).Error; err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	if len(timelineData) > 0 {
-		var timelineNames []string
-		for _, timeline := range timelineData {
-			timelineNames = append(timelineNames, timeline.Name)
-		}
+	if err := s.changeService.RecordChanges(tx, project.ID, userID, 5, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-		timelines, err := s.timelineService.findOrCreate(tx, timelineNames)
+	// Finishing the wizard publishes a draft once its checklist passes; until then it stays a
+	// draft. Later edits leave the lifecycle status alone.
+	if project.Status == model.ProjectStatusDraft {
+		checklist, err := BuildProjectChecklist(tx, project.ID)
 		if err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+		if checklist.Publishable {
+			if err := s.lifecycleService.applyTransition(tx, &project, model.ProjectStatusPublished, userID, ""); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
 
-		if err := tx.Where("project_id = ?", project.ID).Delete(&model.ProjectTimeline{}).Error; err != nil {
+	if len(milestoneData) > 0 {
+		if err := s.webhookService.Dispatch(tx, project.ID, model.WebhookEventTimelineUpdated, map[string]interface{}{
+			"action":   "replaced",
+			"timeline": milestoneData,
+		}); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
 
-		timelineMap := make(map[string]*model.Timeline)
-		for _, timeline := range timelines {
-			timelineMap[timeline.Name] = timeline
-		}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
 
-		for _, timelineDTO := range timelineData {
-			if timelineDTO.Status == "" {
-				timelineDTO.Status = helper.GetDefaultTimelineStatus()
-			}
-			if !helper.IsValidTimelineStatus(timelineDTO.Status) {
-				validStatuses := helper.GetValidTimelineStatuses()
-				tx.Rollback()
-				return nil, errors.New("invalid timeline status: " + timelineDTO.Status + ". Must be one of: " + strings.Join(validStatuses, ", "))
-			}
+	projectResult, err := s.loadProjectWithRelationships(project.ID)
+	if err != nil {
+		return nil, err
+	}
 
-			timeline, exists := timelineMap[timelineDTO.Name]
-			if !exists {
-				tx.Rollback()
-				return nil, errors.New("timeline not found: " + timelineDTO.Name)
-			}
+	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
+}
 
-			projectTimeline := &model.ProjectTimeline{
-				ProjectID:      project.ID,
-				TimelineID:     timeline.ID,
-				TimelineStatus: timelineDTO.Status,
-			}
-			if err := tx.Create(projectTimeline).Error; err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
+// setRequiredSkills replaces the required skills of a project
+func (s *ProjectService) setRequiredSkills(tx *gorm.DB, projectID uint, skillNames []string) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRequiredSkill{}).Error; err != nil {
+		return err
 	}
 
-	if len(tagNames) > 0 {
-		tags, err := s.tagService.findOrCreate(tx, tagNames)
+	for _, skillName := range skillNames {
+		skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
 		if err != nil {
-			tx.Rollback()
-			return nil, err
+			return err
 		}
-
-		if err := tx.Where("project_id = ?", project.ID).Delete(&model.ProjectTag{}).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+		projectSkill := model.ProjectRequiredSkill{
+			ProjectID: projectID,
+			SkillID:   skill.ID,
+		}
+		if err := tx.Create(&projectSkill).Error; err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		for _, tag := range tags {
-			projectTag := &model.ProjectTag{
-				ProjectID: project.ID,
-				TagID:     tag.ID,
-			}
-			if err := tx.Create(projectTag).Error; err != nil {
-				tx.Rollback()
-				return nil, err
-			}
+// setConditions replaces the conditions of a project
+func (s *ProjectService) setConditions(tx *gorm.DB, projectID uint, conditionDescriptions []string) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectCondition{}).Error; err != nil {
+		return err
+	}
+
+	for _, desc := range conditionDescriptions {
+		condition := model.ProjectCondition{ProjectID: projectID, Description: desc}
+		if err := tx.Create(&condition).Error; err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	if err := tx.Save(&project).Error; err != nil {
-		tx.Rollback()
+// createRole creates a project role together with its required skills
+func (s *ProjectService) createRole(tx *gorm.DB, projectID uint, roleData RoleDTO) (*model.ProjectRole, error) {
+	role := &model.ProjectRole{
+		ProjectID:      projectID,
+		Name:           roleData.Name,
+		SlotsAvailable: roleData.SlotsAvailable,
+		Description:    roleData.Description,
+	}
+	if err := tx.Create(role).Error; err != nil {
 		return nil, err
 	}
-	if err := tx.Commit().Error; err != nil {
+	if err := s.setRoleSkills(tx, role.ID, roleData.SkillNames); err != nil {
 		return nil, err
 	}
+	return role, nil
+}
+
+// setProjectBenefits replaces the benefits of a project
+func (s *ProjectService) setProjectBenefits(tx *gorm.DB, projectID uint, benefitNames []string) error {
+	benefits, err := s.benefitService.findOrCreate(tx, benefitNames)
+	if err != nil {
+		return err
+	}
 
-	projectResult, err := s.loadProjectWithRelationships(project.ID)
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectBenefit{}).Error; err != nil {
+		return err
+	}
+
+	for _, benefit := range benefits {
+		projectBenefit := &model.ProjectBenefit{
+			ProjectID: projectID,
+			BenefitID: benefit.ID,
+		}
+		if err := tx.Create(projectBenefit).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setProjectTags replaces the tags of a project
+func (s *ProjectService) setProjectTags(tx *gorm.DB, projectID uint, tagNames []string) error {
+	tags, err := s.tagService.findOrCreate(tx, tagNames)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectTag{}).Error; err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		projectTag := &model.ProjectTag{
+			ProjectID: projectID,
+			TagID:     tag.ID,
+		}
+		if err := tx.Create(projectTag).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *ProjectService) CreateRolesOnly(projectID, userID uint, roles []RoleDTO) (interface{}, error) {
@@ -672,34 +773,10 @@ func (s *ProjectService) CreateRolesOnly(projectID, userID uint, roles []RoleDTO
 	tx.Where("project_id = ?", projectID).Delete(&model.ProjectRole{})
 
 	for _, roleData := range roles {
-		role := model.ProjectRole{
-			ProjectID:      project.ID,
-			Name:           roleData.Name,
-			SlotsAvailable: roleData.SlotsAvailable,
-			Description:    roleData.Description,
-		}
-		if err := tx.Create(&role).Error; err != nil {
+		if _, err := s.createRole(tx, project.ID, roleData); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
-
-		if len(roleData.SkillNames) > 0 {
-			for _, skillName := range roleData.SkillNames {
-				skill, err := s.skillService.FindOrCreateWithTx(tx, skillName)
-				if err != nil {
-					tx.Rollback()
-					return nil, err
-				}
-				roleSkill := model.ProjectRoleSkill{
-					ProjectRoleID: role.ID,
-					SkillID:       skill.ID,
-				}
-				if err := tx.Create(&roleSkill).Error; err != nil {
-					tx.Rollback()
-					return nil, err
-				}
-			}
-		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -814,8 +891,9 @@ func (s *ProjectService) GetUserProjects(userID uint) ([]interface{}, error) {
 		Preload("Members.MemberSkills.Skill").
 		Preload("Tags.Tag").
 		Preload("Benefits.Benefit").
-		Preload("Timeline.Timeline").
-		Where("creator_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ?)", userID, userID).
+		Preload("Milestones", orderMilestones).
+		Preload("Milestones.Dependencies").
+		Where("creator_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ?) OR "+projectAccessCondition, userID, userID, userID).
 		Find(&projects).Error
 
 	if err != nil {
@@ -861,7 +939,8 @@ func (s *ProjectService) GetMyCreatedProjects(userID uint) ([]interface{}, error
 		Preload("Members.MemberSkills.Skill").
 		Preload("Tags.Tag").
 		Preload("Benefits.Benefit").
-		Preload("Timeline.Timeline").
+		Preload("Milestones", orderMilestones).
+		Preload("Milestones.Dependencies").
 		Where("creator_id = ?", userID).
 		Find(&projects).Error
 
@@ -908,7 +987,8 @@ func (s *ProjectService) GetMyMemberProjects(userID uint) ([]interface{}, error)
 		Preload("Members.MemberSkills.Skill").
 		Preload("Tags.Tag").
 		Preload("Benefits.Benefit").
-		Preload("Timeline.Timeline").
+		Preload("Milestones", orderMilestones).
+		Preload("Milestones.Dependencies").
 		Where("id IN (SELECT project_id FROM project_members WHERE user_id = ?)", userID).
 		Find(&projects).Error
 
@@ -955,6 +1035,8 @@ func (s *ProjectService) GetUserProject(userID, projectID uint) (interface{}, er
 
 	if project.CreatorID == userID {
 		hasAccess = true
+	} else if allowed, _ := HasProjectPermission(s.DB, &project, userID, model.ProjectPermissionReviewApplications); allowed {
+		hasAccess = true
 	} else {
 		var memberCount int64
 		s.DB.Model(&model.ProjectMember{}).
@@ -974,13 +1056,18 @@ func (s *ProjectService) GetUserProject(userID, projectID uint) (interface{}, er
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
 
+	if projectResult.Questions, err = loadPublicQuestions(s.DB, projectID); err != nil {
+		return nil, err
+	}
+
 	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
 }
 
-func (s *ProjectService) GetAllProjects() ([]interface{}, error) {
+// GetAllProjects lists the public projects matching the filters, nearest first in radius searches
+func (s *ProjectService) GetAllProjects(filter ProjectTermsFilter, geo GeoFilter) ([]interface{}, error) {
 	var projects []model.Project
 
-	err := s.DB.Preload("Creator").
+	err := geo.Apply(filter.Apply(s.DB), "projects").Preload("Creator").
 		Preload("RequiredSkills.Skill").
 		Preload("Conditions").
 		Preload("Roles.RequiredSkills.Skill").
@@ -989,8 +1076,9 @@ func (s *ProjectService) GetAllProjects() ([]interface{}, error) {
 		Preload("Members.MemberSkills.Skill").
 		Preload("Tags.Tag").
 		Preload("Benefits.Benefit").
-		Preload("Timeline.Timeline").
-		Where("status != ?", "draft").
+		Preload("Milestones", orderMilestones).
+		Preload("Milestones.Dependencies").
+		Where("status IN ?", ProjectPublicStatuses).
 		Find(&projects).Error
 
 	if err != nil {
@@ -1017,129 +1105,65 @@ func (s *ProjectService) GetAllProjects() ([]interface{}, error) {
 	var responses []interface{}
 	for _, project := range projects {
 		profile, exists := profileMap[project.CreatorID]
-		response := s.transformProjectToResponseWithProfile(&project, profile, exists)
+		response := s.transformProjectToResponseWithProfile(&project, profile, exists).(ProjectResponseForMarshal)
+		response.DistanceKm = geo.DistanceKm(project.GeoLocation)
 		responses = append(responses, response)
 	}
 
 	return responses, nil
 }
 
-// GetProjectByID retrieves a single project by ID without authentication (public access)
-func (s *ProjectService) GetProjectByID(projectID uint) (interface{}, error) {
+// GetProjectByID retrieves a single project by ID without authentication (public access) and
+// counts the view for the project's analytics
+func (s *ProjectService) GetProjectByID(projectID uint, viewer ProjectViewer) (interface{}, error) {
 	var project model.Project
 
-	err := s.DB.Where("id = ? AND status != ?", projectID, "draft").First(&project).Error
+	err := s.DB.Where("id = ? AND status NOT IN ?", projectID, []string{model.ProjectStatusDraft, model.ProjectStatusArchived}).First(&project).Error
 	if err != nil {
 		return nil, fmt.Errorf("project not found")
 	}
 
+	// A failure to count the view shouldn't keep the project from being shown
+	if err := s.analyticsService.RecordView(&project, viewer); err != nil {
+		log.Printf("Failed to record view of project %d: %v", project.ID, err)
+	}
+
 	projectResult, err := s.loadProjectWithRelationships(projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
 
+	if projectResult.Questions, err = loadPublicQuestions(s.DB, projectID); err != nil {
+		return nil, err
+	}
+
 	return s.transformProjectToResponseWithSingleProfile(projectResult), nil
 }
 
-// DeleteProject deletes a project by ID, only if the user is the creator
+// DeleteProject moves a project to the trash, only if the user is an owner.
+// The project and everything attached to it stay hidden until it is restored or purged.
 func (s *ProjectService) DeleteProject(projectID, userID uint) error {
-	tx := s.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// First check if project exists and user is authorized
 	var project model.Project
-	if err := tx.First(&project, projectID).Error; err != nil {
-		tx.Rollback()
+	if err := s.DB.First(&project, projectID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("project not found")
 		}
 		return fmt.Errorf("failed to find project: %w", err)
 	}
 
-	// Check if user is the creator (only owner can delete)
-	if project.CreatorID != userID {
-		tx.Rollback()
-		return errors.New("only the project owner can delete this project")
-	}
-
-	// Delete related records in proper order to respect foreign key constraints
-
-	// 1. First delete project member skills (references project_members)
-	if err := tx.Where("project_member_id IN (SELECT id FROM project_members WHERE project_id = ?)", projectID).Delete(&model.ProjectMemberSkill{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project member skills: %w", err)
-	}
-
-	// 2. Delete project applications (references project_roles) - This is crucial!
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectApplication{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project applications: %w", err)
-	}
-
-	// 3. Delete project members (references project_roles)
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectMember{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project members: %w", err)
-	}
-
-	// 4. Delete project role skills (references project_roles)
-	if err := tx.Where("project_role_id IN (SELECT id FROM project_roles WHERE project_id = ?)", projectID).Delete(&model.ProjectRoleSkill{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project role skills: %w", err)
-	}
-
-	// 5. Now we can safely delete project roles
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRole{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project roles: %w", err)
-	}
-
-	// 6. Delete other project-related records
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectBenefit{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project benefits: %w", err)
-	}
-
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectTimeline{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project timeline: %w", err)
-	}
-
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRequiredSkill{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project required skills: %w", err)
-	}
-
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectCondition{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project conditions: %w", err)
-	}
-
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectTag{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project tags: %w", err)
+	// Only owners can delete
+	allowed, err := HasProjectPermission(s.DB, &project, userID, model.ProjectPermissionDelete)
+	if err != nil {
+		return err
 	}
-
-	// Delete notifications that reference this project
-	if err := tx.Where("project_id = ?", projectID).Delete(&model.Notification{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete project notifications: %w", err)
+	if !allowed {
+		return errors.New("only the project owner can delete this project")
 	}
 
-	// Finally delete the project itself
-	if err := tx.Delete(&project).Error; err != nil {
-		tx.Rollback()
+	if err := s.DB.Delete(&project).Error; err != nil {
 		return fmt.Errorf("failed to delete project: %w", err)
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return nil
 }
 
@@ -0,0 +1,615 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/model"
+)
+
+// TaskStatuses lists the task board columns in order
+var TaskStatuses = []string{
+	model.TaskStatusTodo,
+	model.TaskStatusInProgress,
+	model.TaskStatusInReview,
+	model.TaskStatusDone,
+}
+
+type ProjectTaskService struct {
+	DB            *gorm.DB
+	OutboxService *OutboxService
+}
+
+func NewProjectTaskService(db *gorm.DB, outboxService *OutboxService) *ProjectTaskService {
+	return &ProjectTaskService{
+		DB:            db,
+		OutboxService: outboxService,
+	}
+}
+
+// TaskDTO contains the editable fields of a task; nil fields are left unchanged.
+// An AssigneeID or MilestoneID of 0 clears the assignee or milestone.
+type TaskDTO struct {
+	Title        *string
+	Description  *string
+	Status       *string
+	Position     *int
+	Labels       []string
+	AssigneeID   *uint
+	MilestoneID  *uint
+	DueDate      *time.Time
+	ClearDueDate bool
+}
+
+// TaskFilter narrows the tasks of a board; zero values match everything
+type TaskFilter struct {
+	Status      string
+	AssigneeID  *uint
+	MilestoneID *uint
+	Label       string
+}
+
+// TaskBoardColumn is one status column of the task board
+type TaskBoardColumn struct {
+	Status string              `json:"status"`
+	Tasks  []model.ProjectTask `json:"tasks"`
+}
+
+func isValidTaskStatus(status string) bool {
+	for _, valid := range TaskStatuses {
+		if valid == status {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTaskLabels trims, lowercases and de-duplicates labels into a comma-separated list
+func normalizeTaskLabels(labels []string) string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		unique = append(unique, label)
+	}
+	return strings.Join(unique, ",")
+}
+
+// loadTaskProject loads a project the user takes part in; write access also requires it not to be archived
+func (s *ProjectTaskService) loadTaskProject(tx *gorm.DB, projectID, userID uint, write bool) (*model.Project, error) {
+	var project model.Project
+	if err := tx.First(&project, projectID).Error; err != nil {
+		return nil, errors.New("project not found")
+	}
+	if !CanViewProject(tx, &project, userID) {
+		return nil, errors.New("you are not a member of this project")
+	}
+	if write && project.Status == model.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+	return &project, nil
+}
+
+func (s *ProjectTaskService) getTask(tx *gorm.DB, projectID, taskID uint) (*model.ProjectTask, error) {
+	var task model.ProjectTask
+	if err := tx.Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error; err != nil {
+		return nil, errors.New("task not found")
+	}
+	return &task, nil
+}
+
+// validateAssignee checks that a task can be assigned to userID: the owner or an accepted member
+func validateAssignee(tx *gorm.DB, project *model.Project, userID uint) error {
+	if userID == project.CreatorID {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&model.ProjectMember{}).
+		Where("project_id = ? AND user_id = ? AND status = ?", project.ID, userID, "accepted").
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check assignee: %v", err)
+	}
+	if count == 0 {
+		return errors.New("tasks can only be assigned to project members")
+	}
+	return nil
+}
+
+func nextTaskPosition(tx *gorm.DB, projectID uint, status string) (int, error) {
+	var maxPosition *int
+	if err := tx.Model(&model.ProjectTask{}).
+		Where("project_id = ? AND status = ?", projectID, status).
+		Select("MAX(position)").
+		Scan(&maxPosition).Error; err != nil {
+		return 0, fmt.Errorf("failed to get task position: %v", err)
+	}
+	if maxPosition == nil {
+		return 0, nil
+	}
+	return *maxPosition + 1, nil
+}
+
+// taskFollowers returns the creator and assignee of a task
+func taskFollowers(task *model.ProjectTask) []uint {
+	followers := []uint{task.CreatedBy}
+	if task.AssigneeID != nil && *task.AssigneeID != task.CreatedBy {
+		followers = append(followers, *task.AssigneeID)
+	}
+	return followers
+}
+
+func (s *ProjectTaskService) loadTask(taskID uint) (*model.ProjectTask, error) {
+	var task model.ProjectTask
+	if err := s.DB.Preload("Assignee").
+		Preload("Creator").
+		Preload("Milestone").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Comments.User").
+		First(&task, taskID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load task: %v", err)
+	}
+	return &task, nil
+}
+
+// CreateTask adds a task to the board of a project
+func (s *ProjectTaskService) CreateTask(projectID, userID uint, data TaskDTO) (*model.ProjectTask, error) {
+	if data.Title == nil || strings.TrimSpace(*data.Title) == "" {
+		return nil, errors.New("title is required")
+	}
+
+	status := model.TaskStatusTodo
+	if data.Status != nil {
+		status = *data.Status
+	}
+	if !isValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status: %s. Must be one of: %s", status, strings.Join(TaskStatuses, ", "))
+	}
+
+	tx := s.DB.Begin()
+	project, err := s.loadTaskProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	task := &model.ProjectTask{
+		ProjectID: projectID,
+		Title:     strings.TrimSpace(*data.Title),
+		Status:    status,
+		Labels:    normalizeTaskLabels(data.Labels),
+		CreatedBy: userID,
+		DueDate:   data.DueDate,
+	}
+	if data.Description != nil {
+		task.Description = *data.Description
+	}
+	if data.AssigneeID != nil && *data.AssigneeID != 0 {
+		if err := validateAssignee(tx, project, *data.AssigneeID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		task.AssigneeID = data.AssigneeID
+	}
+	if data.MilestoneID != nil && *data.MilestoneID != 0 {
+		if err := validateTaskMilestone(tx, projectID, *data.MilestoneID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		task.MilestoneID = data.MilestoneID
+	}
+	if data.Position != nil {
+		task.Position = *data.Position
+	} else if task.Position, err = nextTaskPosition(tx, projectID, status); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if status == model.TaskStatusDone {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+
+	if err := tx.Create(task).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create task: %v", err)
+	}
+
+	if err := rollUpMilestone(tx, task.MilestoneID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordActivity(tx, projectID, userID, model.ActivityTypeTaskCreated,
+		fmt.Sprintf("Created task '%s'", task.Title),
+		map[string]interface{}{"task_id": task.ID, "status": task.Status}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if task.AssigneeID != nil && *task.AssigneeID != userID {
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyTaskAssigned, NotificationPayload{
+			ProjectID:  projectID,
+			UserID:     userID,
+			TaskID:     task.ID,
+			Recipients: []uint{*task.AssigneeID},
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to create task: %v", err)
+	}
+
+	return s.loadTask(task.ID)
+}
+
+// GetTasks lists the tasks of a project matching filter, in board order
+func (s *ProjectTaskService) GetTasks(projectID, userID uint, filter TaskFilter) ([]model.ProjectTask, error) {
+	if _, err := s.loadTaskProject(s.DB, projectID, userID, false); err != nil {
+		return nil, err
+	}
+
+	query := s.DB.Preload("Assignee").Preload("Milestone").Where("project_id = ?", projectID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.MilestoneID != nil {
+		query = query.Where("milestone_id = ?", *filter.MilestoneID)
+	}
+	if filter.Label != "" {
+		query = query.Where("',' || labels || ',' LIKE ?", "%,"+strings.ToLower(filter.Label)+",%")
+	}
+
+	var tasks []model.ProjectTask
+	if err := query.Order("position ASC, id ASC").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %v", err)
+	}
+	return tasks, nil
+}
+
+// GetBoard returns the tasks of a project grouped into status columns
+func (s *ProjectTaskService) GetBoard(projectID, userID uint, filter TaskFilter) ([]TaskBoardColumn, error) {
+	filter.Status = ""
+	tasks, err := s.GetTasks(projectID, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]TaskBoardColumn, len(TaskStatuses))
+	index := make(map[string]int, len(TaskStatuses))
+	for i, status := range TaskStatuses {
+		columns[i] = TaskBoardColumn{Status: status, Tasks: []model.ProjectTask{}}
+		index[status] = i
+	}
+	for _, task := range tasks {
+		i := index[task.Status]
+		columns[i].Tasks = append(columns[i].Tasks, task)
+	}
+	return columns, nil
+}
+
+// GetTask retrieves a task with its comments
+func (s *ProjectTaskService) GetTask(projectID, taskID, userID uint) (*model.ProjectTask, error) {
+	if _, err := s.loadTaskProject(s.DB, projectID, userID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTask(s.DB, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.loadTask(taskID)
+}
+
+// UpdateTask changes a task; status changes roll up to the attached milestone
+func (s *ProjectTaskService) UpdateTask(projectID, taskID, userID uint, data TaskDTO) (*model.ProjectTask, error) {
+	tx := s.DB.Begin()
+	project, err := s.loadTaskProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	task, err := s.getTask(tx, projectID, taskID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	previousStatus := task.Status
+	previousMilestoneID := task.MilestoneID
+	previousAssigneeID := task.AssigneeID
+
+	if data.Title != nil {
+		if strings.TrimSpace(*data.Title) == "" {
+			tx.Rollback()
+			return nil, errors.New("title is required")
+		}
+		task.Title = strings.TrimSpace(*data.Title)
+	}
+	if data.Description != nil {
+		task.Description = *data.Description
+	}
+	if data.Labels != nil {
+		task.Labels = normalizeTaskLabels(data.Labels)
+	}
+	if data.DueDate != nil {
+		task.DueDate = data.DueDate
+	} else if data.ClearDueDate {
+		task.DueDate = nil
+	}
+	if data.AssigneeID != nil {
+		if *data.AssigneeID == 0 {
+			task.AssigneeID = nil
+		} else {
+			if err := validateAssignee(tx, project, *data.AssigneeID); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			task.AssigneeID = data.AssigneeID
+		}
+	}
+	if data.MilestoneID != nil {
+		if *data.MilestoneID == 0 {
+			task.MilestoneID = nil
+		} else {
+			if err := validateTaskMilestone(tx, projectID, *data.MilestoneID); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			task.MilestoneID = data.MilestoneID
+		}
+	}
+	if data.Status != nil && *data.Status != task.Status {
+		if !isValidTaskStatus(*data.Status) {
+			tx.Rollback()
+			return nil, fmt.Errorf("invalid task status: %s. Must be one of: %s", *data.Status, strings.Join(TaskStatuses, ", "))
+		}
+		task.Status = *data.Status
+		if task.Status == model.TaskStatusDone {
+			now := time.Now()
+			task.CompletedAt = &now
+		} else {
+			task.CompletedAt = nil
+		}
+		// Moved cards go to the bottom of their new column unless placed explicitly
+		if data.Position == nil {
+			if task.Position, err = nextTaskPosition(tx, projectID, task.Status); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+	if data.Position != nil {
+		task.Position = *data.Position
+	}
+
+	if err := tx.Save(task).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update task: %v", err)
+	}
+
+	if err := rollUpMilestone(tx, task.MilestoneID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if previousMilestoneID != nil && (task.MilestoneID == nil || *previousMilestoneID != *task.MilestoneID) {
+		if err := rollUpMilestone(tx, previousMilestoneID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	assigneeChanged := task.AssigneeID != nil && (previousAssigneeID == nil || *previousAssigneeID != *task.AssigneeID)
+	statusChanged := task.Status != previousStatus
+
+	activityType := model.ActivityTypeTaskUpdated
+	message := fmt.Sprintf("Updated task '%s'", task.Title)
+	activityData := map[string]interface{}{"task_id": task.ID}
+	if statusChanged {
+		activityType = model.ActivityTypeTaskStatusChanged
+		message = fmt.Sprintf("Moved task '%s' from %s to %s", task.Title,
+			strings.ReplaceAll(previousStatus, "_", " "), strings.ReplaceAll(task.Status, "_", " "))
+		activityData["previous_status"] = previousStatus
+		activityData["status"] = task.Status
+	} else if assigneeChanged {
+		activityType = model.ActivityTypeTaskAssigned
+		message = fmt.Sprintf("Assigned task '%s'", task.Title)
+		activityData["assignee_id"] = *task.AssigneeID
+	}
+	if err := recordActivity(tx, projectID, userID, activityType, message, activityData); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if assigneeChanged && *task.AssigneeID != userID {
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyTaskAssigned, NotificationPayload{
+			ProjectID:  projectID,
+			UserID:     userID,
+			TaskID:     task.ID,
+			Recipients: []uint{*task.AssigneeID},
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if statusChanged {
+		if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyTaskUpdated, NotificationPayload{
+			ProjectID:      projectID,
+			UserID:         userID,
+			TaskID:         task.ID,
+			Status:         task.Status,
+			PreviousStatus: previousStatus,
+			Recipients:     taskFollowers(task),
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to update task: %v", err)
+	}
+
+	return s.loadTask(task.ID)
+}
+
+// DeleteTask removes a task and its comments; allowed for its creator and users who can edit the project
+func (s *ProjectTaskService) DeleteTask(projectID, taskID, userID uint) error {
+	tx := s.DB.Begin()
+	project, err := s.loadTaskProject(tx, projectID, userID, true)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	task, err := s.getTask(tx, projectID, taskID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if task.CreatedBy != userID {
+		allowed, err := HasProjectPermission(tx, project, userID, model.ProjectPermissionEdit)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if !allowed {
+			tx.Rollback()
+			return errors.New("only the task creator or project managers can delete this task")
+		}
+	}
+
+	if err := tx.Where("task_id = ?", task.ID).Delete(&model.ProjectTaskComment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete task comments: %v", err)
+	}
+
+	if err := tx.Delete(task).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete task: %v", err)
+	}
+
+	if err := rollUpMilestone(tx, task.MilestoneID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordActivity(tx, projectID, userID, model.ActivityTypeTaskDeleted,
+		fmt.Sprintf("Deleted task '%s'", task.Title),
+		map[string]interface{}{"task_id": task.ID}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AddComment comments on a task and notifies its creator, assignee and earlier commenters
+func (s *ProjectTaskService) AddComment(projectID, taskID, userID uint, body string) (*model.ProjectTaskComment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+
+	tx := s.DB.Begin()
+	if _, err := s.loadTaskProject(tx, projectID, userID, true); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	task, err := s.getTask(tx, projectID, taskID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var commenterIDs []uint
+	if err := tx.Model(&model.ProjectTaskComment{}).
+		Where("task_id = ?", task.ID).
+		Distinct().
+		Pluck("user_id", &commenterIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to load task commenters: %v", err)
+	}
+
+	comment := &model.ProjectTaskComment{
+		TaskID: task.ID,
+		UserID: userID,
+		Body:   body,
+	}
+	if err := tx.Create(comment).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+
+	if err := recordActivity(tx, projectID, userID, model.ActivityTypeTaskCommented,
+		fmt.Sprintf("Commented on task '%s'", task.Title),
+		map[string]interface{}{"task_id": task.ID, "comment_id": comment.ID}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	recipients := taskFollowers(task)
+	seen := make(map[uint]bool)
+	for _, id := range recipients {
+		seen[id] = true
+	}
+	for _, id := range commenterIDs {
+		if !seen[id] {
+			seen[id] = true
+			recipients = append(recipients, id)
+		}
+	}
+
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicNotifyTaskCommented, NotificationPayload{
+		ProjectID:  projectID,
+		UserID:     userID,
+		TaskID:     task.ID,
+		Recipients: recipients,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+
+	if err := s.DB.Preload("User").First(comment, comment.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load comment: %v", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment; only its author can delete it
+func (s *ProjectTaskService) DeleteComment(projectID, taskID, commentID, userID uint) error {
+	if _, err := s.loadTaskProject(s.DB, projectID, userID, true); err != nil {
+		return err
+	}
+	if _, err := s.getTask(s.DB, projectID, taskID); err != nil {
+		return err
+	}
+
+	result := s.DB.Where("id = ? AND task_id = ? AND user_id = ?", commentID, taskID, userID).Delete(&model.ProjectTaskComment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete comment: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("comment not found or unauthorized")
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+type ProjectTemplateService struct {
+	DB             *gorm.DB
+	projectService *ProjectService
+}
+
+func NewProjectTemplateService(db *gorm.DB, projectService *ProjectService) *ProjectTemplateService {
+	return &ProjectTemplateService{
+		DB:             db,
+		projectService: projectService,
+	}
+}
+
+// TemplateTimelineItem is a milestone whose dates are relative to the project start
+type TemplateTimelineItem struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	StartOffsetDays *int     `json:"start_offset_days,omitempty"`
+	DueOffsetDays   *int     `json:"due_offset_days,omitempty"`
+	DependsOn       []string `json:"depends_on,omitempty"`
+}
+
+// ProjectTemplateContent is the blueprint stored in a template
+type ProjectTemplateContent struct {
+	ProjectType    string `json:"project_type"`
+	Description    string `json:"description"`
+	PictureURL     string `json:"picture_url,omitempty"`
+	Duration       string `json:"duration"`
+	TotalTeam      int    `json:"total_team"`
+	Location       string `json:"location"`
+	Budget         string `json:"budget"`
+	BudgetMin      *int64 `json:"budget_min,omitempty"`
+	BudgetMax      *int64 `json:"budget_max,omitempty"`
+	BudgetCurrency string `json:"budget_currency,omitempty"`
+	IsPaid         *bool  `json:"is_paid,omitempty"`
+	TimeCommitment string `json:"time_commitment"`
+	HoursPerWeek   *int   `json:"hours_per_week,omitempty"`
+
+	model.GeoLocation
+
+	// Dates relative to the start date
+	DurationDays         int `json:"duration_days"`
+	RegistrationLeadDays int `json:"registration_lead_days"`
+
+	RequiredSkills []string               `json:"required_skills"`
+	Conditions     []string               `json:"conditions"`
+	Roles          []RoleDTO              `json:"roles"`
+	Benefits       []string               `json:"benefits"`
+	Timeline       []TemplateTimelineItem `json:"timeline"`
+	Tags           []string               `json:"tags"`
+}
+
+// ProjectTemplateResponse is a template together with its decoded content
+type ProjectTemplateResponse struct {
+	model.ProjectTemplate
+	Content ProjectTemplateContent `json:"content"`
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// buildTemplateContent captures a project as a template blueprint
+func (s *ProjectTemplateService) buildTemplateContent(projectID uint) (*model.Project, *ProjectTemplateContent, error) {
+	project, err := s.projectService.loadProjectWithRelationships(projectID)
+	if err != nil {
+		return nil, nil, errors.New("project not found")
+	}
+
+	content := &ProjectTemplateContent{
+		ProjectType:    project.ProjectType,
+		Description:    project.Description,
+		PictureURL:     project.PictureURL,
+		Duration:       project.Duration,
+		TotalTeam:      project.TotalTeam,
+		Location:       project.Location,
+		GeoLocation:    project.GeoLocation,
+		Budget:         project.Budget,
+		BudgetMin:      project.BudgetMin,
+		BudgetMax:      project.BudgetMax,
+		BudgetCurrency: project.BudgetCurrency,
+		IsPaid:         project.IsPaid,
+		TimeCommitment: project.TimeCommitment,
+		HoursPerWeek:   project.HoursPerWeek,
+		DurationDays:   project.DurationDays,
+	}
+
+	if !project.StartDate.IsZero() {
+		if !project.EndDate.IsZero() {
+			content.DurationDays = daysBetween(project.StartDate, project.EndDate)
+		}
+		if !project.RegistrationDeadline.IsZero() {
+			content.RegistrationLeadDays = daysBetween(project.RegistrationDeadline, project.StartDate)
+		}
+	}
+
+	for _, skill := range project.RequiredSkills {
+		content.RequiredSkills = append(content.RequiredSkills, skill.Skill.Name)
+	}
+	for _, condition := range project.Conditions {
+		content.Conditions = append(content.Conditions, condition.Description)
+	}
+	for _, role := range project.Roles {
+		roleDTO := RoleDTO{
+			Name:           role.Name,
+			SlotsAvailable: role.SlotsAvailable,
+			Description:    role.Description,
+		}
+		for _, skill := range role.RequiredSkills {
+			roleDTO.SkillNames = append(roleDTO.SkillNames, skill.Skill.Name)
+		}
+		content.Roles = append(content.Roles, roleDTO)
+	}
+	for _, benefit := range project.Benefits {
+		content.Benefits = append(content.Benefits, benefit.Benefit.Name)
+	}
+	milestoneNames := make(map[uint]string, len(project.Milestones))
+	for _, item := range project.Milestones {
+		milestoneNames[item.ID] = item.Name
+	}
+	for _, item := range project.Milestones {
+		templateItem := TemplateTimelineItem{Name: item.Name, Description: item.Description}
+		if !project.StartDate.IsZero() {
+			if item.StartDate != nil {
+				offset := daysBetween(project.StartDate, *item.StartDate)
+				templateItem.StartOffsetDays = &offset
+			}
+			if item.DueDate != nil {
+				offset := daysBetween(project.StartDate, *item.DueDate)
+				templateItem.DueOffsetDays = &offset
+			}
+		}
+		for _, dependency := range item.Dependencies {
+			templateItem.DependsOn = append(templateItem.DependsOn, milestoneNames[dependency.DependsOnID])
+		}
+		content.Timeline = append(content.Timeline, templateItem)
+	}
+	for _, tag := range project.Tags {
+		content.Tags = append(content.Tags, tag.Tag.Name)
+	}
+
+	return project, content, nil
+}
+
+func toTemplateResponse(template *model.ProjectTemplate) (*ProjectTemplateResponse, error) {
+	var content ProjectTemplateContent
+	if err := json.Unmarshal([]byte(template.Content), &content); err != nil {
+		return nil, fmt.Errorf("failed to read template content: %v", err)
+	}
+	return &ProjectTemplateResponse{ProjectTemplate: *template, Content: content}, nil
+}
+
+// CreateTemplateFromProject saves one of the user's projects as a template
+func (s *ProjectTemplateService) CreateTemplateFromProject(projectID, userID uint, name, description string) (*ProjectTemplateResponse, error) {
+	project, content, err := s.buildTemplateContent(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if allowed, err := HasProjectPermission(s.DB, project, userID, model.ProjectPermissionEdit); err != nil {
+		return nil, err
+	} else if !allowed {
+		return nil, errors.New("you are not authorized to use this project as a template")
+	}
+
+	if name == "" {
+		name = project.Title
+	}
+
+	contentBytes, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save template content: %v", err)
+	}
+
+	template := &model.ProjectTemplate{
+		CreatorID:       userID,
+		SourceProjectID: &project.ID,
+		Name:            name,
+		Description:     description,
+		Content:         string(contentBytes),
+	}
+	if err := s.DB.Create(template).Error; err != nil {
+		return nil, fmt.Errorf("failed to create template: %v", err)
+	}
+
+	return &ProjectTemplateResponse{ProjectTemplate: *template, Content: *content}, nil
+}
+
+// GetTemplates lists the templates of a user
+func (s *ProjectTemplateService) GetTemplates(userID uint) ([]ProjectTemplateResponse, error) {
+	var templates []model.ProjectTemplate
+	if err := s.DB.Where("creator_id = ?", userID).Order("created_at DESC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get templates: %v", err)
+	}
+
+	responses := make([]ProjectTemplateResponse, 0, len(templates))
+	for i := range templates {
+		response, err := toTemplateResponse(&templates[i])
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+func (s *ProjectTemplateService) getTemplate(templateID, userID uint) (*model.ProjectTemplate, error) {
+	var template model.ProjectTemplate
+	if err := s.DB.Where("id = ? AND creator_id = ?", templateID, userID).First(&template).Error; err != nil {
+		return nil, errors.New("template not found")
+	}
+	return &template, nil
+}
+
+// GetTemplate retrieves a single template of a user
+func (s *ProjectTemplateService) GetTemplate(templateID, userID uint) (*ProjectTemplateResponse, error) {
+	template, err := s.getTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toTemplateResponse(template)
+}
+
+// DeleteTemplate removes a template; projects created from it are unaffected
+func (s *ProjectTemplateService) DeleteTemplate(templateID, userID uint) error {
+	template, err := s.getTemplate(templateID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.DB.Delete(template).Error; err != nil {
+		return fmt.Errorf("failed to delete template: %v", err)
+	}
+	return nil
+}
+
+// CreateProjectFromTemplate creates a new draft project from a template with dates shifted onto startDate
+func (s *ProjectTemplateService) CreateProjectFromTemplate(templateID, userID uint, title string, startDate time.Time) (interface{}, error) {
+	template, err := s.getTemplate(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := toTemplateResponse(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.instantiate(&response.Content, userID, title, startDate)
+}
+
+// DuplicateProject copies one of the user's projects into a new draft with dates shifted onto startDate
+func (s *ProjectTemplateService) DuplicateProject(projectID, userID uint, title string, startDate time.Time) (interface{}, error) {
+	project, content, err := s.buildTemplateContent(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if allowed, err := HasProjectPermission(s.DB, project, userID, model.ProjectPermissionEdit); err != nil {
+		return nil, err
+	} else if !allowed {
+		return nil, errors.New("you are not authorized to duplicate this project")
+	}
+
+	if title == "" {
+		title = project.Title + " (Copy)"
+	}
+
+	return s.instantiate(content, userID, title, startDate)
+}
+
+// instantiate creates a draft project from a blueprint. The new project is left at stage 4,
+// so the creator reviews it and publishes through stage 5 as usual.
+func (s *ProjectTemplateService) instantiate(content *ProjectTemplateContent, userID uint, title string, startDate time.Time) (interface{}, error) {
+	if title == "" {
+		return nil, errors.New("title are required")
+	}
+	if startDate.IsZero() {
+		return nil, errors.New("start date is required")
+	}
+
+	project := model.Project{
+		CreatorID:            userID,
+		Title:                title,
+		ProjectType:          content.ProjectType,
+		Description:          content.Description,
+		PictureURL:           content.PictureURL,
+		Duration:             content.Duration,
+		TotalTeam:            content.TotalTeam,
+		StartDate:            startDate,
+		EndDate:              startDate.AddDate(0, 0, content.DurationDays),
+		Location:             content.Location,
+		GeoLocation:          content.GeoLocation,
+		Budget:               content.Budget,
+		BudgetMin:            content.BudgetMin,
+		BudgetMax:            content.BudgetMax,
+		BudgetCurrency:       content.BudgetCurrency,
+		IsPaid:               content.IsPaid,
+		RegistrationDeadline: startDate.AddDate(0, 0, -content.RegistrationLeadDays),
+		TimeCommitment:       content.TimeCommitment,
+		HoursPerWeek:         content.HoursPerWeek,
+		DurationDays:         content.DurationDays,
+		Status:               model.ProjectStatusDraft,
+		CompletionStage:      4,
+	}
+	if project.BudgetCurrency == "" {
+		project.BudgetCurrency = helper.DefaultCurrency
+	}
+	// Templates saved before locations were structured only have the text
+	if project.City == "" && project.Region == "" && !project.HasCoordinates() && project.LocationMode == "" {
+		location, err := ResolveGeoLocation(project.Location, GeoLocationInput{})
+		if err != nil {
+			return nil, err
+		}
+		project.GeoLocation = location
+	}
+
+	tx := s.DB.Begin()
+	if err := tx.Create(&project).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create project: %v", err)
+	}
+
+	if err := s.projectService.setRequiredSkills(tx, project.ID, content.RequiredSkills); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.projectService.setConditions(tx, project.ID, content.Conditions); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, role := range content.Roles {
+		if _, err := s.projectService.createRole(tx, project.ID, role); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if len(content.Benefits) > 0 {
+		if err := s.projectService.setProjectBenefits(tx, project.ID, content.Benefits); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if len(content.Timeline) > 0 {
+		milestoneData := make([]MilestoneDTO, 0, len(content.Timeline))
+		for _, item := range content.Timeline {
+			milestoneDTO := MilestoneDTO{
+				Name:        item.Name,
+				Description: item.Description,
+				DependsOn:   item.DependsOn,
+			}
+			if item.StartOffsetDays != nil {
+				startsOn := startDate.AddDate(0, 0, *item.StartOffsetDays)
+				milestoneDTO.StartDate = &startsOn
+			}
+			if item.DueOffsetDays != nil {
+				dueDate := startDate.AddDate(0, 0, *item.DueOffsetDays)
+				milestoneDTO.DueDate = &dueDate
+			}
+			milestoneData = append(milestoneData, milestoneDTO)
+		}
+		if err := replaceProjectMilestones(tx, project.ID, milestoneData); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if len(content.Tags) > 0 {
+		if err := s.projectService.setProjectTags(tx, project.ID, content.Tags); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	projectResult, err := s.projectService.loadProjectWithRelationships(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectService.transformProjectToResponseWithSingleProfile(projectResult), nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ProjectTermsInput carries the typed budget and duration fields of stage 2.
+// Nil fields were not sent; the free-text budget and duration are parsed instead.
+type ProjectTermsInput struct {
+	BudgetMin      *int64
+	BudgetMax      *int64
+	BudgetCurrency string
+	IsPaid         *bool
+	DurationDays   *int
+}
+
+func (t ProjectTermsInput) hasBudget() bool {
+	return t.BudgetMin != nil || t.BudgetMax != nil || t.IsPaid != nil
+}
+
+// applyProjectBudget sets the budget of a project from typed input, or parses the free text when
+// no typed budget was sent. An empty description is filled in from the typed budget.
+func applyProjectBudget(project *model.Project, text string, terms ProjectTermsInput) error {
+	project.Budget = strings.TrimSpace(text)
+	project.BudgetMin, project.BudgetMax, project.IsPaid = nil, nil, nil
+	project.BudgetCurrency = helper.DefaultCurrency
+
+	if !terms.hasBudget() {
+		if parsed, ok := helper.ParseBudget(project.Budget); ok {
+			project.BudgetMin, project.BudgetMax = parsed.Min, parsed.Max
+			project.BudgetCurrency = parsed.Currency
+			project.IsPaid = &parsed.IsPaid
+		}
+		return nil
+	}
+
+	if terms.BudgetCurrency != "" {
+		currency := strings.ToUpper(strings.TrimSpace(terms.BudgetCurrency))
+		if !currencyPattern.MatchString(currency) {
+			return errors.New("budget currency must be a three-letter code such as IDR or USD")
+		}
+		project.BudgetCurrency = currency
+	}
+
+	if terms.IsPaid != nil && !*terms.IsPaid {
+		if (terms.BudgetMin != nil && *terms.BudgetMin > 0) || (terms.BudgetMax != nil && *terms.BudgetMax > 0) {
+			return errors.New("unpaid projects cannot have a budget")
+		}
+		project.IsPaid = terms.IsPaid
+	} else {
+		if terms.BudgetMin == nil && terms.BudgetMax == nil {
+			return errors.New("paid projects need a budget_min or budget_max")
+		}
+		if (terms.BudgetMin != nil && *terms.BudgetMin < 0) || (terms.BudgetMax != nil && *terms.BudgetMax < 0) {
+			return errors.New("budget cannot be negative")
+		}
+		if terms.BudgetMin != nil && terms.BudgetMax != nil && *terms.BudgetMin > *terms.BudgetMax {
+			return errors.New("budget_min cannot be more than budget_max")
+		}
+		paid := true
+		project.BudgetMin, project.BudgetMax, project.IsPaid = terms.BudgetMin, terms.BudgetMax, &paid
+	}
+
+	if project.Budget == "" {
+		project.Budget = helper.FormatBudget(project.BudgetMin, project.BudgetMax, project.BudgetCurrency, project.IsPaid)
+	}
+	return nil
+}
+
+// applyProjectDuration derives the duration in days from the start and end dates. A typed
+// duration must agree with them, or sets the end date when only the start is known; without
+// dates the free-text duration is parsed. An empty description is filled in from the days.
+func applyProjectDuration(project *model.Project, text string, durationDays *int) error {
+	project.Duration = strings.TrimSpace(text)
+
+	if !project.StartDate.IsZero() && !project.EndDate.IsZero() && project.EndDate.Before(project.StartDate) {
+		return errors.New("end date cannot be before the start date")
+	}
+	derived := helper.DurationDaysBetween(project.StartDate, project.EndDate)
+
+	switch {
+	case durationDays != nil:
+		if *durationDays <= 0 {
+			return errors.New("duration_days must be positive")
+		}
+		if !project.StartDate.IsZero() && project.EndDate.IsZero() {
+			project.EndDate = project.StartDate.AddDate(0, 0, *durationDays)
+		} else if derived > 0 && (derived-*durationDays > 1 || *durationDays-derived > 1) {
+			return fmt.Errorf("duration_days does not match the start and end dates, which are %d days apart", derived)
+		}
+		project.DurationDays = *durationDays
+	case derived > 0:
+		project.DurationDays = derived
+	default:
+		project.DurationDays, _ = helper.ParseDurationDays(project.Duration)
+	}
+
+	if project.Duration == "" {
+		project.Duration = helper.FormatDurationDays(project.DurationDays)
+	}
+	return nil
+}
+
+// applyProjectHours sets the weekly hours of a project from typed input, or parses the free-text
+// time commitment. An empty description is filled in from the hours.
+func applyProjectHours(project *model.Project, text string, hoursPerWeek *int) error {
+	project.TimeCommitment = strings.TrimSpace(text)
+	project.HoursPerWeek = nil
+
+	if hoursPerWeek != nil {
+		if *hoursPerWeek < 1 || *hoursPerWeek > helper.MaxHoursPerWeek {
+			return fmt.Errorf("hours_per_week must be between 1 and %d", helper.MaxHoursPerWeek)
+		}
+		project.HoursPerWeek = hoursPerWeek
+	} else if hours, ok := helper.ParseHoursPerWeek(project.TimeCommitment); ok {
+		project.HoursPerWeek = &hours
+	}
+
+	if project.TimeCommitment == "" {
+		project.TimeCommitment = formatHoursPerWeek(project.HoursPerWeek)
+	}
+	return nil
+}
+
+// ProjectTermsFilter narrows project listings by budget, pay, duration and weekly hours.
+// Nil fields match everything.
+type ProjectTermsFilter struct {
+	// Budget range the project must overlap, in Currency (IDR by default)
+	BudgetMin *int64
+	BudgetMax *int64
+	Currency  string
+	Paid      *bool
+
+	MinDurationDays *int
+	MaxDurationDays *int
+	// MaxHoursPerWeek leaves out projects asking for more hours; projects without hours are kept
+	MaxHoursPerWeek *int
+}
+
+// Apply adds the filter conditions to a query over projects
+func (f ProjectTermsFilter) Apply(query *gorm.DB) *gorm.DB {
+	if f.Currency != "" || f.BudgetMin != nil || f.BudgetMax != nil {
+		currency := strings.ToUpper(f.Currency)
+		if currency == "" {
+			currency = helper.DefaultCurrency
+		}
+		query = query.Where("budget_currency = ?", currency)
+	}
+	if f.BudgetMin != nil {
+		query = query.Where("COALESCE(budget_max, budget_min) >= ?", *f.BudgetMin)
+	}
+	if f.BudgetMax != nil {
+		query = query.Where("COALESCE(budget_min, budget_max) <= ?", *f.BudgetMax)
+	}
+	if f.Paid != nil {
+		query = query.Where("is_paid = ?", *f.Paid)
+	}
+	if f.MinDurationDays != nil {
+		query = query.Where("duration_days >= ?", *f.MinDurationDays)
+	}
+	if f.MaxDurationDays != nil {
+		query = query.Where("duration_days > 0 AND duration_days <= ?", *f.MaxDurationDays)
+	}
+	if f.MaxHoursPerWeek != nil {
+		query = query.Where("hours_per_week IS NULL OR hours_per_week <= ?", *f.MaxHoursPerWeek)
+	}
+	return query
+}
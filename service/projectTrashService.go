@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// defaultProjectRestoreDays is how long a deleted project can be restored before it is purged
+const defaultProjectRestoreDays = 30
+
+// notDeletedProjectCondition restricts rows with a project_id to projects that are not in the trash
+const notDeletedProjectCondition = "project_id IN (SELECT id FROM projects WHERE deleted_at IS NULL)"
+
+type ProjectTrashService struct {
+	DB *gorm.DB
+}
+
+func NewProjectTrashService(db *gorm.DB) *ProjectTrashService {
+	return &ProjectTrashService{DB: db}
+}
+
+// DeletedProject is a project in the trash together with the time it will be purged
+type DeletedProject struct {
+	model.Project
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// ProjectRestoreWindow returns how long deleted projects are kept, configured through PROJECT_RESTORE_DAYS
+func ProjectRestoreWindow() time.Duration {
+	days := defaultProjectRestoreDays
+	if value := os.Getenv("PROJECT_RESTORE_DAYS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			days = parsed
+		} else {
+			log.Printf("Invalid PROJECT_RESTORE_DAYS %q, using %d days", value, defaultProjectRestoreDays)
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetDeletedProjects lists the user's projects that are in the trash, most recently deleted first
+func (s *ProjectTrashService) GetDeletedProjects(userID uint) ([]DeletedProject, error) {
+	var projects []model.Project
+	if err := s.DB.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("creator_id = ? OR id IN (SELECT project_id FROM project_accesses WHERE user_id = ? AND level = ?)", userID, userID, model.ProjectAccessOwner).
+		Order("deleted_at DESC").
+		Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deleted projects: %v", err)
+	}
+
+	window := ProjectRestoreWindow()
+	deleted := make([]DeletedProject, 0, len(projects))
+	for _, project := range projects {
+		deleted = append(deleted, DeletedProject{
+			Project: project,
+			PurgeAt: project.DeletedAt.Time.Add(window),
+		})
+	}
+	return deleted, nil
+}
+
+// RestoreProject takes a project out of the trash together with everything attached to it
+func (s *ProjectTrashService) RestoreProject(projectID, userID uint) (*model.Project, error) {
+	var project model.Project
+	if err := s.DB.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", projectID).
+		First(&project).Error; err != nil {
+		return nil, errors.New("deleted project not found")
+	}
+
+	allowed, err := HasProjectPermission(s.DB, &project, userID, model.ProjectPermissionDelete)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("only the project owner can restore this project")
+	}
+
+	if time.Since(project.DeletedAt.Time) > ProjectRestoreWindow() {
+		return nil, errors.New("the restore window for this project has passed")
+	}
+
+	if err := s.DB.Unscoped().Model(&project).Update("deleted_at", nil).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore project: %v", err)
+	}
+	project.DeletedAt = gorm.DeletedAt{}
+
+	return &project, nil
+}
+
+// PurgeDeletedProjects permanently deletes every project that has been in the trash longer than the restore window
+func (s *ProjectTrashService) PurgeDeletedProjects() error {
+	var projectIDs []uint
+	if err := s.DB.Unscoped().Model(&model.Project{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", time.Now().Add(-ProjectRestoreWindow())).
+		Pluck("id", &projectIDs).Error; err != nil {
+		return fmt.Errorf("failed to find expired projects: %v", err)
+	}
+
+	var errs []string
+	for _, projectID := range projectIDs {
+		if err := s.purgeProject(projectID); err != nil {
+			errs = append(errs, fmt.Sprintf("project %d: %v", projectID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// purgeProject permanently deletes a project and every record tied to it
+func (s *ProjectTrashService) purgeProject(projectID uint) error {
+	tx := s.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Rows are deleted before the rows they reference
+	if err := tx.Where("project_member_id IN (SELECT id FROM project_members WHERE project_id = ?)", projectID).Delete(&model.ProjectMemberSkill{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project member skills: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectApplication{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project applications: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectMember{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project members: %w", err)
+	}
+
+	if err := tx.Where("project_role_id IN (SELECT id FROM project_roles WHERE project_id = ?)", projectID).Delete(&model.ProjectRoleSkill{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project role skills: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRole{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project roles: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectBenefit{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project benefits: %w", err)
+	}
+
+	if err := tx.Where("milestone_id IN (SELECT id FROM project_milestones WHERE project_id = ?)", projectID).Delete(&model.ProjectMilestoneDependency{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete milestone dependencies: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectRequiredSkill{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project required skills: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectCondition{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project conditions: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectTag{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project tags: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectReminderSetting{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete reminder settings: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectReminderLog{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete reminder logs: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectChange{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project changes: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectStatusHistory{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete status history: %w", err)
+	}
+
+	if err := tx.Where("endpoint_id IN (SELECT id FROM webhook_endpoints WHERE project_id = ?)", projectID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.WebhookEndpoint{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete webhook endpoints: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.Notification{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project notifications: %w", err)
+	}
+
+	if err := tx.Where("task_id IN (SELECT id FROM project_tasks WHERE project_id = ?)", projectID).Delete(&model.ProjectTaskComment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete task comments: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectTask{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project tasks: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectMilestone{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project milestones: %w", err)
+	}
+
+	announcementIDs := "announcement_id IN (SELECT id FROM project_announcements WHERE project_id = ?)"
+	if err := tx.Where(announcementIDs, projectID).Delete(&model.ProjectAnnouncementReaction{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement reactions: %w", err)
+	}
+
+	if err := tx.Where(announcementIDs, projectID).Delete(&model.ProjectAnnouncementComment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement comments: %w", err)
+	}
+
+	var attachmentFiles []string
+	if err := tx.Model(&model.ProjectAnnouncementAttachment{}).Where(announcementIDs, projectID).Pluck("file_path", &attachmentFiles).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to load announcement attachments: %w", err)
+	}
+
+	if err := tx.Where(announcementIDs, projectID).Delete(&model.ProjectAnnouncementAttachment{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete announcement attachments: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectAnnouncement{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project announcements: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectQuestion{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project questions: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectActivity{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project activity: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectView{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project views: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.SavedProject{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete saved projects: %w", err)
+	}
+
+	if err := tx.Where("project_id = ?", projectID).Delete(&model.ProjectAccess{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project access: %w", err)
+	}
+
+	// Templates made from this project outlive it
+	if err := tx.Model(&model.ProjectTemplate{}).Where("source_project_id = ?", projectID).Update("source_project_id", nil).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to detach project templates: %w", err)
+	}
+
+	if err := tx.Unscoped().Delete(&model.Project{}, projectID).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Files are only removed once the rows pointing to them are gone for good
+	for _, filePath := range attachmentFiles {
+		helper.DeleteFile(filePath)
+	}
+
+	return nil
+}
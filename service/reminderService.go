@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// reminderMaxOffsetDays is the furthest ahead a reminder can be scheduled
+const reminderMaxOffsetDays = 60
+
+type ReminderService struct {
+	DB *gorm.DB
+}
+
+func NewReminderService(db *gorm.DB) *ReminderService {
+	return &ReminderService{DB: db}
+}
+
+// ReminderSettingsDTO contains the editable reminder settings of a project
+type ReminderSettingsDTO struct {
+	Enabled             *bool
+	RegistrationOffsets []int
+	StartDateOffsets    []int
+	EndDateOffsets      []int
+	MilestoneOffsets    []int
+}
+
+// reminderTarget is a single dated item a reminder can be sent for
+type reminderTarget struct {
+	kind     string
+	targetID uint
+	name     string
+	date     time.Time
+}
+
+func defaultReminderSetting(projectID uint) *model.ProjectReminderSetting {
+	return &model.ProjectReminderSetting{
+		ProjectID:           projectID,
+		Enabled:             true,
+		RegistrationOffsets: "7,3,1",
+		StartDateOffsets:    "3,1",
+		EndDateOffsets:      "7,1",
+		MilestoneOffsets:    "3,1",
+	}
+}
+
+// ParseReminderOffsets parses a comma-separated list of day offsets
+func ParseReminderOffsets(value string) ([]int, error) {
+	var offsets []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder offset: %s", part)
+		}
+		offsets = append(offsets, days)
+	}
+	return offsets, nil
+}
+
+func formatReminderOffsets(offsets []int) (string, error) {
+	seen := make(map[int]bool)
+	var unique []int
+	for _, days := range offsets {
+		if days < 0 || days > reminderMaxOffsetDays {
+			return "", fmt.Errorf("reminder offsets must be between 0 and %d days", reminderMaxOffsetDays)
+		}
+		if !seen[days] {
+			seen[days] = true
+			unique = append(unique, days)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(unique)))
+
+	parts := make([]string, len(unique))
+	for i, days := range unique {
+		parts[i] = strconv.Itoa(days)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func (s *ReminderService) loadSetting(projectID uint) (*model.ProjectReminderSetting, error) {
+	var setting model.ProjectReminderSetting
+	err := s.DB.Where("project_id = ?", projectID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultReminderSetting(projectID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder settings: %v", err)
+	}
+	return &setting, nil
+}
+
+// GetSettings returns the reminder settings of a project, falling back to the defaults
+func (s *ReminderService) GetSettings(projectID, userID uint) (*model.ProjectReminderSetting, error) {
+	if _, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionEdit); err != nil {
+		return nil, errors.New("project not found or unauthorized")
+	}
+	return s.loadSetting(projectID)
+}
+
+// UpdateSettings changes the reminder offsets of a project
+func (s *ReminderService) UpdateSettings(projectID, userID uint, data ReminderSettingsDTO) (*model.ProjectReminderSetting, error) {
+	setting, err := s.GetSettings(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Enabled != nil {
+		setting.Enabled = *data.Enabled
+	}
+
+	fields := []struct {
+		offsets []int
+		target  *string
+	}{
+		{data.RegistrationOffsets, &setting.RegistrationOffsets},
+		{data.StartDateOffsets, &setting.StartDateOffsets},
+		{data.EndDateOffsets, &setting.EndDateOffsets},
+		{data.MilestoneOffsets, &setting.MilestoneOffsets},
+	}
+	for _, field := range fields {
+		if field.offsets == nil {
+			continue
+		}
+		formatted, err := formatReminderOffsets(field.offsets)
+		if err != nil {
+			return nil, err
+		}
+		*field.target = formatted
+	}
+
+	if err := s.DB.Save(setting).Error; err != nil {
+		return nil, fmt.Errorf("failed to save reminder settings: %v", err)
+	}
+	return setting, nil
+}
+
+// SendDueReminders sends every reminder that falls due today across all active projects.
+// Each reminder is logged so reruns of the job on the same day do not send it again.
+func (s *ReminderService) SendDueReminders() error {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	horizon := today.AddDate(0, 0, reminderMaxOffsetDays+1)
+
+	var projects []model.Project
+	if err := s.DB.Preload("Milestones", orderMilestones).
+		Where("status IN ?", ProjectActiveStatuses).
+		Where("(registration_deadline >= ? AND registration_deadline < ?) OR (start_date >= ? AND start_date < ?) OR (end_date >= ? AND end_date < ?) OR id IN (SELECT project_id FROM project_milestones WHERE due_date >= ? AND due_date < ?)",
+			today, horizon, today, horizon, today, horizon, today, horizon).
+		Find(&projects).Error; err != nil {
+		return fmt.Errorf("failed to find projects with upcoming dates: %v", err)
+	}
+
+	var errs []string
+	for i := range projects {
+		if err := s.sendProjectReminders(&projects[i], today); err != nil {
+			errs = append(errs, fmt.Sprintf("project %d: %v", projects[i].ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (s *ReminderService) sendProjectReminders(project *model.Project, today time.Time) error {
+	setting, err := s.loadSetting(project.ID)
+	if err != nil {
+		return err
+	}
+	if !setting.Enabled {
+		return nil
+	}
+
+	targets := []struct {
+		target  reminderTarget
+		offsets string
+	}{
+		{reminderTarget{kind: model.ReminderKindRegistrationDeadline, date: project.RegistrationDeadline}, setting.RegistrationOffsets},
+		{reminderTarget{kind: model.ReminderKindStartDate, date: project.StartDate}, setting.StartDateOffsets},
+		{reminderTarget{kind: model.ReminderKindEndDate, date: project.EndDate}, setting.EndDateOffsets},
+	}
+	for _, item := range project.Milestones {
+		if item.DueDate == nil || item.Status == helper.TimelineStatusDone {
+			continue
+		}
+		targets = append(targets, struct {
+			target  reminderTarget
+			offsets string
+		}{reminderTarget{kind: model.ReminderKindMilestone, targetID: item.ID, name: item.Name, date: *item.DueDate}, setting.MilestoneOffsets})
+	}
+
+	for _, t := range targets {
+		if t.target.date.IsZero() {
+			continue
+		}
+		offsets, err := ParseReminderOffsets(t.offsets)
+		if err != nil {
+			return err
+		}
+
+		targetDay := time.Date(t.target.date.Year(), t.target.date.Month(), t.target.date.Day(), 0, 0, 0, 0, today.Location())
+		daysLeft := int(targetDay.Sub(today).Hours() / 24)
+		for _, offset := range offsets {
+			if offset != daysLeft {
+				continue
+			}
+			if err := s.sendReminder(project, t.target, targetDay, offset); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reminderRecipients returns the creator and accepted members of a project
+func (s *ReminderService) reminderRecipients(tx *gorm.DB, project *model.Project) ([]uint, error) {
+	var memberIDs []uint
+	if err := tx.Model(&model.ProjectMember{}).
+		Where("project_id = ? AND status = ?", project.ID, "accepted").
+		Pluck("user_id", &memberIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load project members: %v", err)
+	}
+
+	recipients := []uint{project.CreatorID}
+	for _, id := range memberIDs {
+		if id != project.CreatorID {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients, nil
+}
+
+func (s *ReminderService) sendReminder(project *model.Project, target reminderTarget, targetDay time.Time, offset int) error {
+	tx := s.DB.Begin()
+
+	log := &model.ProjectReminderLog{
+		ProjectID:  project.ID,
+		Kind:       target.kind,
+		TargetID:   target.targetID,
+		TargetDate: targetDay,
+		OffsetDays: offset,
+		SentAt:     time.Now(),
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(log)
+	if result.Error != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record reminder: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Already sent
+		tx.Rollback()
+		return nil
+	}
+
+	recipients, err := s.reminderRecipients(tx, project)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	notificationType, title, message := reminderContent(project, target, offset)
+	data := map[string]interface{}{
+		"project_id":    project.ID,
+		"project_title": project.Title,
+		"kind":          target.kind,
+		"date":          target.date,
+		"days_left":     offset,
+	}
+	if target.kind == model.ReminderKindMilestone {
+		data["milestone_id"] = target.targetID
+		data["milestone"] = target.name
+	}
+
+	notificationService := NewNotificationService(tx)
+	for _, userID := range recipients {
+		if _, err := notificationService.CreateNotification(userID, &project.ID, notificationType, title, message, data); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func reminderWhen(offset int) string {
+	switch offset {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", offset)
+	}
+}
+
+func reminderContent(project *model.Project, target reminderTarget, offset int) (string, string, string) {
+	when := reminderWhen(offset)
+	switch target.kind {
+	case model.ReminderKindStartDate:
+		return model.NotificationTypeProjectStarting, "Project Starting Soon",
+			fmt.Sprintf("Project '%s' starts %s", project.Title, when)
+	case model.ReminderKindEndDate:
+		return model.NotificationTypeProjectEnding, "Project Ending Soon",
+			fmt.Sprintf("Project '%s' ends %s", project.Title, when)
+	case model.ReminderKindMilestone:
+		return model.NotificationTypeMilestoneDue, "Milestone Due Soon",
+			fmt.Sprintf("Milestone '%s' of project '%s' is due %s", target.name, project.Title, when)
+	default:
+		return model.NotificationTypeDeadlineApproaching, "Project Deadline Approaching",
+			fmt.Sprintf("The registration deadline for project '%s' is %s", project.Title, when)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/model"
+)
+
+type SavedProjectService struct {
+	DB *gorm.DB
+}
+
+func NewSavedProjectService(db *gorm.DB) *SavedProjectService {
+	return &SavedProjectService{DB: db}
+}
+
+// SaveProject bookmarks a public project for the user. Saving it again does nothing.
+func (s *SavedProjectService) SaveProject(userID, projectID uint) error {
+	var project model.Project
+	if err := s.DB.Where("id = ? AND status IN ?", projectID, ProjectPublicStatuses).First(&project).Error; err != nil {
+		return errors.New("project not found")
+	}
+
+	saved := model.SavedProject{UserID: userID, ProjectID: project.ID}
+	if err := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&saved).Error; err != nil {
+		return fmt.Errorf("failed to save project: %v", err)
+	}
+	return nil
+}
+
+// UnsaveProject removes a project from the user's saved projects
+func (s *SavedProjectService) UnsaveProject(userID, projectID uint) error {
+	result := s.DB.Where("user_id = ? AND project_id = ?", userID, projectID).Delete(&model.SavedProject{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to unsave project: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("project is not saved")
+	}
+	return nil
+}
+
+// GetSavedProjectsQuery lists the user's saved projects, newest first. Projects that were
+// deleted or are no longer public are left out but stay saved, in case they come back.
+func (s *SavedProjectService) GetSavedProjectsQuery(userID uint) *gorm.DB {
+	return s.DB.Model(&model.SavedProject{}).
+		Joins("JOIN projects ON projects.id = saved_projects.project_id AND projects.deleted_at IS NULL").
+		Where("saved_projects.user_id = ? AND projects.status NOT IN ?", userID, []string{model.ProjectStatusDraft, model.ProjectStatusArchived}).
+		Preload("Project").
+		Order("saved_projects.created_at DESC")
+}
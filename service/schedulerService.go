@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+// Job name constants
+const (
+	JobOTPCleanup       = "otp-cleanup"
+	JobProjectReminders = "project-reminders"
+	JobProjectPurge     = "project-purge"
+)
+
+// schedulerLeaderLockKey is the Postgres advisory lock key held by the scheduler leader
+const schedulerLeaderLockKey int64 = 0x53594e4c454144 // "SYNLEAD"
+
+const schedulerTickInterval = 30 * time.Second
+
+// ErrJobRunning is returned when a job is already running somewhere in the cluster
+var ErrJobRunning = errors.New("job is already running")
+
+type jobDefinition struct {
+	name     string
+	spec     string
+	schedule *helper.CronSchedule
+	run      func() error
+}
+
+type SchedulerService struct {
+	DB         *gorm.DB
+	jobs       map[string]*jobDefinition
+	instance   string
+	leaderConn *sql.Conn
+}
+
+func NewSchedulerService(db *gorm.DB) *SchedulerService {
+	hostname, _ := os.Hostname()
+	s := &SchedulerService{
+		DB:       db,
+		jobs:     make(map[string]*jobDefinition),
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+	s.registerDefaultJobs()
+	return s
+}
+
+// JobInfo describes a registered job together with its persisted state
+type JobInfo struct {
+	model.ScheduledJob
+	LastRun *model.JobRun `json:"last_run,omitempty"`
+}
+
+// RegisterJob registers a job under name using a cron expression; it panics on an invalid expression
+func (s *SchedulerService) RegisterJob(name, spec string, run func() error) {
+	schedule, err := helper.ParseCron(spec)
+	if err != nil {
+		panic(fmt.Sprintf("scheduler: job %s: %v", name, err))
+	}
+	s.jobs[name] = &jobDefinition{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		run:      run,
+	}
+}
+
+func (s *SchedulerService) registerDefaultJobs() {
+	s.RegisterJob(JobOTPCleanup, "0 * * * *", func() error {
+		return NewOTPService().CleanupExpiredOTPs()
+	})
+
+	s.RegisterJob(JobProjectReminders, "0 8 * * *", func() error {
+		return NewReminderService(s.DB).SendDueReminders()
+	})
+
+	s.RegisterJob(JobProjectPurge, "30 3 * * *", func() error {
+		return NewProjectTrashService(s.DB).PurgeDeletedProjects()
+	})
+}
+
+// Start syncs job definitions to the database and runs the scheduling loop until ctx is cancelled.
+// Every instance runs the loop, but only the one holding the leader advisory lock dispatches jobs.
+func (s *SchedulerService) Start(ctx context.Context) {
+	if err := s.syncJobs(); err != nil {
+		log.Printf("Scheduler: failed to sync jobs: %v", err)
+	}
+
+	ticker := time.NewTicker(schedulerTickInterval)
+	defer ticker.Stop()
+	defer s.releaseLeadership()
+
+	for {
+		if s.ensureLeadership(ctx) {
+			s.dispatchDueJobs()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncJobs creates a row for each registered job, keeps its schedule in line with the code
+// and removes rows of jobs that are no longer registered
+func (s *SchedulerService) syncJobs() error {
+	now := time.Now()
+	names := make([]string, 0, len(s.jobs))
+	for _, def := range s.jobs {
+		names = append(names, def.name)
+		var job model.ScheduledJob
+		err := s.DB.Where("name = ?", def.name).First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			next := def.schedule.Next(now)
+			job = model.ScheduledJob{
+				Name:      def.name,
+				Schedule:  def.spec,
+				Enabled:   true,
+				NextRunAt: &next,
+			}
+			if err := s.DB.Create(&job).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if job.Schedule != def.spec || job.NextRunAt == nil {
+			next := def.schedule.Next(now)
+			if err := s.DB.Model(&job).Updates(map[string]interface{}{
+				"schedule":    def.spec,
+				"next_run_at": &next,
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.DB.Where("name NOT IN ?", names).Delete(&model.ScheduledJob{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ensureLeadership acquires or re-checks the session-level leader lock on a dedicated connection
+func (s *SchedulerService) ensureLeadership(ctx context.Context) bool {
+	if s.leaderConn != nil {
+		if err := s.leaderConn.PingContext(ctx); err == nil {
+			return true
+		}
+		log.Printf("Scheduler: lost leader connection on %s", s.instance)
+		s.leaderConn.Close()
+		s.leaderConn = nil
+	}
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return false
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", schedulerLeaderLockKey).Scan(&acquired); err != nil || !acquired {
+		conn.Close()
+		return false
+	}
+
+	log.Printf("Scheduler: %s is now the leader", s.instance)
+	s.leaderConn = conn
+	return true
+}
+
+func (s *SchedulerService) releaseLeadership() {
+	if s.leaderConn == nil {
+		return
+	}
+	s.leaderConn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", schedulerLeaderLockKey)
+	s.leaderConn.Close()
+	s.leaderConn = nil
+}
+
+func (s *SchedulerService) dispatchDueJobs() {
+	now := time.Now()
+
+	var due []model.ScheduledJob
+	if err := s.DB.Where("enabled = ? AND next_run_at <= ?", true, now).Find(&due).Error; err != nil {
+		log.Printf("Scheduler: failed to load due jobs: %v", err)
+		return
+	}
+
+	for _, job := range due {
+		def, ok := s.jobs[job.Name]
+		if !ok {
+			continue
+		}
+
+		next := def.schedule.Next(now)
+		if err := s.DB.Model(&job).Update("next_run_at", &next).Error; err != nil {
+			log.Printf("Scheduler: failed to advance %s: %v", job.Name, err)
+			continue
+		}
+
+		go func(def *jobDefinition) {
+			if _, err := s.runJob(def, model.JobTriggerSchedule); err != nil && !errors.Is(err, ErrJobRunning) {
+				log.Printf("Scheduler: job %s failed: %v", def.name, err)
+			}
+		}(def)
+	}
+}
+
+// jobLockKey derives the per-job advisory lock key from the job name
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("job:" + name))
+	return int64(h.Sum64())
+}
+
+// runJob executes a job while holding its advisory lock, so a job never runs twice at once across instances
+func (s *SchedulerService) runJob(def *jobDefinition, trigger string) (*model.JobRun, error) {
+	ctx := context.Background()
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	lockKey := jobLockKey(def.name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&acquired); err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrJobRunning
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+
+	run := &model.JobRun{
+		JobName:   def.name,
+		Trigger:   trigger,
+		Status:    model.JobRunStatusRunning,
+		Instance:  s.instance,
+		StartedAt: time.Now(),
+	}
+	if err := s.DB.Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to record job run: %v", err)
+	}
+
+	jobErr := s.execute(def)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = model.JobRunStatusSuccess
+	if jobErr != nil {
+		run.Status = model.JobRunStatusFailed
+		run.Error = jobErr.Error()
+	}
+
+	if err := s.DB.Save(run).Error; err != nil {
+		log.Printf("Scheduler: failed to save run of %s: %v", def.name, err)
+	}
+	if err := s.DB.Model(&model.ScheduledJob{}).Where("name = ?", def.name).Updates(map[string]interface{}{
+		"last_run_at": run.StartedAt,
+		"last_status": run.Status,
+	}).Error; err != nil {
+		log.Printf("Scheduler: failed to update job %s: %v", def.name, err)
+	}
+
+	return run, jobErr
+}
+
+// execute runs the job function, turning a panic into an error
+func (s *SchedulerService) execute(def *jobDefinition) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return def.run()
+}
+
+// GetJobs lists all registered jobs with their schedule state and most recent run
+func (s *SchedulerService) GetJobs() ([]JobInfo, error) {
+	var jobs []model.ScheduledJob
+	if err := s.DB.Order("name ASC").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %v", err)
+	}
+
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		info := JobInfo{ScheduledJob: job}
+		var lastRun model.JobRun
+		if err := s.DB.Where("job_name = ?", job.Name).Order("started_at DESC").First(&lastRun).Error; err == nil {
+			info.LastRun = &lastRun
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// GetJobRunsQuery returns a query over the run history of a job, for pagination
+func (s *SchedulerService) GetJobRunsQuery(name string) (*gorm.DB, error) {
+	if _, ok := s.jobs[name]; !ok {
+		return nil, errors.New("job not found")
+	}
+	return s.DB.Model(&model.JobRun{}).Where("job_name = ?", name).Order("started_at DESC"), nil
+}
+
+// TriggerJob runs a job immediately on this instance and waits for it to finish.
+// A failing job still returns its run record; the failure is recorded on the run.
+func (s *SchedulerService) TriggerJob(name string) (*model.JobRun, error) {
+	def, ok := s.jobs[name]
+	if !ok {
+		return nil, errors.New("job not found")
+	}
+
+	run, err := s.runJob(def, model.JobTriggerManual)
+	if run == nil {
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+)
+
+// taxonomyKind says where the entries of a kind and their project join rows live
+type taxonomyKind struct {
+	table      string
+	joinTable  string
+	joinColumn string
+}
+
+var taxonomyKinds = map[string]taxonomyKind{
+	model.TaxonomyKindTag:     {table: "tags", joinTable: "project_tags", joinColumn: "tag_id"},
+	model.TaxonomyKindBenefit: {table: "benefits", joinTable: "project_benefits", joinColumn: "benefit_id"},
+}
+
+// usageColumn counts the projects (outside the trash) using each entry
+func (k taxonomyKind) usageColumn() string {
+	return fmt.Sprintf("(SELECT COUNT(*) FROM %[2]s JOIN projects ON projects.id = %[2]s.project_id AND projects.deleted_at IS NULL WHERE %[2]s.%[3]s = %[1]s.id) AS usage_count",
+		k.table, k.joinTable, k.joinColumn)
+}
+
+// TaxonomyEntry is a tag or benefit with the number of projects using it
+type TaxonomyEntry struct {
+	ID         uint                  `json:"id"`
+	Name       string                `json:"name"`
+	UsageCount int64                 `json:"usage_count"`
+	Aliases    []model.TaxonomyAlias `json:"aliases,omitempty" gorm:"-"`
+}
+
+// taxonomyKey is how names are compared: lowercased, with whitespace collapsed
+func taxonomyKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// resolveTaxonomyAlias returns the entry an alias of name points to, or 0 when there is none
+func resolveTaxonomyAlias(tx *gorm.DB, kind, name string) (uint, error) {
+	var alias model.TaxonomyAlias
+	err := tx.Where("kind = ? AND key = ?", kind, taxonomyKey(name)).First(&alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve alias: %v", err)
+	}
+	return alias.TargetID, nil
+}
+
+type TaxonomyService struct {
+	DB *gorm.DB
+}
+
+func NewTaxonomyService(db *gorm.DB) *TaxonomyService {
+	return &TaxonomyService{DB: db}
+}
+
+func getTaxonomyKind(kind string) (taxonomyKind, error) {
+	k, ok := taxonomyKinds[kind]
+	if !ok {
+		return taxonomyKind{}, fmt.Errorf("unknown taxonomy %q; use tags or benefits", kind)
+	}
+	return k, nil
+}
+
+func (s *TaxonomyService) getEntry(tx *gorm.DB, kind string, k taxonomyKind, id uint) (*TaxonomyEntry, error) {
+	var entry TaxonomyEntry
+	err := tx.Table(k.table).Select("id, name, "+k.usageColumn()).Where("id = ?", id).Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%s entry %d not found", strings.TrimSuffix(kind, "s"), id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load entry: %v", err)
+	}
+	if err := tx.Where("kind = ? AND target_id = ?", kind, entry.ID).Order("alias").Find(&entry.Aliases).Error; err != nil {
+		return nil, fmt.Errorf("failed to load aliases: %v", err)
+	}
+	return &entry, nil
+}
+
+// Autocomplete suggests entries whose name or an alias starts with query (or has a word starting
+// with it), the most used first. An empty query returns the most used entries.
+func (s *TaxonomyService) Autocomplete(kind, query string, limit int) ([]TaxonomyEntry, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	db := s.DB.Table(k.table).Select("id, name, " + k.usageColumn())
+	if key := taxonomyKey(query); key != "" {
+		prefix := escapeLike(key) + "%"
+		// Names starting with the query come before names with a later word or an alias matching it
+		db = db.Where("LOWER(name) LIKE ? OR LOWER(name) LIKE ? OR id IN (SELECT target_id FROM taxonomy_aliases WHERE kind = ? AND key LIKE ?)",
+			prefix, "% "+prefix, kind, prefix).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN LOWER(name) LIKE ? THEN 0 ELSE 1 END, usage_count DESC, name", Vars: []interface{}{prefix}}})
+	} else {
+		db = db.Order("usage_count DESC").Order("name")
+	}
+
+	var entries []TaxonomyEntry
+	if err := db.Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to search entries: %v", err)
+	}
+	return entries, nil
+}
+
+// GetEntriesQuery lists the entries of a kind for admins, filtered by name and sorted by name or
+// by usage
+func (s *TaxonomyService) GetEntriesQuery(kind, query, sort string) (*gorm.DB, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	db := s.DB.Table(k.table).Select("id, name, " + k.usageColumn())
+	if key := taxonomyKey(query); key != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+escapeLike(key)+"%")
+	}
+	if sort == "usage" {
+		db = db.Order("usage_count DESC")
+	}
+	return db.Order("name"), nil
+}
+
+// LoadAliases fills in the aliases of listed entries
+func (s *TaxonomyService) LoadAliases(kind string, entries []TaxonomyEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	var aliases []model.TaxonomyAlias
+	if err := s.DB.Where("kind = ? AND target_id IN ?", kind, ids).Order("alias").Find(&aliases).Error; err != nil {
+		return fmt.Errorf("failed to load aliases: %v", err)
+	}
+	byTarget := make(map[uint][]model.TaxonomyAlias)
+	for _, alias := range aliases {
+		byTarget[alias.TargetID] = append(byTarget[alias.TargetID], alias)
+	}
+	for i := range entries {
+		entries[i].Aliases = byTarget[entries[i].ID]
+	}
+	return nil
+}
+
+// FindDuplicates groups entries whose names only differ in case, spacing or punctuation, such as
+// "UI/UX", "UI UX" and "uiux", as candidates for merging
+func (s *TaxonomyService) FindDuplicates(kind string) ([][]TaxonomyEntry, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	const normalized = "LOWER(REGEXP_REPLACE(name, '[^[:alnum:]]+', '', 'g'))"
+
+	var entries []struct {
+		TaxonomyEntry
+		Normalized string
+	}
+	if err := s.DB.Table(k.table).
+		Select("id, name, " + k.usageColumn() + ", " + normalized + " AS normalized").
+		Where(normalized + " IN (SELECT " + normalized + " FROM " + k.table + " GROUP BY 1 HAVING COUNT(*) > 1)").
+		Order("normalized").Order("usage_count DESC").Order("name").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %v", err)
+	}
+
+	groups := [][]TaxonomyEntry{}
+	for i, entry := range entries {
+		if i == 0 || entry.Normalized != entries[i-1].Normalized {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], entry.TaxonomyEntry)
+	}
+	return groups, nil
+}
+
+// checkNameFree makes sure no other entry of the kind is already called name or has it as an alias
+func checkNameFree(tx *gorm.DB, kind string, k taxonomyKind, name string, id uint) error {
+	key := taxonomyKey(name)
+	var count int64
+	if err := tx.Table(k.table).Where("LOWER(name) = ? AND id <> ?", key, id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check name: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("another entry is already called %q; merge it instead", name)
+	}
+	if err := tx.Model(&model.TaxonomyAlias{}).Where("kind = ? AND key = ? AND target_id <> ?", kind, key, id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check name: %v", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%q is already an alias of another entry", name)
+	}
+	return nil
+}
+
+// RenameEntry renames an entry, e.g. to fix a typo. Projects show the new name right away.
+func (s *TaxonomyService) RenameEntry(kind string, id uint, name string) (*TaxonomyEntry, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	tx := s.DB.Begin()
+	if _, err := s.getEntry(tx, kind, k, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := checkNameFree(tx, kind, k, name, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Table(k.table).Where("id = ?", id).Updates(map[string]interface{}{"name": name, "updated_at": gorm.Expr("NOW()")}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to rename entry: %v", err)
+	}
+	// The new name no longer needs an alias of its own
+	if err := tx.Where("kind = ? AND key = ?", kind, taxonomyKey(name)).Delete(&model.TaxonomyAlias{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update aliases: %v", err)
+	}
+	entry, err := s.getEntry(tx, kind, k, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return entry, nil
+}
+
+// AddAlias makes alias resolve to an entry when projects name it
+func (s *TaxonomyService) AddAlias(kind string, id uint, alias string) (*TaxonomyEntry, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	alias = strings.Join(strings.Fields(alias), " ")
+	if alias == "" {
+		return nil, errors.New("alias is required")
+	}
+
+	tx := s.DB.Begin()
+	entry, err := s.getEntry(tx, kind, k, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if taxonomyKey(alias) == taxonomyKey(entry.Name) {
+		tx.Rollback()
+		return nil, errors.New("an alias must differ from the entry name")
+	}
+	if err := checkNameFree(tx, kind, k, alias, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	record := model.TaxonomyAlias{Kind: kind, Key: taxonomyKey(alias), Alias: alias, TargetID: id}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to add alias: %v", err)
+	}
+	if entry, err = s.getEntry(tx, kind, k, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return entry, nil
+}
+
+// RemoveAlias stops an alias from resolving to an entry
+func (s *TaxonomyService) RemoveAlias(kind string, id, aliasID uint) error {
+	if _, err := getTaxonomyKind(kind); err != nil {
+		return err
+	}
+	result := s.DB.Where("id = ? AND kind = ? AND target_id = ?", aliasID, kind, id).Delete(&model.TaxonomyAlias{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove alias: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("alias not found")
+	}
+	return nil
+}
+
+// MergeEntries folds the source entries into the target: projects using a source use the target
+// instead, and the source names and aliases become aliases of the target, so they are not
+// created again.
+func (s *TaxonomyService) MergeEntries(kind string, targetID uint, sourceIDs []uint) (*TaxonomyEntry, error) {
+	k, err := getTaxonomyKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceIDs) == 0 {
+		return nil, errors.New("at least one source entry is required")
+	}
+
+	tx := s.DB.Begin()
+	target, err := s.getEntry(tx, kind, k, targetID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	var sources []TaxonomyEntry
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			tx.Rollback()
+			return nil, errors.New("an entry cannot be merged into itself")
+		}
+		source, err := s.getEntry(tx, kind, k, sourceID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		sources = append(sources, *source)
+	}
+
+	// Projects that already use the target keep a single join row
+	if err := tx.Exec(fmt.Sprintf("INSERT INTO %[1]s (project_id, %[2]s) SELECT DISTINCT project_id, ? FROM %[1]s WHERE %[2]s IN ? ON CONFLICT DO NOTHING",
+		k.joinTable, k.joinColumn), targetID, sourceIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to move projects: %v", err)
+	}
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", k.joinTable, k.joinColumn), sourceIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to move projects: %v", err)
+	}
+
+	if err := tx.Model(&model.TaxonomyAlias{}).Where("kind = ? AND target_id IN ?", kind, sourceIDs).Update("target_id", targetID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to move aliases: %v", err)
+	}
+	targetKey := taxonomyKey(target.Name)
+	for _, source := range sources {
+		if taxonomyKey(source.Name) == targetKey {
+			continue
+		}
+		alias := model.TaxonomyAlias{Kind: kind, Key: taxonomyKey(source.Name), Alias: source.Name, TargetID: targetID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alias).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to add alias: %v", err)
+		}
+	}
+	// The target's own name needs no alias
+	if err := tx.Where("kind = ? AND key = ?", kind, targetKey).Delete(&model.TaxonomyAlias{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update aliases: %v", err)
+	}
+
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", k.table), sourceIDs).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to delete merged entries: %v", err)
+	}
+	if err := tx.Table(k.table).Where("id = ?", targetID).Update("updated_at", gorm.Expr("NOW()")).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to update entry: %v", err)
+	}
+
+	merged, err := s.getEntry(tx, kind, k, targetID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return merged, nil
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+const (
+	webhookTimeout         = 10 * time.Second
+	webhookMaxResponseBody = 2048
+)
+
+type WebhookService struct {
+	DB            *gorm.DB
+	OutboxService *OutboxService
+	client        *http.Client
+}
+
+func NewWebhookService(db *gorm.DB, outboxService *OutboxService) *WebhookService {
+	return &WebhookService{
+		DB:            db,
+		OutboxService: outboxService,
+		client:        helper.NewWebhookHTTPClient(webhookTimeout),
+	}
+}
+
+// WebhookDeliveryPayload is the outbox payload for a single webhook delivery
+type WebhookDeliveryPayload struct {
+	DeliveryID uint `json:"delivery_id"`
+}
+
+// WebhookEnvelope is the JSON body posted to webhook endpoints
+type WebhookEnvelope struct {
+	Event      string                 `json:"event"`
+	ProjectID  uint                   `json:"project_id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// WebhookEndpointDTO contains the editable fields of a webhook endpoint
+type WebhookEndpointDTO struct {
+	URL         *string
+	Events      []string
+	Description *string
+	IsActive    *bool
+}
+
+func (s *WebhookService) authorizeProject(projectID, userID uint) (*model.Project, error) {
+	project, err := AuthorizeProject(s.DB, projectID, userID, model.ProjectPermissionManageWebhooks)
+	if err != nil {
+		return nil, errors.New("project not found or unauthorized")
+	}
+	return project, nil
+}
+
+func (s *WebhookService) getEndpoint(projectID, endpointID uint) (*model.WebhookEndpoint, error) {
+	var endpoint model.WebhookEndpoint
+	if err := s.DB.Where("id = ? AND project_id = ?", endpointID, projectID).First(&endpoint).Error; err != nil {
+		return nil, errors.New("webhook endpoint not found")
+	}
+	return &endpoint, nil
+}
+
+func validateWebhookEvents(events []string) (string, error) {
+	if len(events) == 0 {
+		return "", errors.New("at least one event is required")
+	}
+
+	var cleaned []string
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+		if event == "*" {
+			return "*", nil
+		}
+		valid := false
+		for _, known := range model.WebhookEvents {
+			if event == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", fmt.Errorf("unknown webhook event: %s. Must be one of: %s", event, strings.Join(model.WebhookEvents, ", "))
+		}
+		cleaned = append(cleaned, event)
+	}
+
+	if len(cleaned) == 0 {
+		return "", errors.New("at least one event is required")
+	}
+	return strings.Join(cleaned, ","), nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func subscribesTo(endpoint *model.WebhookEndpoint, event string) bool {
+	if event == model.WebhookEventPing || endpoint.Events == "*" {
+		return true
+	}
+	for _, e := range strings.Split(endpoint.Events, ",") {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateEndpoint registers a webhook endpoint for a project. The signing secret is only returned here.
+func (s *WebhookService) CreateEndpoint(projectID, userID uint, url string, events []string, description string) (*model.WebhookEndpoint, string, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, "", err
+	}
+
+	if err := helper.ValidateWebhookURL(url); err != nil {
+		return nil, "", err
+	}
+
+	eventList, err := validateWebhookEvents(events)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, "", errors.New("failed to generate webhook secret")
+	}
+
+	endpoint := &model.WebhookEndpoint{
+		ProjectID:   projectID,
+		CreatedBy:   userID,
+		URL:         url,
+		Secret:      secret,
+		Events:      eventList,
+		Description: description,
+		IsActive:    true,
+	}
+
+	if err := s.DB.Create(endpoint).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create webhook endpoint: %v", err)
+	}
+
+	return endpoint, secret, nil
+}
+
+// GetEndpoints lists the webhook endpoints of a project
+func (s *WebhookService) GetEndpoints(projectID, userID uint) ([]model.WebhookEndpoint, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	var endpoints []model.WebhookEndpoint
+	if err := s.DB.Where("project_id = ?", projectID).Order("created_at DESC").Find(&endpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoints: %v", err)
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint changes the URL, events, description or active flag of an endpoint
+func (s *WebhookService) UpdateEndpoint(projectID, endpointID, userID uint, data WebhookEndpointDTO) (*model.WebhookEndpoint, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.getEndpoint(projectID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.URL != nil {
+		if err := helper.ValidateWebhookURL(*data.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *data.URL
+	}
+	if data.Events != nil {
+		eventList, err := validateWebhookEvents(data.Events)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Events = eventList
+	}
+	if data.Description != nil {
+		endpoint.Description = *data.Description
+	}
+	if data.IsActive != nil {
+		endpoint.IsActive = *data.IsActive
+	}
+
+	if err := s.DB.Save(endpoint).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook endpoint: %v", err)
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint together with its delivery log
+func (s *WebhookService) DeleteEndpoint(projectID, endpointID, userID uint) error {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return err
+	}
+
+	endpoint, err := s.getEndpoint(projectID, endpointID)
+	if err != nil {
+		return err
+	}
+
+	tx := s.DB.Begin()
+	if err := tx.Where("endpoint_id = ?", endpoint.ID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete webhook deliveries: %v", err)
+	}
+	if err := tx.Delete(endpoint).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to delete webhook endpoint: %v", err)
+	}
+	return tx.Commit().Error
+}
+
+// GetDeliveriesQuery returns a query over the delivery log of an endpoint, for pagination
+func (s *WebhookService) GetDeliveriesQuery(projectID, endpointID, userID uint) (*gorm.DB, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getEndpoint(projectID, endpointID); err != nil {
+		return nil, err
+	}
+
+	return s.DB.Model(&model.WebhookDelivery{}).Where("endpoint_id = ?", endpointID).Order("created_at DESC"), nil
+}
+
+// Dispatch queues event for every active endpoint of the project subscribed to it.
+// It writes through tx so deliveries are only sent if the triggering change commits.
+func (s *WebhookService) Dispatch(tx *gorm.DB, projectID uint, event string, data map[string]interface{}) error {
+	var endpoints []model.WebhookEndpoint
+	if err := tx.Where("project_id = ? AND is_active = ?", projectID, true).Find(&endpoints).Error; err != nil {
+		return fmt.Errorf("failed to load webhook endpoints: %v", err)
+	}
+
+	for i := range endpoints {
+		if !subscribesTo(&endpoints[i], event) {
+			continue
+		}
+		if _, err := s.queueDelivery(tx, &endpoints[i], projectID, event, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) queueDelivery(tx *gorm.DB, endpoint *model.WebhookEndpoint, projectID uint, event string, data map[string]interface{}) (*model.WebhookDelivery, error) {
+	body, err := json.Marshal(WebhookEnvelope{
+		Event:      event,
+		ProjectID:  projectID,
+		OccurredAt: time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	delivery := &model.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		Event:      event,
+		Payload:    string(body),
+		Status:     model.WebhookDeliveryPending,
+	}
+	if err := tx.Create(delivery).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %v", err)
+	}
+
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicWebhookDelivery, WebhookDeliveryPayload{DeliveryID: delivery.ID}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Ping sends a test event to a single endpoint
+func (s *WebhookService) Ping(projectID, endpointID, userID uint) (*model.WebhookDelivery, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.getEndpoint(projectID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.DB.Begin()
+	delivery, err := s.queueDelivery(tx, endpoint, projectID, model.WebhookEventPing, map[string]interface{}{
+		"endpoint_id": endpoint.ID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a fresh delivery of a previously logged payload
+func (s *WebhookService) Redeliver(projectID, endpointID, deliveryID, userID uint) (*model.WebhookDelivery, error) {
+	if _, err := s.authorizeProject(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getEndpoint(projectID, endpointID); err != nil {
+		return nil, err
+	}
+
+	var original model.WebhookDelivery
+	if err := s.DB.Where("id = ? AND endpoint_id = ?", deliveryID, endpointID).First(&original).Error; err != nil {
+		return nil, errors.New("webhook delivery not found")
+	}
+
+	delivery := &model.WebhookDelivery{
+		EndpointID:   original.EndpointID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		Status:       model.WebhookDeliveryPending,
+		RedeliveryOf: &original.ID,
+	}
+
+	tx := s.DB.Begin()
+	if err := tx.Create(delivery).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create webhook delivery: %v", err)
+	}
+	if err := s.OutboxService.Enqueue(tx, model.OutboxTopicWebhookDelivery, WebhookDeliveryPayload{DeliveryID: delivery.ID}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Deliver posts a queued delivery to its endpoint and records the outcome.
+// It is called by the outbox worker, which retries returned errors with backoff.
+func (s *WebhookService) Deliver(deliveryID uint) error {
+	var delivery model.WebhookDelivery
+	if err := s.DB.Preload("Endpoint").First(&delivery, deliveryID).Error; err != nil {
+		return PermanentError(fmt.Errorf("webhook delivery %d not found", deliveryID))
+	}
+
+	if !delivery.Endpoint.IsActive {
+		s.DB.Model(&delivery).Updates(map[string]interface{}{
+			"status": model.WebhookDeliveryFailed,
+			"error":  "endpoint is disabled",
+		})
+		return PermanentError(errors.New("webhook endpoint is disabled"))
+	}
+
+	updates, deliveryErr := s.post(&delivery)
+	updates["attempts"] = delivery.Attempts + 1
+	s.DB.Model(&delivery).Updates(updates)
+	return deliveryErr
+}
+
+// post sends a delivery to its endpoint and returns the columns to record and the error for the outbox.
+// Errors that retrying cannot fix are wrapped with PermanentError.
+func (s *WebhookService) post(delivery *model.WebhookDelivery) (map[string]interface{}, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.Endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return map[string]interface{}{
+			"status": model.WebhookDeliveryFailed,
+			"error":  err.Error(),
+		}, PermanentError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Synergazing-Webhooks/1.0")
+	req.Header.Set("X-Synergazing-Event", delivery.Event)
+	req.Header.Set("X-Synergazing-Delivery", fmt.Sprintf("%d", delivery.ID))
+	req.Header.Set(helper.WebhookSignatureHeader, helper.SignWebhookPayload(delivery.Endpoint.Secret, time.Now().Unix(), body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	updates := map[string]interface{}{
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		updates["status"] = model.WebhookDeliveryFailed
+		updates["error"] = err.Error()
+		// Retrying will not make a blocked address or a redirect acceptable
+		if errors.Is(err, helper.ErrWebhookHostNotAllowed) || errors.Is(err, helper.ErrWebhookRedirect) {
+			return updates, PermanentError(err)
+		}
+		return updates, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	updates["response_status"] = resp.StatusCode
+	updates["response_body"] = string(respBody)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		now := time.Now()
+		updates["status"] = model.WebhookDeliverySuccess
+		updates["error"] = ""
+		updates["delivered_at"] = &now
+		return updates, nil
+	}
+
+	deliveryErr := fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	updates["status"] = model.WebhookDeliveryFailed
+	updates["error"] = deliveryErr.Error()
+
+	// Client errors will not fix themselves, except timeouts and rate limiting
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return updates, PermanentError(deliveryErr)
+	}
+	return updates, deliveryErr
+}
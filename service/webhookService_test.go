@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"synergazing.com/synergazing/helper"
+	"synergazing.com/synergazing/model"
+)
+
+func newTestWebhookService() *WebhookService {
+	return &WebhookService{client: helper.NewWebhookHTTPClient(time.Second)}
+}
+
+func newTestDelivery(url string) *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		ID:       7,
+		Event:    model.WebhookEventPing,
+		Payload:  `{"event":"ping"}`,
+		Endpoint: model.WebhookEndpoint{URL: url, Secret: "whsec_test", IsActive: true},
+	}
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+func TestWebhookPostSignsDelivery(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = helper.VerifyWebhookSignature("whsec_test", r.Header.Get(helper.WebhookSignatureHeader), body, time.Minute)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	updates, err := newTestWebhookService().post(newTestDelivery(server.URL))
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("receiver could not verify signature: %v", verifyErr)
+	}
+	if updates["status"] != model.WebhookDeliverySuccess || updates["response_status"] != http.StatusOK {
+		t.Fatalf("unexpected updates: %v", updates)
+	}
+}
+
+func TestWebhookPostStatusRetries(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusNotFound, true},
+		{http.StatusGone, true},
+		{http.StatusRequestTimeout, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+		{http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		updates, err := newTestWebhookService().post(newTestDelivery(server.URL))
+		server.Close()
+
+		if err == nil {
+			t.Errorf("status %d: expected an error", tt.status)
+			continue
+		}
+		if isPermanent(err) != tt.permanent {
+			t.Errorf("status %d: permanent = %v, want %v", tt.status, isPermanent(err), tt.permanent)
+		}
+		if updates["status"] != model.WebhookDeliveryFailed || updates["response_status"] != tt.status {
+			t.Errorf("status %d: unexpected updates: %v", tt.status, updates)
+		}
+	}
+}
+
+func TestWebhookPostRefusesLoopback(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "")
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := newTestWebhookService().post(newTestDelivery(server.URL))
+	if !errors.Is(err, helper.ErrWebhookHostNotAllowed) || !isPermanent(err) {
+		t.Fatalf("post to loopback = %v, want permanent ErrWebhookHostNotAllowed", err)
+	}
+	if called {
+		t.Fatal("loopback receiver was reached")
+	}
+}
+
+func TestWebhookPostDoesNotFollowRedirects(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCALHOST", "true")
+
+	followed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/target" {
+			followed = true
+			return
+		}
+		http.Redirect(w, r, "/target", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestWebhookService().post(newTestDelivery(server.URL + "/hook"))
+	if !errors.Is(err, helper.ErrWebhookRedirect) || !isPermanent(err) {
+		t.Fatalf("post to redirecting endpoint = %v, want permanent ErrWebhookRedirect", err)
+	}
+	if followed {
+		t.Fatal("redirect was followed")
+	}
+}